@@ -0,0 +1,62 @@
+package trace
+
+import (
+	"context"
+	"time"
+)
+
+// StatusAborted marks a trace whose computation was cut short — typically by
+// context cancellation or a deadline — before it could complete or fail on
+// its own terms.
+const StatusAborted Status = "aborted"
+
+// Abort finishes the trace in the aborted state, recording a terminal
+// cancellation step carrying the reason, the elapsed time, and the last
+// completed step, so interrupted computations still leave an explainable
+// artifact.
+func (tr *Trace) Abort(reason error) {
+	tr.mu.Lock()
+	defer tr.runFinishHooks()
+	defer tr.mu.Unlock()
+	if tr.Status != StatusRecording {
+		return
+	}
+	inputs := map[string]any{
+		"elapsed": time.Since(tr.Started).String(),
+	}
+	if reason != nil {
+		inputs["reason"] = reason.Error()
+		tr.Error = reason.Error()
+	}
+	if n := len(tr.Steps); n > 0 {
+		last := tr.Steps[n-1]
+		inputs["last_step_index"] = last.Index
+		inputs["last_step_operation"] = last.Operation
+	}
+	tr.finalizeLimitLocked()
+	tr.addStepLocked("trace.abort", inputs, nil)
+	tr.sealChainLocked()
+	tr.Status = StatusAborted
+	tr.Finished = time.Now()
+	tr.endRuntimeTaskLocked()
+	tr.releaseCollectorLocked()
+}
+
+// WatchContext aborts the trace automatically if ctx is canceled or its
+// deadline passes while the trace is still recording. The returned stop
+// function releases the watcher and must be called once the computation
+// finishes normally:
+//
+//	stop := tr.WatchContext(ctx)
+//	defer stop()
+func (tr *Trace) WatchContext(ctx context.Context) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			tr.Abort(ctx.Err())
+		case <-done:
+		}
+	}()
+	return func() { close(done) }
+}
@@ -0,0 +1,54 @@
+package trace
+
+import (
+	"fmt"
+	"time"
+)
+
+// Amendment corrects a completed trace without mutating it: the original
+// stays immutable, and the amendment links it — by ID and content hash —
+// to the superseding trace that replaces it, with the reason on record.
+// Chains of amendments form the version history of a computation.
+type Amendment struct {
+	OriginalID      string    `json:"original_id"`
+	OriginalHash    string    `json:"original_hash"`
+	SupersedingID   string    `json:"superseding_id"`
+	SupersedingHash string    `json:"superseding_hash"`
+	Reason          string    `json:"reason"`
+	Time            time.Time `json:"time"`
+}
+
+// NewAmendment records that superseding corrects original. Both traces
+// must be terminal — a still-recording trace has nothing final to amend
+// or to amend with — and must be distinct.
+func NewAmendment(original, superseding *Trace, reason string) (*Amendment, error) {
+	if original.Status == StatusRecording {
+		return nil, fmt.Errorf("trace %s: amend: original still recording", original.ID)
+	}
+	if superseding.Status == StatusRecording {
+		return nil, fmt.Errorf("trace %s: amend: superseding trace %s still recording", original.ID, superseding.ID)
+	}
+	if original.ID == superseding.ID && original.ContentHash() == superseding.ContentHash() {
+		return nil, fmt.Errorf("trace %s: amend: trace cannot supersede itself", original.ID)
+	}
+	return &Amendment{
+		OriginalID:      original.ID,
+		OriginalHash:    original.ContentHash(),
+		SupersedingID:   superseding.ID,
+		SupersedingHash: superseding.ContentHash(),
+		Reason:          reason,
+		Time:            time.Now(),
+	}, nil
+}
+
+// Verify checks the amendment against the two traces it links, detecting
+// an amendment re-pointed at different content after it was recorded.
+func (am *Amendment) Verify(original, superseding *Trace) error {
+	if original.ID != am.OriginalID || original.ContentHash() != am.OriginalHash {
+		return fmt.Errorf("trace %s: amendment does not match original content", am.OriginalID)
+	}
+	if superseding.ID != am.SupersedingID || superseding.ContentHash() != am.SupersedingHash {
+		return fmt.Errorf("trace %s: amendment does not match superseding content", am.OriginalID)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package trace
+
+import "testing"
+
+func TestAmendmentLinksAndVerifies(t *testing.T) {
+	original := New("calc", map[string]any{"rate": 0.2})
+	original.AddStep("apply", nil, map[string]any{"out": 20.0})
+	original.Complete(20.0)
+	superseding := New("calc", map[string]any{"rate": 0.21})
+	superseding.AddStep("apply", nil, map[string]any{"out": 21.0})
+	superseding.Complete(21.0)
+
+	am, err := NewAmendment(original, superseding, "rate table corrected")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if am.OriginalHash != original.ContentHash() || am.SupersedingHash != superseding.ContentHash() {
+		t.Fatalf("amendment hashes do not pin the traces: %+v", am)
+	}
+	if err := am.Verify(original, superseding); err != nil {
+		t.Fatal(err)
+	}
+	// Re-pointing the amendment at different content must fail Verify.
+	other := New("calc", nil)
+	other.Complete(nil)
+	if err := am.Verify(original, other); err == nil {
+		t.Fatal("amendment verified against a different superseding trace")
+	}
+}
+
+func TestAmendmentRejectsRecordingAndSelf(t *testing.T) {
+	recording := New("calc", nil)
+	done := New("calc", nil)
+	done.Complete(nil)
+	if _, err := NewAmendment(recording, done, "r"); err == nil {
+		t.Fatal("amended a still-recording original")
+	}
+	if _, err := NewAmendment(done, recording, "r"); err == nil {
+		t.Fatal("superseded with a still-recording trace")
+	}
+	if _, err := NewAmendment(done, done, "r"); err == nil {
+		t.Fatal("trace superseded itself")
+	}
+}
+
+func TestAnnotatePinsContentHash(t *testing.T) {
+	tr := New("calc", nil)
+	if _, err := Annotate(tr, "reviewer", AnnotationComment, "looks off"); err == nil {
+		t.Fatal("annotated a recording trace")
+	}
+	tr.Complete(nil)
+	an, err := Annotate(tr, "reviewer", AnnotationClassification, "approved")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if an.TraceID != tr.ID || an.ContentHash != tr.ContentHash() {
+		t.Fatalf("annotation %+v does not pin trace %s", an, tr.ID)
+	}
+}
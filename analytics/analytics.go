@@ -0,0 +1,160 @@
+// Package analytics runs user-defined extractors over collections of traces
+// and aggregates the extracted values into metrics and time series, turning
+// trace archives into dashboard feeds.
+package analytics
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Source yields traces to analyze; store.Store satisfies it, and Traces
+// adapts an in-memory slice.
+type Source interface {
+	Each(fn func(tr *trace.Trace) error) error
+}
+
+// Traces adapts a slice of traces into a Source.
+type Traces []*trace.Trace
+
+// Each visits the traces in slice order.
+func (ts Traces) Each(fn func(tr *trace.Trace) error) error {
+	for _, tr := range ts {
+		if err := fn(tr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Extractor pulls one numeric observation out of a trace. Extract returns
+// false when the trace carries no observation for this metric.
+type Extractor struct {
+	Name    string
+	Extract func(tr *trace.Trace) (float64, bool)
+}
+
+// StepCount counts steps with the given operation; with "" it counts all
+// steps.
+func StepCount(operation string) Extractor {
+	name := "step_count"
+	if operation != "" {
+		name = "step_count." + operation
+	}
+	return Extractor{
+		Name: name,
+		Extract: func(tr *trace.Trace) (float64, bool) {
+			n := 0
+			for _, st := range tr.Steps {
+				if operation == "" || st.Operation == operation {
+					n++
+				}
+			}
+			return float64(n), true
+		},
+	}
+}
+
+// ResultValue extracts the final result when it is numeric.
+func ResultValue() Extractor {
+	return Extractor{
+		Name: "result",
+		Extract: func(tr *trace.Trace) (float64, bool) {
+			switch v := tr.Result.(type) {
+			case float64:
+				return v, true
+			case float32:
+				return float64(v), true
+			case int:
+				return float64(v), true
+			case int64:
+				return float64(v), true
+			}
+			return 0, false
+		},
+	}
+}
+
+// TotalDuration extracts the wall-clock duration of the trace in seconds.
+func TotalDuration() Extractor {
+	return Extractor{
+		Name: "duration_seconds",
+		Extract: func(tr *trace.Trace) (float64, bool) {
+			if tr.Finished.IsZero() {
+				return 0, false
+			}
+			return tr.Finished.Sub(tr.Started).Seconds(), true
+		},
+	}
+}
+
+// Metric is the aggregate of one extractor over a source.
+type Metric struct {
+	Name  string  `json:"name"`
+	Count int     `json:"count"`
+	Sum   float64 `json:"sum"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Mean  float64 `json:"mean"`
+}
+
+// Point is one observation placed on the time axis at the trace's start.
+type Point struct {
+	Time    time.Time `json:"time"`
+	TraceID string    `json:"trace_id"`
+	Value   float64   `json:"value"`
+}
+
+// Report holds the aggregates and per-trace time series of one Run.
+type Report struct {
+	Metrics map[string]Metric  `json:"metrics"`
+	Series  map[string][]Point `json:"series"`
+}
+
+// Run applies every extractor to every trace from the source, aggregating
+// metrics and collecting time series ordered by trace start time.
+func Run(src Source, extractors ...Extractor) (*Report, error) {
+	report := &Report{
+		Metrics: map[string]Metric{},
+		Series:  map[string][]Point{},
+	}
+	for _, e := range extractors {
+		report.Metrics[e.Name] = Metric{Name: e.Name, Min: math.Inf(1), Max: math.Inf(-1)}
+	}
+	err := src.Each(func(tr *trace.Trace) error {
+		for _, e := range extractors {
+			v, ok := e.Extract(tr)
+			if !ok {
+				continue
+			}
+			m := report.Metrics[e.Name]
+			m.Count++
+			m.Sum += v
+			m.Min = math.Min(m.Min, v)
+			m.Max = math.Max(m.Max, v)
+			report.Metrics[e.Name] = m
+			report.Series[e.Name] = append(report.Series[e.Name], Point{
+				Time: tr.Started, TraceID: tr.ID, Value: v,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for name, m := range report.Metrics {
+		if m.Count == 0 {
+			m.Min, m.Max = 0, 0
+		} else {
+			m.Mean = m.Sum / float64(m.Count)
+		}
+		report.Metrics[name] = m
+	}
+	for _, points := range report.Series {
+		sort.Slice(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+	}
+	return report, nil
+}
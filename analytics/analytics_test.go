@@ -0,0 +1,67 @@
+package analytics
+
+import (
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+func sampleTraces() Traces {
+	var ts Traces
+	for _, result := range []float64{10, 20, 30} {
+		tr := trace.New("calc", map[string]any{"r": result})
+		tr.AddStep("multiply", nil, nil)
+		tr.AddStep("round", nil, nil)
+		tr.Complete(result)
+		ts = append(ts, tr)
+	}
+	aborted := trace.New("calc", map[string]any{"r": -1.0})
+	aborted.Complete("not a number")
+	return append(ts, aborted)
+}
+
+func TestRunAggregatesMetrics(t *testing.T) {
+	report, err := Run(sampleTraces(), ResultValue(), StepCount("multiply"), StepCount(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := report.Metrics["result"]
+	// The non-numeric result carries no observation.
+	if m.Count != 3 || m.Sum != 60 || m.Min != 10 || m.Max != 30 || m.Mean != 20 {
+		t.Fatalf("result metric %+v", m)
+	}
+	if c := report.Metrics["step_count.multiply"]; c.Count != 4 || c.Sum != 3 {
+		t.Fatalf("multiply count %+v", c)
+	}
+	if c := report.Metrics["step_count"]; c.Max != 2 {
+		t.Fatalf("total count %+v", c)
+	}
+	series := report.Series["result"]
+	if len(series) != 3 || series[0].Value != 10 || series[0].TraceID == "" {
+		t.Fatalf("series %+v", series)
+	}
+}
+
+func TestRunWithNoObservations(t *testing.T) {
+	report, err := Run(Traces{}, ResultValue(), TotalDuration())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := report.Metrics["result"]
+	if m.Count != 0 || m.Min != 0 || m.Max != 0 {
+		t.Fatalf("empty metric %+v", m)
+	}
+}
+
+func TestTotalDurationSkipsRecordingTraces(t *testing.T) {
+	recording := trace.New("t", nil)
+	done := trace.New("t", nil)
+	done.Complete(nil)
+	report, err := Run(Traces{recording, done}, TotalDuration())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := report.Metrics["duration_seconds"]; m.Count != 1 {
+		t.Fatalf("duration metric %+v", m)
+	}
+}
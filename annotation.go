@@ -0,0 +1,45 @@
+package trace
+
+import (
+	"fmt"
+	"time"
+)
+
+// Annotation kinds.
+const (
+	AnnotationComment        = "comment"
+	AnnotationClassification = "classification"
+	AnnotationLink           = "link"
+)
+
+// Annotation is reviewer commentary attached to a completed trace —
+// comments, classifications, links to tickets or papers. Annotations live
+// beside the trace, never inside it: the signed content hash covers the
+// computation only, so annotating cannot invalidate signatures. The
+// annotation pins the content hash it was written against, so commentary
+// on a superseded version is identifiable as such.
+type Annotation struct {
+	TraceID     string    `json:"trace_id"`
+	ContentHash string    `json:"content_hash"`
+	Author      string    `json:"author"`
+	Kind        string    `json:"kind"`
+	Text        string    `json:"text"`
+	Time        time.Time `json:"time"`
+}
+
+// Annotate writes an annotation against the trace's current content. Only
+// terminal traces can be annotated; commentary on a computation still in
+// flight would pin a content hash about to change.
+func Annotate(tr *Trace, author, kind, text string) (*Annotation, error) {
+	if tr.Status == StatusRecording {
+		return nil, fmt.Errorf("trace %s: annotate: trace still recording", tr.ID)
+	}
+	return &Annotation{
+		TraceID:     tr.ID,
+		ContentHash: tr.ContentHash(),
+		Author:      author,
+		Kind:        kind,
+		Text:        text,
+		Time:        time.Now(),
+	}, nil
+}
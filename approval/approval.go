@@ -0,0 +1,127 @@
+// Package approval records review decisions over completed traces — the
+// last mile of the audit process. Reviewers sign approve/reject decisions
+// against the trace content hash, and a configurable policy aggregates
+// the decisions into a final status.
+package approval
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"time"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Verdict is one reviewer's call.
+type Verdict string
+
+const (
+	Approve Verdict = "approve"
+	Reject  Verdict = "reject"
+)
+
+// Decision is a signed approve/reject decision. The signature covers the
+// trace content hash, the verdict, and the reviewer, so a decision cannot
+// be replayed against different content or flipped after signing.
+type Decision struct {
+	TraceID     string    `json:"trace_id"`
+	ContentHash string    `json:"content_hash"`
+	Reviewer    string    `json:"reviewer"`
+	Verdict     Verdict   `json:"verdict"`
+	Comment     string    `json:"comment,omitempty"`
+	Time        time.Time `json:"time"`
+	Signature   []byte    `json:"signature"`
+}
+
+// Decide signs a verdict on the trace's current content with the
+// reviewer's Ed25519 key. Only terminal traces can be decided on.
+func Decide(tr *trace.Trace, reviewer string, verdict Verdict, comment string, key ed25519.PrivateKey) (*Decision, error) {
+	if tr.Status == trace.StatusRecording {
+		return nil, fmt.Errorf("approval: trace %s still recording", tr.ID)
+	}
+	d := &Decision{
+		TraceID:     tr.ID,
+		ContentHash: tr.ContentHash(),
+		Reviewer:    reviewer,
+		Verdict:     verdict,
+		Comment:     comment,
+		Time:        time.Now(),
+	}
+	d.Signature = ed25519.Sign(key, d.signedContent())
+	return d, nil
+}
+
+// Verify checks the decision against the trace's current content and the
+// reviewer's public key.
+func (d *Decision) Verify(tr *trace.Trace, pub ed25519.PublicKey) error {
+	if hash := tr.ContentHash(); d.ContentHash != hash {
+		return fmt.Errorf("approval: content hash mismatch: decided on %s, trace is %s", d.ContentHash, hash)
+	}
+	if !ed25519.Verify(pub, d.signedContent(), d.Signature) {
+		return fmt.Errorf("approval: decision by %q does not verify", d.Reviewer)
+	}
+	return nil
+}
+
+func (d *Decision) signedContent() []byte {
+	return []byte(fmt.Sprintf("%s|%s|%s|%s", d.ContentHash, d.Reviewer, d.Verdict, d.Comment))
+}
+
+// Status is the aggregate outcome of review.
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusRejected Status = "rejected"
+)
+
+// Policy configures how decisions aggregate.
+type Policy struct {
+	// RequiredApprovals is how many distinct reviewers must approve; the
+	// zero value means one.
+	RequiredApprovals int
+	// AnyRejectBlocks, when set, makes a single rejection final regardless
+	// of approvals.
+	AnyRejectBlocks bool
+}
+
+// ApprovalStatus aggregates the decisions that match the trace's current
+// content under the policy. Decisions pinned to other content — earlier
+// versions of the trace — are ignored; duplicate decisions by one
+// reviewer count once, the latest winning.
+func ApprovalStatus(tr *trace.Trace, decisions []*Decision, p Policy) Status {
+	required := p.RequiredApprovals
+	if required < 1 {
+		required = 1
+	}
+	hash := tr.ContentHash()
+	latest := map[string]*Decision{}
+	for _, d := range decisions {
+		if d.ContentHash != hash {
+			continue
+		}
+		if prev, ok := latest[d.Reviewer]; !ok || d.Time.After(prev.Time) {
+			latest[d.Reviewer] = d
+		}
+	}
+	approvals, rejections := 0, 0
+	for _, d := range latest {
+		switch d.Verdict {
+		case Approve:
+			approvals++
+		case Reject:
+			rejections++
+		}
+	}
+	if p.AnyRejectBlocks && rejections > 0 {
+		return StatusRejected
+	}
+	if approvals >= required {
+		return StatusApproved
+	}
+	if rejections > 0 && approvals == 0 {
+		return StatusRejected
+	}
+	return StatusPending
+}
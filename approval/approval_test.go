@@ -0,0 +1,117 @@
+package approval
+
+import (
+	"crypto/ed25519"
+	"testing"
+	"time"
+
+	"github.com/euclidtrace/trace"
+)
+
+func reviewer(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pub, priv
+}
+
+func reviewed() *trace.Trace {
+	tr := trace.New("quarterly", map[string]any{"x": 1})
+	tr.AddStep("double", map[string]any{"x": 1}, map[string]any{"y": 2})
+	tr.Complete(2)
+	return tr
+}
+
+func TestDecideAndVerify(t *testing.T) {
+	pub, priv := reviewer(t)
+	tr := reviewed()
+	d, err := Decide(tr, "lee", Approve, "figures check out", priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.TraceID != tr.ID || d.ContentHash != tr.ContentHash() {
+		t.Fatalf("decision %+v", d)
+	}
+	if err := d.Verify(tr, pub); err != nil {
+		t.Fatal(err)
+	}
+	// A flipped verdict breaks the signature.
+	d.Verdict = Reject
+	if err := d.Verify(tr, pub); err == nil {
+		t.Fatal("verified a tampered decision")
+	}
+}
+
+func TestDecideRefusesRecordingTrace(t *testing.T) {
+	_, priv := reviewer(t)
+	if _, err := Decide(trace.New("open", nil), "lee", Approve, "", priv); err == nil {
+		t.Fatal("decided on a trace still recording")
+	}
+}
+
+func TestVerifyRejectsOtherContent(t *testing.T) {
+	pub, priv := reviewer(t)
+	tr := reviewed()
+	d, err := Decide(tr, "lee", Approve, "", priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := trace.New("quarterly", map[string]any{"x": 2})
+	other.Complete(4)
+	if err := d.Verify(other, pub); err == nil {
+		t.Fatal("decision replayed against different content")
+	}
+}
+
+func TestApprovalStatus(t *testing.T) {
+	_, priv := reviewer(t)
+	tr := reviewed()
+	decide := func(who string, v Verdict) *Decision {
+		d, err := Decide(tr, who, v, "", priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	if s := ApprovalStatus(tr, nil, Policy{}); s != StatusPending {
+		t.Fatalf("no decisions: %s", s)
+	}
+	one := decide("lee", Approve)
+	if s := ApprovalStatus(tr, []*Decision{one}, Policy{}); s != StatusApproved {
+		t.Fatalf("single approval: %s", s)
+	}
+	if s := ApprovalStatus(tr, []*Decision{one}, Policy{RequiredApprovals: 2}); s != StatusPending {
+		t.Fatalf("one of two approvals: %s", s)
+	}
+
+	// A lone rejection is final; mixed decisions stay pending unless the
+	// policy makes any rejection blocking.
+	nay := decide("kim", Reject)
+	if s := ApprovalStatus(tr, []*Decision{nay}, Policy{}); s != StatusRejected {
+		t.Fatalf("single rejection: %s", s)
+	}
+	mixed := []*Decision{one, nay}
+	if s := ApprovalStatus(tr, mixed, Policy{}); s != StatusApproved {
+		t.Fatalf("mixed decisions: %s", s)
+	}
+	if s := ApprovalStatus(tr, mixed, Policy{AnyRejectBlocks: true}); s != StatusRejected {
+		t.Fatalf("mixed with blocking rejects: %s", s)
+	}
+
+	// One reviewer counts once, their latest decision winning.
+	recant := decide("kim", Approve)
+	recant.Time = nay.Time.Add(time.Minute)
+	if s := ApprovalStatus(tr, []*Decision{nay, recant}, Policy{RequiredApprovals: 2, AnyRejectBlocks: true}); s != StatusPending {
+		t.Fatalf("recanted rejection: %s", s)
+	}
+
+	// Decisions pinned to an earlier version are ignored.
+	stale := decide("lee", Approve)
+	stale.ContentHash = "earlier-version"
+	if s := ApprovalStatus(tr, []*Decision{stale}, Policy{}); s != StatusPending {
+		t.Fatalf("stale decision counted: %s", s)
+	}
+}
@@ -0,0 +1,183 @@
+// Package arrow flattens traces into Apache Arrow record batches — one row
+// per step — serialized in the Arrow JSON integration format, which the
+// standard Arrow toolchain converts losslessly to IPC files or Parquet. Data
+// teams can then query trace archives with the engines they already run;
+// this package carries no Arrow dependency itself.
+package arrow
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Source yields traces to export; store.Store satisfies it.
+type Source interface {
+	Each(fn func(tr *trace.Trace) error) error
+}
+
+// schema is the fixed step-row schema: identifiers and operation as utf8,
+// index as int32, timestamps and durations as int64 nanoseconds, and the
+// input/output maps as JSON-encoded utf8 columns.
+var schema = map[string]any{
+	"fields": []map[string]any{
+		utf8Field("trace_id"),
+		intField("step_index", 32),
+		utf8Field("operation"),
+		utf8Field("description"),
+		intField("time_ns", 64),
+		intField("duration_ns", 64),
+		utf8Field("error"),
+		utf8Field("inputs_json"),
+		utf8Field("outputs_json"),
+	},
+}
+
+func utf8Field(name string) map[string]any {
+	return map[string]any{
+		"name":     name,
+		"type":     map[string]any{"name": "utf8"},
+		"nullable": true,
+		"children": []any{},
+	}
+}
+
+func intField(name string, bits int) map[string]any {
+	return map[string]any{
+		"name":     name,
+		"type":     map[string]any{"name": "int", "bitWidth": bits, "isSigned": true},
+		"nullable": false,
+		"children": []any{},
+	}
+}
+
+// Export writes every trace from the source as one record batch per trace.
+func Export(w io.Writer, src Source) error {
+	var batches []map[string]any
+	err := src.Each(func(tr *trace.Trace) error {
+		batch, err := traceBatch(tr)
+		if err != nil {
+			return err
+		}
+		batches = append(batches, batch)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return write(w, batches)
+}
+
+// ExportTrace writes a single trace as one record batch.
+func ExportTrace(w io.Writer, tr *trace.Trace) error {
+	batch, err := traceBatch(tr)
+	if err != nil {
+		return err
+	}
+	return write(w, []map[string]any{batch})
+}
+
+func write(w io.Writer, batches []map[string]any) error {
+	if batches == nil {
+		batches = []map[string]any{}
+	}
+	enc := json.NewEncoder(w)
+	return enc.Encode(map[string]any{
+		"schema":  schema,
+		"batches": batches,
+	})
+}
+
+func traceBatch(tr *trace.Trace) (map[string]any, error) {
+	n := len(tr.Steps)
+	cols := []*column{
+		newUTF8("trace_id"),
+		newInt("step_index", false),
+		newUTF8("operation"),
+		newUTF8("description"),
+		newInt("time_ns", true),
+		newInt("duration_ns", true),
+		newUTF8("error"),
+		newUTF8("inputs_json"),
+		newUTF8("outputs_json"),
+	}
+	for _, st := range tr.Steps {
+		inputs, err := json.Marshal(st.Inputs)
+		if err != nil {
+			return nil, fmt.Errorf("arrow: step %d inputs: %w", st.Index, err)
+		}
+		outputs, err := json.Marshal(st.Outputs)
+		if err != nil {
+			return nil, fmt.Errorf("arrow: step %d outputs: %w", st.Index, err)
+		}
+		cols[0].pushString(tr.ID)
+		cols[1].pushInt(int64(st.Index))
+		cols[2].pushString(st.Operation)
+		cols[3].pushString(st.Description)
+		cols[4].pushInt(st.Time.UnixNano())
+		cols[5].pushInt(int64(st.Duration))
+		cols[6].pushString(st.Err)
+		cols[7].pushString(string(inputs))
+		cols[8].pushString(string(outputs))
+	}
+	out := make([]map[string]any, len(cols))
+	for i, c := range cols {
+		out[i] = c.render()
+	}
+	return map[string]any{"count": n, "columns": out}, nil
+}
+
+// column accumulates one Arrow column in integration-format layout.
+type column struct {
+	name    string
+	utf8    bool
+	wide    bool // int64 values render as strings, per the format
+	valid   []int
+	offsets []int
+	data    []any
+}
+
+func newUTF8(name string) *column {
+	return &column{name: name, utf8: true, offsets: []int{0}}
+}
+
+func newInt(name string, wide bool) *column {
+	return &column{name: name, wide: wide}
+}
+
+func (c *column) pushString(s string) {
+	if s == "" {
+		c.valid = append(c.valid, 0)
+	} else {
+		c.valid = append(c.valid, 1)
+	}
+	c.offsets = append(c.offsets, c.offsets[len(c.offsets)-1]+len(s))
+	c.data = append(c.data, s)
+}
+
+func (c *column) pushInt(v int64) {
+	c.valid = append(c.valid, 1)
+	if c.wide {
+		c.data = append(c.data, fmt.Sprintf("%d", v))
+	} else {
+		c.data = append(c.data, v)
+	}
+}
+
+func (c *column) render() map[string]any {
+	out := map[string]any{
+		"name":     c.name,
+		"count":    len(c.valid),
+		"VALIDITY": c.valid,
+		"DATA":     c.data,
+	}
+	if c.data == nil {
+		out["DATA"] = []any{}
+	}
+	if c.utf8 {
+		out["OFFSET"] = c.offsets
+	}
+	return out
+}
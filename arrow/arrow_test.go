@@ -0,0 +1,107 @@
+package arrow
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+type traces []*trace.Trace
+
+func (ts traces) Each(fn func(tr *trace.Trace) error) error {
+	for _, tr := range ts {
+		if err := fn(tr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func stepped(name string) *trace.Trace {
+	tr := trace.New(name, map[string]any{"x": 1})
+	tr.AddStep("double", map[string]any{"x": 1}, map[string]any{"y": 2},
+		trace.WithDescription("double the input"))
+	tr.AddStep("round", map[string]any{"y": 2}, map[string]any{"z": 2})
+	tr.Complete(2)
+	return tr
+}
+
+func TestExportTrace(t *testing.T) {
+	tr := stepped("exported")
+	var buf bytes.Buffer
+	if err := ExportTrace(&buf, tr); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Schema struct {
+			Fields []struct {
+				Name string `json:"name"`
+			} `json:"fields"`
+		} `json:"schema"`
+		Batches []struct {
+			Count   int `json:"count"`
+			Columns []struct {
+				Name string `json:"name"`
+				Data []any  `json:"DATA"`
+			} `json:"columns"`
+		} `json:"batches"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Schema.Fields) != 9 || doc.Schema.Fields[0].Name != "trace_id" {
+		t.Fatalf("schema %+v", doc.Schema)
+	}
+	if len(doc.Batches) != 1 || doc.Batches[0].Count != 2 {
+		t.Fatalf("batches %+v", doc.Batches)
+	}
+	byName := map[string][]any{}
+	for _, c := range doc.Batches[0].Columns {
+		byName[c.Name] = c.Data
+	}
+	if ops := byName["operation"]; len(ops) != 2 || ops[0] != "double" || ops[1] != "round" {
+		t.Fatalf("operations %v", ops)
+	}
+	if ids := byName["trace_id"]; ids[0] != tr.ID {
+		t.Fatalf("trace ids %v", ids)
+	}
+	var inputs map[string]any
+	if err := json.Unmarshal([]byte(byName["inputs_json"][0].(string)), &inputs); err != nil {
+		t.Fatal(err)
+	}
+	if inputs["x"] != 1.0 {
+		t.Fatalf("inputs %v", inputs)
+	}
+}
+
+func TestExportSource(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Export(&buf, traces{stepped("a"), stepped("b")}); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Batches []json.RawMessage `json:"batches"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Batches) != 2 {
+		t.Fatalf("batches %d", len(doc.Batches))
+	}
+}
+
+func TestExportEmptySource(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Export(&buf, traces{}); err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if batches, ok := doc["batches"].([]any); !ok || len(batches) != 0 {
+		t.Fatalf("batches %v", doc["batches"])
+	}
+}
@@ -0,0 +1,31 @@
+package trace
+
+import "fmt"
+
+// Assert records a named assertion as an "assert" step: domain rules checked
+// during the computation become explicit, checkable trace content. Failed
+// assertions do not stop the trace; they are recorded with an error and
+// surface through FailedAssertions and compliance rules.
+func (tr *Trace) Assert(name string, passed bool, detail string) *Step {
+	opts := []StepOption{WithDescription(detail)}
+	if !passed {
+		opts = append(opts, WithStepError(fmt.Errorf("assertion %s failed", name)))
+	}
+	return tr.AddStep("assert",
+		map[string]any{"name": name},
+		map[string]any{"passed": passed},
+		opts...)
+}
+
+// FailedAssertions returns the assertion steps that did not pass.
+func (tr *Trace) FailedAssertions() []*Step {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	var failed []*Step
+	for _, st := range tr.Steps {
+		if st.Operation == "assert" && st.Outputs["passed"] == false {
+			failed = append(failed, st)
+		}
+	}
+	return failed
+}
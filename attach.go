@@ -0,0 +1,22 @@
+package trace
+
+// Attach stores a named binary attachment (a source document, a payload too
+// large for a step value) alongside the trace. Attachments are not part of
+// the canonical JSON form or the content hash; bundles carry them as indexed
+// files with their own checksums.
+func (tr *Trace) Attach(name string, data []byte) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if tr.Attachments == nil {
+		tr.Attachments = map[string][]byte{}
+	}
+	tr.Attachments[name] = data
+}
+
+// Attachment returns the named attachment, if present.
+func (tr *Trace) Attachment(name string) ([]byte, bool) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	data, ok := tr.Attachments[name]
+	return data, ok
+}
@@ -0,0 +1,38 @@
+package trace
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+var buildMetaOnce = sync.OnceValue(readBuildMetadata)
+
+// BuildInfoMetadata returns the build provenance recorded on every trace:
+// the main module version and, when the binary was built inside a version
+// control checkout, the VCS revision, commit time, and dirty flag. The result
+// is empty for binaries compiled without module build info.
+func BuildInfoMetadata() map[string]string {
+	return buildMetaOnce()
+}
+
+func readBuildMetadata() map[string]string {
+	meta := map[string]string{}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return meta
+	}
+	if info.Main.Version != "" && info.Main.Version != "(devel)" {
+		meta["build.module_version"] = info.Main.Version
+	}
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			meta["build.vcs_revision"] = s.Value
+		case "vcs.time":
+			meta["build.vcs_time"] = s.Value
+		case "vcs.modified":
+			meta["build.vcs_dirty"] = s.Value
+		}
+	}
+	return meta
+}
@@ -0,0 +1,228 @@
+// Package bundle packs a trace, its attachments, signatures, and manifest
+// into a single .tracebundle file — a zip container with a checksummed index
+// — so a complete, verifiable computation record can be handed to an auditor
+// as one artifact.
+package bundle
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Ext is the conventional file extension for trace bundles.
+const Ext = ".tracebundle"
+
+// Bundle is the in-memory form of a .tracebundle container.
+type Bundle struct {
+	Trace       *trace.Trace
+	Manifest    trace.Manifest
+	Attachments map[string][]byte
+	Signatures  []trace.Signature
+	// Kit carries offline-verification material when the bundle was
+	// built for third parties; see ExportVerifiable.
+	Kit *VerifyKit
+}
+
+// indexEntry describes one file in the container, with its checksum, so
+// readers can detect corruption or tampering before parsing anything.
+type indexEntry struct {
+	Path   string `json:"path"`
+	Size   int    `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// New assembles a bundle from a trace, carrying over its attachments and any
+// detached signatures.
+func New(tr *trace.Trace, sigs ...trace.Signature) *Bundle {
+	b := &Bundle{
+		Trace:       tr,
+		Manifest:    tr.Manifest(),
+		Attachments: map[string][]byte{},
+		Signatures:  sigs,
+	}
+	for name, data := range tr.Attachments {
+		b.Attachments[name] = data
+	}
+	return b
+}
+
+// Write serializes the bundle as a zip container: trace.json, manifest.json,
+// signatures.json, attachments/<name>, and an index.json listing every entry
+// with its SHA-256.
+func Write(w io.Writer, b *Bundle) error {
+	traceJSON, err := b.Trace.ToJSON()
+	if err != nil {
+		return fmt.Errorf("bundle: serialize trace: %w", err)
+	}
+	manifestJSON, err := b.Manifest.ToJSON()
+	if err != nil {
+		return fmt.Errorf("bundle: serialize manifest: %w", err)
+	}
+	sigsJSON, err := json.MarshalIndent(b.Signatures, "", "  ")
+	if err != nil {
+		return fmt.Errorf("bundle: serialize signatures: %w", err)
+	}
+
+	files := map[string][]byte{
+		"trace.json":      traceJSON,
+		"manifest.json":   manifestJSON,
+		"signatures.json": sigsJSON,
+	}
+	if b.Kit != nil {
+		kitJSON, err := json.MarshalIndent(b.Kit, "", "  ")
+		if err != nil {
+			return fmt.Errorf("bundle: serialize verify kit: %w", err)
+		}
+		files["verify.json"] = kitJSON
+		files["VERIFY.md"] = []byte(verifyInstructions(b))
+	}
+	for name, data := range b.Attachments {
+		files["attachments/"+name] = data
+	}
+
+	var index []indexEntry
+	for _, path := range sortedPaths(files) {
+		sum := sha256.Sum256(files[path])
+		index = append(index, indexEntry{
+			Path:   path,
+			Size:   len(files[path]),
+			SHA256: hex.EncodeToString(sum[:]),
+		})
+	}
+	indexJSON, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	if err := writeEntry(zw, "index.json", indexJSON); err != nil {
+		return err
+	}
+	for _, path := range sortedPaths(files) {
+		if err := writeEntry(zw, path, files[path]); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// WriteFile writes the bundle to a file.
+func WriteFile(path string, b *Bundle) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := Write(f, b); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read parses a bundle, verifying every entry against the index checksums.
+func Read(r io.ReaderAt, size int64) (*Bundle, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("bundle: open container: %w", err)
+	}
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		files[f.Name] = data
+	}
+
+	indexJSON, ok := files["index.json"]
+	if !ok {
+		return nil, fmt.Errorf("bundle: missing index.json")
+	}
+	var index []indexEntry
+	if err := json.Unmarshal(indexJSON, &index); err != nil {
+		return nil, fmt.Errorf("bundle: parse index: %w", err)
+	}
+	for _, e := range index {
+		data, ok := files[e.Path]
+		if !ok {
+			return nil, fmt.Errorf("bundle: indexed entry %q missing", e.Path)
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != e.SHA256 {
+			return nil, fmt.Errorf("bundle: checksum mismatch for %q", e.Path)
+		}
+	}
+
+	tr, err := trace.FromJSON(files["trace.json"])
+	if err != nil {
+		return nil, fmt.Errorf("bundle: parse trace: %w", err)
+	}
+	b := &Bundle{Trace: tr, Attachments: map[string][]byte{}}
+	if err := json.Unmarshal(files["manifest.json"], &b.Manifest); err != nil {
+		return nil, fmt.Errorf("bundle: parse manifest: %w", err)
+	}
+	if sigs, ok := files["signatures.json"]; ok {
+		if err := json.Unmarshal(sigs, &b.Signatures); err != nil {
+			return nil, fmt.Errorf("bundle: parse signatures: %w", err)
+		}
+	}
+	if kit, ok := files["verify.json"]; ok {
+		b.Kit = &VerifyKit{}
+		if err := json.Unmarshal(kit, b.Kit); err != nil {
+			return nil, fmt.Errorf("bundle: parse verify kit: %w", err)
+		}
+	}
+	for path, data := range files {
+		if len(path) > len("attachments/") && path[:len("attachments/")] == "attachments/" {
+			name := path[len("attachments/"):]
+			b.Attachments[name] = data
+			tr.Attach(name, data)
+		}
+	}
+	return b, nil
+}
+
+// ReadFile reads a bundle from a file.
+func ReadFile(path string) (*Bundle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return Read(f, fi.Size())
+}
+
+func writeEntry(zw *zip.Writer, path string, data []byte) error {
+	w, err := zw.Create(path)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func sortedPaths(files map[string][]byte) []string {
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
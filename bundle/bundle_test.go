@@ -0,0 +1,190 @@
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ed25519"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+func signedTrace(t *testing.T) (*trace.Trace, trace.Signature, ed25519.PublicKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := trace.New("bundled", map[string]any{"x": 1})
+	tr.AddStep("double", map[string]any{"x": 1}, map[string]any{"y": 2})
+	tr.Attach("workings.txt", []byte("x doubled"))
+	tr.Complete(2)
+	return tr, trace.Sign(tr, "auditor-1", priv), pub
+}
+
+func TestWriteReadRoundTrip(t *testing.T) {
+	tr, sig, _ := signedTrace(t)
+	path := filepath.Join(t.TempDir(), "calc"+Ext)
+	if err := WriteFile(path, New(tr, sig)); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Trace.ContentHash() != tr.ContentHash() {
+		t.Fatal("round trip changed the trace content")
+	}
+	if b.Manifest.ContentHash != tr.ContentHash() {
+		t.Fatalf("manifest hash %s", b.Manifest.ContentHash)
+	}
+	if len(b.Signatures) != 1 || b.Signatures[0].KeyID != "auditor-1" {
+		t.Fatalf("signatures %+v", b.Signatures)
+	}
+	if string(b.Attachments["workings.txt"]) != "x doubled" {
+		t.Fatalf("attachments %v", b.Attachments)
+	}
+	if err := b.Trace.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadDetectsCorruption(t *testing.T) {
+	tr, _, _ := signedTrace(t)
+	var buf bytes.Buffer
+	if err := Write(&buf, New(tr)); err != nil {
+		t.Fatal(err)
+	}
+	// Rebuild the container with a modified attachment but the original
+	// index.json: a valid zip whose contents no longer match their
+	// recorded checksums.
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var forged bytes.Buffer
+	zw := zip.NewWriter(&forged)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.Name == "attachments/workings.txt" {
+			body = []byte("x tripled")
+		}
+		w, err := zw.Create(f.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	_, err = Read(bytes.NewReader(forged.Bytes()), int64(forged.Len()))
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("read a corrupted bundle: %v", err)
+	}
+}
+
+func TestVerifyOffline(t *testing.T) {
+	tr, sig, pub := signedTrace(t)
+	var kit VerifyKit
+	kit.AddKey("auditor-1", pub)
+	b := ExportVerifiable(tr, kit, sig)
+	if err := VerifyOffline(b, true); err != nil {
+		t.Fatal(err)
+	}
+
+	// The bundle survives serialization with its kit intact.
+	var buf bytes.Buffer
+	if err := Write(&buf, b); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyOffline(got, true); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(rawEntry(t, buf.Bytes(), "VERIFY.md")), tr.ContentHash()) {
+		t.Fatal("VERIFY.md does not name the trace's content hash")
+	}
+}
+
+func TestVerifyOfflineStrictness(t *testing.T) {
+	tr, sig, _ := signedTrace(t)
+	b := ExportVerifiable(tr, VerifyKit{}, sig)
+	// Without the key, strict mode refuses; lax mode still checks that
+	// the signature binds to this content.
+	if err := VerifyOffline(b, true); err == nil {
+		t.Fatal("strict verification passed without the public key")
+	}
+	if err := VerifyOffline(b, false); err != nil {
+		t.Fatal(err)
+	}
+	sig.ContentHash = "other-content"
+	rebound := ExportVerifiable(tr, VerifyKit{}, sig)
+	if err := VerifyOffline(rebound, false); err == nil {
+		t.Fatal("accepted a signature bound to different content")
+	}
+}
+
+func TestVerifyOfflineChecksCatalog(t *testing.T) {
+	catalog := trace.NewOpCatalog()
+	if err := catalog.Register("double", trace.OpSpec{Version: "1.2.0"}); err != nil {
+		t.Fatal(err)
+	}
+	tr := trace.New("cataloged", map[string]any{"x": 1}, trace.WithOpCatalog(catalog))
+	tr.AddStep("double", map[string]any{"x": 1}, map[string]any{"y": 2})
+	tr.Complete(2)
+
+	var kit VerifyKit
+	kit.SetCatalog(catalog)
+	if err := VerifyOffline(ExportVerifiable(tr, kit), true); err != nil {
+		t.Fatal(err)
+	}
+	// A versioned step with no embedded catalog cannot be verified
+	// strictly.
+	if err := VerifyOffline(ExportVerifiable(tr, VerifyKit{}), true); err == nil {
+		t.Fatal("strict verification passed without the catalog")
+	}
+}
+
+// rawEntry extracts one named entry from serialized bundle bytes; Read
+// does not expose auxiliary files like VERIFY.md.
+func rawEntry(t *testing.T, data []byte, name string) []byte {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rc.Close()
+		body, err := io.ReadAll(rc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return body
+	}
+	t.Fatalf("no %s entry in the bundle", name)
+	return nil
+}
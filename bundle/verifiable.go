@@ -0,0 +1,153 @@
+package bundle
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/euclidtrace/trace"
+)
+
+// VerifyKit is the offline-verification material packed alongside a
+// trace for third parties: the public keys behind every signature and
+// the operation catalog versions the trace was recorded against. With
+// the kit embedded, an auditor needs nothing but the bundle file and a
+// verifier — no network, no access to our infrastructure.
+type VerifyKit struct {
+	// PublicKeys maps key IDs to hex-encoded ed25519 public keys.
+	PublicKeys map[string]string `json:"public_keys,omitempty"`
+	// Catalog carries the versioned operation specs in force when the
+	// trace was recorded; see trace.OpCatalog.
+	Catalog map[string]trace.OpSpec `json:"catalog,omitempty"`
+}
+
+// AddKey registers a public key under its key ID.
+func (k *VerifyKit) AddKey(keyID string, pub ed25519.PublicKey) {
+	if k.PublicKeys == nil {
+		k.PublicKeys = map[string]string{}
+	}
+	k.PublicKeys[keyID] = hex.EncodeToString(pub)
+}
+
+// SetCatalog embeds the catalog's operation versions.
+func (k *VerifyKit) SetCatalog(c *trace.OpCatalog) {
+	k.Catalog = c.Operations()
+}
+
+// ExportVerifiable assembles a self-contained bundle for third-party
+// auditors: the trace, its attachments and signatures, the kit's public
+// keys and catalog versions, and a VERIFY.md with step-by-step offline
+// verification instructions.
+func ExportVerifiable(tr *trace.Trace, kit VerifyKit, sigs ...trace.Signature) *Bundle {
+	b := New(tr, sigs...)
+	b.Kit = &kit
+	return b
+}
+
+// VerifyOffline verifies everything the bundle can prove by itself: the
+// trace's hash chain, each detached signature against the embedded
+// public keys, each step author signature, and the recorded operation
+// versions against the embedded catalog. In strict mode a signature
+// whose key is absent from the kit is an error; otherwise it is only
+// checked to bind to the right content hash.
+func VerifyOffline(b *Bundle, strict bool) error {
+	if err := b.Trace.Verify(); err != nil {
+		return err
+	}
+	hash := b.Trace.ContentHash()
+	for _, sig := range b.Signatures {
+		if sig.ContentHash != hash {
+			return fmt.Errorf("bundle: signature by key %q binds to %s, trace is %s", sig.KeyID, sig.ContentHash, hash)
+		}
+		pub, ok := kitKey(b.Kit, sig.KeyID)
+		if !ok {
+			if strict {
+				return fmt.Errorf("bundle: no public key for signature by key %q", sig.KeyID)
+			}
+			continue
+		}
+		if err := sig.Verify(b.Trace, pub); err != nil {
+			return err
+		}
+	}
+	for _, st := range b.Trace.Steps {
+		if st.Author == nil || len(st.AuthorSig) == 0 {
+			continue
+		}
+		pub, ok := kitKey(b.Kit, st.Author.KeyID)
+		if !ok {
+			if strict {
+				return fmt.Errorf("bundle: no public key for step %d author %q", st.Index, st.Author.KeyID)
+			}
+			continue
+		}
+		if err := trace.VerifyStepAuthor(st, pub); err != nil {
+			return fmt.Errorf("bundle: step %d: %w", st.Index, err)
+		}
+	}
+	if b.Kit != nil && len(b.Kit.Catalog) > 0 {
+		catalog := trace.NewOpCatalog()
+		for _, op := range sortedOps(b.Kit.Catalog) {
+			if err := catalog.Register(op, b.Kit.Catalog[op]); err != nil {
+				return err
+			}
+		}
+		b.Trace.SetOpCatalog(catalog)
+		if err := b.Trace.Verify(); err != nil {
+			return err
+		}
+	} else if strict {
+		for _, st := range b.Trace.Steps {
+			if st.OpVersion != "" {
+				return fmt.Errorf("bundle: step %d records operation version %s but the bundle carries no catalog", st.Index, st.OpVersion)
+			}
+		}
+	}
+	return nil
+}
+
+// verifyInstructions renders the VERIFY.md embedded in a verifiable
+// bundle, naming the artifact's identity so the instructions cannot be
+// reattached to a different trace unnoticed.
+func verifyInstructions(b *Bundle) string {
+	var sb strings.Builder
+	sb.WriteString("# Verifying this bundle offline\n\n")
+	fmt.Fprintf(&sb, "Trace `%s` (%s), content hash `%s`.\n\n", b.Trace.ID, b.Trace.Name, b.Trace.ContentHash())
+	sb.WriteString("With the tracectl tool (no network access required):\n\n")
+	sb.WriteString("    tracectl verify -offline <this file>\n\n")
+	sb.WriteString("This checks, in order: every entry against index.json checksums,\n")
+	sb.WriteString("the step hash chain of trace.json, each signature in\n")
+	sb.WriteString("signatures.json and each step author signature against the public\n")
+	sb.WriteString("keys in verify.json, and the recorded operation versions against\n")
+	sb.WriteString("the catalog in verify.json.\n\n")
+	sb.WriteString("Without tracectl, any implementation of the trace format\n")
+	sb.WriteString("specification (spec/SPEC.md in the euclidtrace/trace repository)\n")
+	sb.WriteString("can perform the same checks from the files in this archive alone.\n")
+	return sb.String()
+}
+
+func kitKey(k *VerifyKit, keyID string) (ed25519.PublicKey, bool) {
+	if k == nil {
+		return nil, false
+	}
+	encoded, ok := k.PublicKeys[keyID]
+	if !ok {
+		return nil, false
+	}
+	raw, err := hex.DecodeString(encoded)
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, false
+	}
+	return ed25519.PublicKey(raw), true
+}
+
+func sortedOps(m map[string]trace.OpSpec) []string {
+	ops := make([]string, 0, len(m))
+	for op := range m {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+	return ops
+}
@@ -0,0 +1,35 @@
+package trace
+
+// CacheOrigin identifies the provenance of a cached value: the trace, and
+// the step within it, that originally computed the value.
+type CacheOrigin struct {
+	TraceID   string `json:"trace_id"`
+	StepIndex int    `json:"step_index"`
+}
+
+// RecordCacheHit records a "cache.lookup" step for a value served from a
+// memoization cache. The key is recorded by hash, and origin ties the cached
+// value back to the trace that computed it, so results served from cache
+// remain explainable end to end.
+func RecordCacheHit(tr *Trace, key any, value any, origin CacheOrigin) *Step {
+	outputs := map[string]any{
+		"hit":   true,
+		"value": value,
+	}
+	if origin.TraceID != "" {
+		outputs["origin_trace"] = origin.TraceID
+		outputs["origin_step"] = origin.StepIndex
+	}
+	return tr.AddStep("cache.lookup", map[string]any{"key_hash": HashValue(key)}, outputs,
+		WithDescription("cache hit"))
+}
+
+// RecordCacheMiss records a "cache.lookup" step for a key the cache did not
+// hold; the computation that follows should record how the value was then
+// derived.
+func RecordCacheMiss(tr *Trace, key any) *Step {
+	return tr.AddStep("cache.lookup",
+		map[string]any{"key_hash": HashValue(key)},
+		map[string]any{"hit": false},
+		WithDescription("cache miss"))
+}
@@ -0,0 +1,171 @@
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Canonical value encoding
+//
+// Trace IDs and hashes are computed over a typed, canonical byte encoding
+// of values rather than Go's %v formatting, so the encoding is stable
+// across Go versions, implementable in other languages, and unambiguous
+// about value types. The encoding of a value is:
+//
+//	null          z
+//	bool          b:0 or b:1
+//	number        n:<text>
+//	string        s:<byte length>:<UTF-8 bytes>
+//	list          l[<encoding of each element>]
+//	map           m{<encoding of each key><encoding of its value>}
+//
+// Map entries are ordered by key bytes; keys are encoded as strings.
+// Number text is the shortest decimal that round-trips the IEEE-754
+// double, in the exact form encoding/json produces ('f' notation, or 'e'
+// with a trimmed exponent when the magnitude is below 1e-6 or at least
+// 1e21); integers use plain decimal, which coincides with the double form
+// for every integer a double represents exactly. NaN encodes as n:nan,
+// infinities as n:inf and n:-inf.
+//
+// Values of other types are converted through their JSON form and encoded
+// as the generic value that form decodes to. This makes hashing agree
+// with serialization: a trace hashed before ToJSON and after FromJSON
+// hashes identically, because both sides see the same JSON-shaped value.
+// A value with no JSON form falls back to o:<byte length>:<%v text>,
+// which is not portable and exists only so hashing never fails.
+
+// CanonicalBytes returns the canonical encoding of a value — the exact
+// bytes HashValue hashes. It exists for conformance checking and for
+// implementations in other languages to compare against; see spec/.
+func CanonicalBytes(v any) []byte {
+	var b strings.Builder
+	canonicalEncode(&b, v)
+	return []byte(b.String())
+}
+
+// canonicalEncode writes the canonical encoding of v to w.
+func canonicalEncode(w io.Writer, v any) {
+	switch x := v.(type) {
+	case nil:
+		io.WriteString(w, "z")
+	case bool:
+		if x {
+			io.WriteString(w, "b:1")
+		} else {
+			io.WriteString(w, "b:0")
+		}
+	case string:
+		fmt.Fprintf(w, "s:%d:%s", len(x), x)
+	case float64:
+		io.WriteString(w, "n:"+canonicalNumber(x))
+	case float32:
+		io.WriteString(w, "n:"+canonicalNumber(float64(x)))
+	case int:
+		io.WriteString(w, "n:"+strconv.FormatInt(int64(x), 10))
+	case int8:
+		io.WriteString(w, "n:"+strconv.FormatInt(int64(x), 10))
+	case int16:
+		io.WriteString(w, "n:"+strconv.FormatInt(int64(x), 10))
+	case int32:
+		io.WriteString(w, "n:"+strconv.FormatInt(int64(x), 10))
+	case int64:
+		io.WriteString(w, "n:"+strconv.FormatInt(x, 10))
+	case uint:
+		io.WriteString(w, "n:"+strconv.FormatUint(uint64(x), 10))
+	case uint8:
+		io.WriteString(w, "n:"+strconv.FormatUint(uint64(x), 10))
+	case uint16:
+		io.WriteString(w, "n:"+strconv.FormatUint(uint64(x), 10))
+	case uint32:
+		io.WriteString(w, "n:"+strconv.FormatUint(uint64(x), 10))
+	case uint64:
+		io.WriteString(w, "n:"+strconv.FormatUint(x, 10))
+	case json.Number:
+		io.WriteString(w, "n:"+canonicalNumberText(string(x)))
+	case *LazyValue:
+		canonicalEncode(w, x.Value())
+	case []any:
+		io.WriteString(w, "l[")
+		for _, e := range x {
+			canonicalEncode(w, e)
+		}
+		io.WriteString(w, "]")
+	case map[string]any:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		io.WriteString(w, "m{")
+		for _, k := range keys {
+			fmt.Fprintf(w, "s:%d:%s", len(k), k)
+			canonicalEncode(w, x[k])
+		}
+		io.WriteString(w, "}")
+	default:
+		canonicalThroughJSON(w, v)
+	}
+}
+
+// canonicalThroughJSON encodes a value of an arbitrary Go type as the
+// generic value its JSON form decodes to, so hashing agrees with what a
+// serialized trace carries.
+func canonicalThroughJSON(w io.Writer, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		text := fmt.Sprintf("%v", v)
+		fmt.Fprintf(w, "o:%d:%s", len(text), text)
+		return
+	}
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.UseNumber()
+	var generic any
+	if err := dec.Decode(&generic); err != nil {
+		fmt.Fprintf(w, "o:%d:%s", len(data), data)
+		return
+	}
+	canonicalEncode(w, generic)
+}
+
+// canonicalNumber formats an IEEE-754 double exactly as encoding/json
+// does, so numbers hash the same before and after a JSON round trip.
+func canonicalNumber(f float64) string {
+	if math.IsNaN(f) {
+		return "nan"
+	}
+	if math.IsInf(f, 1) {
+		return "inf"
+	}
+	if math.IsInf(f, -1) {
+		return "-inf"
+	}
+	abs := math.Abs(f)
+	format := byte('f')
+	if abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	s := strconv.FormatFloat(f, format, -1, 64)
+	if format == 'e' {
+		// Trim a leading zero in the exponent, as encoding/json does.
+		if n := len(s); n >= 4 && s[n-4] == 'e' && s[n-3] == '-' && s[n-2] == '0' {
+			s = s[:n-2] + s[n-1:]
+		}
+	}
+	return s
+}
+
+// canonicalNumberText canonicalizes a JSON number literal by parsing it
+// as a double; literals beyond double precision hash by their rounded
+// value, the same value every JSON consumer sees.
+func canonicalNumberText(text string) string {
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return text
+	}
+	return canonicalNumber(f)
+}
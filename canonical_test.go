@@ -0,0 +1,83 @@
+package trace
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCanonicalBytesEncoding(t *testing.T) {
+	cases := []struct {
+		v    any
+		want string
+	}{
+		{nil, "z"},
+		{true, "b:1"},
+		{false, "b:0"},
+		{1, "n:1"},
+		{1.0, "n:1"},
+		{1.5, "n:1.5"},
+		{-0.25, "n:-0.25"},
+		{1e21, "n:1e+21"},
+		{1e-7, "n:1e-7"},
+		{math.NaN(), "n:nan"},
+		{math.Inf(1), "n:inf"},
+		{"hi", "s:2:hi"},
+		{"héllo", "s:6:héllo"}, // length counts UTF-8 bytes
+		{[]any{1, "a"}, "l[n:1s:1:a]"},
+		{map[string]any{"b": 2, "a": 1}, "m{s:1:an:1s:1:bn:2}"},
+	}
+	for _, c := range cases {
+		if got := string(CanonicalBytes(c.v)); got != c.want {
+			t.Errorf("CanonicalBytes(%v) = %q, want %q", c.v, got, c.want)
+		}
+	}
+}
+
+func TestHashValueNumericSpellings(t *testing.T) {
+	if HashValue(1) != HashValue(1.0) {
+		t.Fatal("integer and float spellings of 1 hash differently")
+	}
+	if HashValue(int64(7)) != HashValue(float64(7)) {
+		t.Fatal("int64 and float64 spellings of 7 hash differently")
+	}
+	if HashValue(1) == HashValue("1") {
+		t.Fatal("number 1 and string \"1\" hash the same")
+	}
+}
+
+func TestHashValueMapOrderIndependent(t *testing.T) {
+	a := map[string]any{"x": 1, "y": 2, "z": 3}
+	b := map[string]any{"z": 3, "y": 2, "x": 1}
+	if HashValue(a) != HashValue(b) {
+		t.Fatal("map insertion order changed the hash")
+	}
+}
+
+func TestHashValueAgreesAcrossJSONRoundTrip(t *testing.T) {
+	// Structs and timestamps hash through their JSON form, so the hash
+	// recorded in memory must match what a reader recomputes from the
+	// serialized trace.
+	type payload struct {
+		Amount float64   `json:"amount"`
+		Label  string    `json:"label"`
+		At     time.Time `json:"at"`
+	}
+	v := payload{Amount: 12.5, Label: "fee", At: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	tr := New("t", map[string]any{"p": v})
+	tr.Complete(nil)
+	data, err := tr.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := FromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if HashValue(got.Inputs["p"]) != HashValue(v) {
+		t.Fatal("struct input hashes differently after JSON round trip")
+	}
+	if got.ID != tr.ID {
+		t.Fatal("trace ID changed across round trip")
+	}
+}
@@ -0,0 +1,82 @@
+package trace
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// CaptureStruct walks v — a struct or pointer to one — and records every
+// exported field as a named output value of a single "capture.struct" step,
+// using dotted paths for nested structs ("result.totals.net"). Computations
+// that assemble result structs get their whole shape into the trace without
+// per-field AddStep calls.
+//
+// Fields may rename or exclude themselves with a `trace` tag:
+//
+//	type Totals struct {
+//	    Net      float64 `trace:"net_amount"`
+//	    scratch  int                 // unexported: skipped
+//	    Debug    string  `trace:"-"` // excluded
+//	}
+func CaptureStruct(tr *Trace, name string, v any) *Step {
+	outputs := map[string]any{}
+	captureValue(outputs, name, reflect.ValueOf(v))
+	return tr.AddStep("capture.struct", map[string]any{"type": fmt.Sprintf("%T", v)}, outputs,
+		WithDescription("captured struct "+name))
+}
+
+func captureValue(outputs map[string]any, path string, rv reflect.Value) {
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			outputs[path] = nil
+			return
+		}
+		captureValue(outputs, path, rv.Elem())
+	case reflect.Struct:
+		rt := rv.Type()
+		for i := 0; i < rt.NumField(); i++ {
+			field := rt.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldName := field.Name
+			if tag, ok := field.Tag.Lookup("trace"); ok {
+				if tag == "-" {
+					continue
+				}
+				if tag != "" {
+					fieldName = tag
+				}
+			}
+			fieldPath := fieldName
+			if path != "" {
+				fieldPath = path + "." + fieldName
+			}
+			if field.Anonymous && dereferenced(rv.Field(i)).Kind() == reflect.Struct {
+				// Embedded structs flatten into the parent path.
+				captureValue(outputs, path, rv.Field(i))
+				continue
+			}
+			captureValue(outputs, fieldPath, rv.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			captureValue(outputs, fmt.Sprintf("%s[%d]", path, i), rv.Index(i))
+		}
+	case reflect.Invalid:
+		outputs[path] = nil
+	default:
+		outputs[path] = rv.Interface()
+	}
+}
+
+func dereferenced(rv reflect.Value) reflect.Value {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return rv
+		}
+		rv = rv.Elem()
+	}
+	return rv
+}
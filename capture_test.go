@@ -0,0 +1,76 @@
+package trace
+
+import "testing"
+
+func TestCaptureStructFlattensExportedFields(t *testing.T) {
+	type Totals struct {
+		Net   float64 `trace:"net_amount"`
+		Gross float64
+		Debug string `trace:"-"`
+	}
+	type Result struct {
+		Totals Totals
+		Items  []int
+		note   string
+	}
+	tr := New("t", nil)
+	st := CaptureStruct(tr, "result", &Result{
+		Totals: Totals{Net: 80, Gross: 100, Debug: "x"},
+		Items:  []int{1, 2},
+		note:   "hidden",
+	})
+	want := map[string]any{
+		"result.Totals.net_amount": 80.0,
+		"result.Totals.Gross":      100.0,
+		"result.Items[0]":          1,
+		"result.Items[1]":          2,
+	}
+	if len(st.Outputs) != len(want) {
+		t.Fatalf("captured %#v", st.Outputs)
+	}
+	for k, v := range want {
+		if st.Outputs[k] != v {
+			t.Fatalf("output %q = %v, want %v", k, st.Outputs[k], v)
+		}
+	}
+}
+
+func TestCaptureStructNilPointer(t *testing.T) {
+	type S struct{ P *int }
+	tr := New("t", nil)
+	st := CaptureStruct(tr, "s", S{})
+	if v, ok := st.Outputs["s.P"]; !ok || v != nil {
+		t.Fatalf("nil pointer captured as %v", st.Outputs)
+	}
+}
+
+func TestRecordCacheHitAndMiss(t *testing.T) {
+	tr := New("t", nil)
+	miss := RecordCacheMiss(tr, "key-1")
+	if miss.Outputs["hit"] != false || miss.Inputs["key_hash"] != HashValue("key-1") {
+		t.Fatalf("miss step %+v", miss)
+	}
+	hit := RecordCacheHit(tr, "key-1", 42, CacheOrigin{TraceID: "abc", StepIndex: 3})
+	if hit.Outputs["hit"] != true || hit.Outputs["value"] != 42 {
+		t.Fatalf("hit step %+v", hit.Outputs)
+	}
+	if hit.Outputs["origin_trace"] != "abc" || hit.Outputs["origin_step"] != 3 {
+		t.Fatalf("origin not recorded: %+v", hit.Outputs)
+	}
+}
+
+func TestRecordExternalCallHashesPayloads(t *testing.T) {
+	tr := New("t", nil)
+	req, resp := []byte(`{"q":1}`), []byte(`{"a":2}`)
+	st := RecordExternalCall(tr, "pricing", "/v1/quote", req, resp, "200", 0, nil, AttachPayloads())
+	if st.Inputs["request_hash"] != HashValue(req) || st.Outputs["response_hash"] != HashValue(resp) {
+		t.Fatalf("payload hashes missing: %+v %+v", st.Inputs, st.Outputs)
+	}
+	if string(tr.Attachments["external/0-request"]) != string(req) {
+		t.Fatalf("request payload not attached: %v", tr.Attachments)
+	}
+	tr.Complete(nil)
+	if err := tr.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
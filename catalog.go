@@ -0,0 +1,134 @@
+package trace
+
+import "fmt"
+
+// OpSpec describes one cataloged operation: the semantic version of its
+// formula, what arguments it takes and produces, and what it means. When
+// a formula changes, its version changes — and old traces recorded under
+// the old version say so.
+type OpSpec struct {
+	Version     string   `json:"version"`
+	Description string   `json:"description,omitempty"`
+	Inputs      []string `json:"inputs,omitempty"`
+	Outputs     []string `json:"outputs,omitempty"`
+}
+
+// OpCatalog registers operations with versioned semantics. A trace
+// recording against a catalog stamps each step with the operation version
+// in force, validates the argument schema as steps are recorded, and
+// Verify flags steps whose recorded version no longer matches the
+// catalog.
+type OpCatalog struct {
+	ops map[string]OpSpec
+}
+
+// NewOpCatalog returns an empty catalog.
+func NewOpCatalog() *OpCatalog {
+	return &OpCatalog{ops: map[string]OpSpec{}}
+}
+
+// Register adds an operation; registering a name twice is rejected —
+// changed semantics belong under a new version, registered once.
+func (c *OpCatalog) Register(operation string, spec OpSpec) error {
+	if spec.Version == "" {
+		return fmt.Errorf("trace: catalog: operation %q needs a version", operation)
+	}
+	if prev, ok := c.ops[operation]; ok {
+		return fmt.Errorf("trace: catalog: operation %q already registered at version %s", operation, prev.Version)
+	}
+	c.ops[operation] = spec
+	return nil
+}
+
+// Operations returns a copy of every registered operation, for export
+// alongside traces recorded against the catalog.
+func (c *OpCatalog) Operations() map[string]OpSpec {
+	out := make(map[string]OpSpec, len(c.ops))
+	for op, spec := range c.ops {
+		out[op] = spec
+	}
+	return out
+}
+
+// Lookup returns the spec for an operation.
+func (c *OpCatalog) Lookup(operation string) (OpSpec, bool) {
+	spec, ok := c.ops[operation]
+	return spec, ok
+}
+
+// WithOpCatalog attaches the catalog to a new trace.
+func WithOpCatalog(c *OpCatalog) Option {
+	return func(tr *Trace) { tr.catalog = c }
+}
+
+// SetOpCatalog attaches the catalog to a trace loaded from storage, so
+// Verify can check recorded operation versions against current
+// semantics.
+func (tr *Trace) SetOpCatalog(c *OpCatalog) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.catalog = c
+}
+
+// stampOpVersionLocked writes the cataloged version onto a step before
+// its chain hash is computed, so the version in force is part of the
+// hashed record.
+func (tr *Trace) stampOpVersionLocked(st *Step) {
+	if tr.catalog == nil {
+		return
+	}
+	if spec, ok := tr.catalog.Lookup(st.Operation); ok {
+		st.OpVersion = spec.Version
+	}
+}
+
+// checkCatalogLocked embeds a failed assertion for every argument the
+// cataloged schema requires that the just-recorded step is missing.
+func (tr *Trace) checkCatalogLocked(st *Step) {
+	if tr.catalog == nil {
+		return
+	}
+	spec, ok := tr.catalog.Lookup(st.Operation)
+	if !ok {
+		return
+	}
+	var missing []string
+	for _, key := range spec.Inputs {
+		if _, ok := st.Inputs[key]; !ok {
+			missing = append(missing, "input "+key)
+		}
+	}
+	for _, key := range spec.Outputs {
+		if _, ok := st.Outputs[key]; !ok {
+			missing = append(missing, "output "+key)
+		}
+	}
+	for _, m := range missing {
+		name := fmt.Sprintf("catalog:%s:%s", st.Operation, m)
+		tr.addStepLocked("assert",
+			map[string]any{"name": name},
+			map[string]any{"passed": false},
+			WithRefs(st.Index),
+			WithStepError(fmt.Errorf("assertion %s failed", name)),
+			WithDescription(fmt.Sprintf("%s required by %s@%s missing", m, st.Operation, spec.Version)))
+	}
+}
+
+// verifyCatalogLocked flags steps whose recorded operation version no
+// longer matches the attached catalog.
+func (tr *Trace) verifyCatalogLocked() error {
+	if tr.catalog == nil {
+		return nil
+	}
+	for _, st := range tr.Steps {
+		spec, ok := tr.catalog.Lookup(st.Operation)
+		if !ok {
+			continue
+		}
+		if st.OpVersion != spec.Version {
+			return fmt.Errorf("trace %s: step %d operation %s recorded at version %q, catalog is %s",
+				tr.ID, st.Index, st.Operation, st.OpVersion, spec.Version)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package trace
+
+import (
+	"strings"
+	"testing"
+)
+
+func taxCatalog(t *testing.T) *OpCatalog {
+	t.Helper()
+	c := NewOpCatalog()
+	if err := c.Register("tax.rate", OpSpec{
+		Version: "2.1.0",
+		Inputs:  []string{"income", "rate"},
+		Outputs: []string{"tax"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestOpCatalogRegister(t *testing.T) {
+	c := taxCatalog(t)
+	if err := c.Register("tax.rate", OpSpec{Version: "3.0.0"}); err == nil {
+		t.Fatal("re-registration accepted")
+	}
+	if err := c.Register("versionless", OpSpec{}); err == nil {
+		t.Fatal("empty version accepted")
+	}
+	if spec, ok := c.Lookup("tax.rate"); !ok || spec.Version != "2.1.0" {
+		t.Fatalf("Lookup: %+v %v", spec, ok)
+	}
+	ops := c.Operations()
+	ops["tax.rate"] = OpSpec{Version: "hacked"}
+	if spec, _ := c.Lookup("tax.rate"); spec.Version != "2.1.0" {
+		t.Fatal("Operations returned the live map")
+	}
+}
+
+func TestCatalogStampsOpVersionIntoHash(t *testing.T) {
+	tr := New("t", nil, WithOpCatalog(taxCatalog(t)))
+	st := tr.AddStep("tax.rate",
+		map[string]any{"income": 50000, "rate": 0.2},
+		map[string]any{"tax": 10000})
+	if st.OpVersion != "2.1.0" {
+		t.Fatalf("OpVersion %q", st.OpVersion)
+	}
+	tr.Complete(10000)
+	data, _ := tr.ToJSON()
+	cp, _ := FromJSON(data)
+	cp.Steps[0].OpVersion = "2.2.0"
+	if err := cp.Verify(); err == nil {
+		t.Fatal("edited op version passed Verify")
+	}
+}
+
+func TestCatalogEmbedsMissingArgumentAssertions(t *testing.T) {
+	tr := New("t", nil, WithOpCatalog(taxCatalog(t)))
+	tr.AddStep("tax.rate", map[string]any{"income": 50000}, nil) // rate and tax missing
+	failed := tr.FailedAssertions()
+	if len(failed) != 2 {
+		t.Fatalf("%d failed assertions, want 2", len(failed))
+	}
+	name, _ := failed[0].Inputs["name"].(string)
+	if !strings.HasPrefix(name, "catalog:tax.rate:") {
+		t.Fatalf("assertion name %q", name)
+	}
+}
+
+func TestVerifyFlagsCatalogVersionDrift(t *testing.T) {
+	tr := New("t", nil, WithOpCatalog(taxCatalog(t)))
+	tr.AddStep("tax.rate",
+		map[string]any{"income": 1, "rate": 0.1},
+		map[string]any{"tax": 0.1})
+	tr.Complete(0.1)
+	data, _ := tr.ToJSON()
+	cp, _ := FromJSON(data)
+	if err := cp.Verify(); err != nil {
+		t.Fatalf("without a catalog attached: %v", err)
+	}
+	drifted := NewOpCatalog()
+	if err := drifted.Register("tax.rate", OpSpec{Version: "3.0.0"}); err != nil {
+		t.Fatal(err)
+	}
+	cp.SetOpCatalog(drifted)
+	err := cp.Verify()
+	if err == nil || !strings.Contains(err.Error(), "2.1.0") {
+		t.Fatalf("version drift not flagged: %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package trace
+
+import (
+	"time"
+)
+
+// CheckpointStore is the slice of a trace store that checkpointing needs;
+// store.Store satisfies it.
+type CheckpointStore interface {
+	Put(tr *Trace) error
+}
+
+// WithCheckpointing persists the trace to the store every interval while
+// it is recording, with a final snapshot on the first tick after it
+// reaches a terminal state. After a
+// crash the store holds the last checkpoint — a still-recording trace —
+// which store.Recover surfaces for resumption or finalization. Checkpoint
+// errors are recorded in trace metadata rather than interrupting the
+// computation.
+func WithCheckpointing(s CheckpointStore, interval time.Duration) Option {
+	return func(tr *Trace) {
+		go tr.checkpointLoop(s, interval)
+	}
+}
+
+func (tr *Trace) checkpointLoop(s CheckpointStore, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		terminal := tr.checkpoint(s)
+		if terminal {
+			return
+		}
+	}
+}
+
+// checkpoint writes one snapshot and reports whether the trace has
+// reached a terminal state (the final snapshot included).
+func (tr *Trace) checkpoint(s CheckpointStore) bool {
+	tr.mu.Lock()
+	terminal := tr.Status != StatusRecording
+	tr.mu.Unlock()
+	if err := s.Put(tr); err != nil {
+		tr.SetMetadata("checkpoint.error", err.Error())
+	}
+	return terminal
+}
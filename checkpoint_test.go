@@ -0,0 +1,57 @@
+package trace
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type checkpointRecorder struct {
+	mu   sync.Mutex
+	puts int
+	err  error
+}
+
+func (r *checkpointRecorder) Put(tr *Trace) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.puts++
+	return r.err
+}
+
+func (r *checkpointRecorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.puts
+}
+
+func TestCheckpointingSnapshotsUntilTerminal(t *testing.T) {
+	rec := &checkpointRecorder{}
+	tr := New("t", nil, WithCheckpointing(rec, time.Millisecond))
+	tr.AddStep("work", nil, nil)
+	deadline := time.Now().Add(time.Second)
+	for rec.count() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d checkpoints after a second", rec.count())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	tr.Complete(nil)
+	// The loop takes one final snapshot and stops.
+	time.Sleep(20 * time.Millisecond)
+	settled := rec.count()
+	time.Sleep(20 * time.Millisecond)
+	if rec.count() != settled {
+		t.Fatal("checkpoint loop kept running after the terminal snapshot")
+	}
+}
+
+func TestCheckpointErrorLandsInMetadata(t *testing.T) {
+	rec := &checkpointRecorder{err: errors.New("disk full")}
+	tr := New("t", nil)
+	tr.checkpoint(rec)
+	if tr.Metadata["checkpoint.error"] != "disk full" {
+		t.Fatalf("metadata %v", tr.Metadata)
+	}
+}
@@ -0,0 +1,82 @@
+package trace
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Child is an independent recorder handed to one goroutine of a concurrent
+// computation. Each goroutine writes steps to its own child without
+// contending on the parent; Join then merges every child's steps into the
+// parent in a deterministic order — by child name, then recording sequence —
+// rather than interleaved by wall clock, so concurrent runs of the same
+// computation produce identical traces.
+type Child struct {
+	Name string
+
+	mu    sync.Mutex
+	steps []childStep
+}
+
+type childStep struct {
+	operation string
+	inputs    map[string]any
+	outputs   map[string]any
+	opts      []StepOption
+	time      time.Time
+}
+
+// Child creates and registers a named child recorder on the trace. Names
+// should be stable across runs (e.g. "worker-3", not a goroutine ID).
+func (tr *Trace) Child(name string) *Child {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	c := &Child{Name: name}
+	tr.children = append(tr.children, c)
+	return c
+}
+
+// AddStep records a step on the child. It is safe to call from the child's
+// goroutine while other children and the parent record concurrently.
+func (c *Child) AddStep(operation string, inputs, outputs map[string]any, opts ...StepOption) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.steps = append(c.steps, childStep{
+		operation: operation,
+		inputs:    inputs,
+		outputs:   outputs,
+		opts:      opts,
+		time:      time.Now(),
+	})
+}
+
+// Join merges all registered children's steps into the parent trace. Children
+// are merged in lexical name order, each child's steps in the order they were
+// recorded. Callers must ensure every child goroutine has finished before
+// joining. Joined children are unregistered; the same child must not be
+// written to afterwards.
+func (tr *Trace) Join() {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if tr.Status != StatusRecording {
+		return
+	}
+	children := tr.children
+	tr.children = nil
+	sort.Slice(children, func(i, j int) bool { return children[i].Name < children[j].Name })
+	for _, c := range children {
+		c.mu.Lock()
+		for _, cs := range c.steps {
+			opts := append([]StepOption{withOrigin(c.Name)}, cs.opts...)
+			st := tr.addStepLocked(cs.operation, cs.inputs, cs.outputs, opts...)
+			st.Time = cs.time
+		}
+		c.steps = nil
+		c.mu.Unlock()
+	}
+}
+
+func withOrigin(name string) StepOption {
+	return func(st *Step) { st.Origin = name }
+}
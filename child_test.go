@@ -0,0 +1,67 @@
+package trace
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestJoinMergesChildrenDeterministically(t *testing.T) {
+	run := func() *Trace {
+		tr := New("fanout", map[string]any{"n": 2})
+		var wg sync.WaitGroup
+		for _, name := range []string{"worker-b", "worker-a"} {
+			c := tr.Child(name)
+			wg.Add(1)
+			go func(c *Child) {
+				defer wg.Done()
+				for i := 0; i < 3; i++ {
+					c.AddStep("work", map[string]any{"i": i}, nil)
+				}
+			}(c)
+		}
+		wg.Wait()
+		tr.Join()
+		tr.Complete(nil)
+		return tr
+	}
+
+	tr := run()
+	if len(tr.Steps) != 6 {
+		t.Fatalf("%d steps after join", len(tr.Steps))
+	}
+	// Lexical child order, recording order within a child.
+	for i, want := range []string{"worker-a", "worker-a", "worker-a", "worker-b", "worker-b", "worker-b"} {
+		if tr.Steps[i].Origin != want {
+			t.Fatalf("step %d origin %q, want %q", i, tr.Steps[i].Origin, want)
+		}
+	}
+	if err := tr.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	// Concurrent runs of the same computation produce identical traces.
+	if other := run(); other.ContentHash() != tr.ContentHash() {
+		t.Fatal("join order depends on scheduling")
+	}
+}
+
+func TestJoinAfterTerminalIsNoop(t *testing.T) {
+	tr := New("t", nil)
+	c := tr.Child("w")
+	c.AddStep("late", nil, nil)
+	tr.Complete(nil)
+	tr.Join()
+	if len(tr.Steps) != 0 {
+		t.Fatalf("join mutated a terminal trace: %+v", tr.Steps)
+	}
+}
+
+func TestChildOriginIsHashed(t *testing.T) {
+	tr := New("t", nil)
+	c := tr.Child("worker-1")
+	c.AddStep("work", nil, nil)
+	tr.Join()
+	tr.Complete(nil)
+	if err := tamper(t, tr, func(tr *Trace) { tr.Steps[0].Origin = "worker-2" }); err == nil {
+		t.Fatal("rewritten origin passed Verify")
+	}
+}
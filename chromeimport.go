@@ -0,0 +1,132 @@
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// chromeImportEvent mirrors the fields of the Trace Event format the
+// importer consumes; unknown fields are ignored.
+type chromeImportEvent struct {
+	Name  string         `json:"name"`
+	Phase string         `json:"ph"`
+	TS    float64        `json:"ts"`
+	Dur   float64        `json:"dur"`
+	PID   int            `json:"pid"`
+	TID   int            `json:"tid"`
+	Args  map[string]any `json:"args"`
+}
+
+// FromChromeTraceEvents ingests Chrome trace-event JSON (the object form
+// with "traceEvents" or a bare event array) into a trace, so existing
+// profiling artifacts can be analyzed with this package's diff and query
+// tools. Complete ("X") events and matched begin/end ("B"/"E") pairs become
+// steps with their measured durations; event nesting is preserved by
+// recording each step's enclosing step in Refs, and thread names become
+// step origins.
+func FromChromeTraceEvents(r io.Reader) (*Trace, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("trace: read chrome trace: %w", err)
+	}
+	events, name, err := decodeChromeEvents(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	threadNames := make(map[int]string)
+	var spans []chromeSpan
+	open := make(map[int][]chromeImportEvent) // per-tid begin stacks
+	for _, ev := range events {
+		switch ev.Phase {
+		case "M":
+			if ev.Name == "thread_name" {
+				if n, ok := ev.Args["name"].(string); ok {
+					threadNames[ev.TID] = n
+				}
+			}
+		case "X":
+			spans = append(spans, chromeSpan{
+				name: ev.Name, tid: ev.TID, args: ev.Args,
+				start: int64(ev.TS * 1000), end: int64((ev.TS + ev.Dur) * 1000),
+			})
+		case "B":
+			open[ev.TID] = append(open[ev.TID], ev)
+		case "E":
+			stack := open[ev.TID]
+			if len(stack) == 0 {
+				continue
+			}
+			begin := stack[len(stack)-1]
+			open[ev.TID] = stack[:len(stack)-1]
+			spans = append(spans, chromeSpan{
+				name: begin.Name, tid: ev.TID, args: begin.Args,
+				start: int64(begin.TS * 1000), end: int64(ev.TS * 1000),
+			})
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		if spans[i].start != spans[j].start {
+			return spans[i].start < spans[j].start
+		}
+		return spans[i].end > spans[j].end // parents before children
+	})
+
+	tr := New(name, nil)
+	base := time.Now()
+	// Per-tid stacks of (span end, step index) establish containment.
+	type frame struct {
+		end   int64
+		index int
+	}
+	stacks := make(map[int][]frame)
+	for _, sp := range spans {
+		stack := stacks[sp.tid]
+		for len(stack) > 0 && stack[len(stack)-1].end <= sp.start {
+			stack = stack[:len(stack)-1]
+		}
+		opts := []StepOption{WithDuration(time.Duration(sp.end - sp.start))}
+		if len(stack) > 0 {
+			opts = append(opts, WithRefs(stack[len(stack)-1].index))
+		}
+		if lane := threadNames[sp.tid]; lane != "" && lane != "main" {
+			opts = append(opts, withOrigin(lane))
+		}
+		st := tr.AddStep(sp.name, sp.args, nil, opts...)
+		st.Time = base.Add(time.Duration(sp.start))
+		stacks[sp.tid] = append(stack, frame{end: sp.end, index: st.Index})
+	}
+	tr.Complete(nil)
+	return tr, nil
+}
+
+type chromeSpan struct {
+	name       string
+	tid        int
+	args       map[string]any
+	start, end int64 // nanoseconds from file origin
+}
+
+// decodeChromeEvents accepts both serialization shapes of the format and
+// recovers the trace name from otherData when present.
+func decodeChromeEvents(raw []byte) ([]chromeImportEvent, string, error) {
+	name := "chrome-trace"
+	var obj struct {
+		TraceEvents []chromeImportEvent `json:"traceEvents"`
+		OtherData   map[string]any      `json:"otherData"`
+	}
+	if err := json.Unmarshal(raw, &obj); err == nil && obj.TraceEvents != nil {
+		if n, ok := obj.OtherData["trace_name"].(string); ok && n != "" {
+			name = n
+		}
+		return obj.TraceEvents, name, nil
+	}
+	var events []chromeImportEvent
+	if err := json.Unmarshal(raw, &events); err != nil {
+		return nil, "", fmt.Errorf("trace: parse chrome trace: %w", err)
+	}
+	return events, name, nil
+}
@@ -0,0 +1,81 @@
+package trace
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// chromeEvent is one entry in the Trace Event JSON format understood by
+// Perfetto and chrome://tracing. Timestamps and durations are microseconds.
+type chromeEvent struct {
+	Name  string         `json:"name"`
+	Phase string         `json:"ph"`
+	TS    int64          `json:"ts"`
+	Dur   int64          `json:"dur,omitempty"`
+	PID   int            `json:"pid"`
+	TID   int            `json:"tid"`
+	Args  map[string]any `json:"args,omitempty"`
+}
+
+// ToChromeTraceEvents writes the trace in the Trace Event JSON format so it
+// can be loaded into Perfetto or chrome://tracing and inspected on the
+// standard timeline UI. Each step becomes a complete ("X") event; origins
+// map to named threads.
+func (tr *Trace) ToChromeTraceEvents(w io.Writer) error {
+	tr.mu.Lock()
+	steps := make([]*Step, len(tr.Steps))
+	copy(steps, tr.Steps)
+	tr.mu.Unlock()
+
+	lanes, order := timelineLanes(steps)
+	start, _ := timelineSpan(steps)
+
+	tids := make(map[string]int, len(order))
+	events := make([]chromeEvent, 0, len(steps)+len(order))
+	for i, lane := range order {
+		tids[lane] = i + 1
+		events = append(events, chromeEvent{
+			Name:  "thread_name",
+			Phase: "M",
+			PID:   1,
+			TID:   i + 1,
+			Args:  map[string]any{"name": lane},
+		})
+	}
+	for _, lane := range order {
+		for _, st := range lanes[lane] {
+			args := map[string]any{
+				"trace_id":   tr.ID,
+				"step_index": st.Index,
+			}
+			if len(st.Inputs) > 0 {
+				args["inputs"] = st.Inputs
+			}
+			if len(st.Outputs) > 0 {
+				args["outputs"] = st.Outputs
+			}
+			if st.Err != "" {
+				args["error"] = st.Err
+			}
+			dur := st.Duration.Microseconds()
+			if dur < 1 {
+				dur = 1
+			}
+			events = append(events, chromeEvent{
+				Name:  st.Operation,
+				Phase: "X",
+				TS:    st.Time.Sub(start).Microseconds(),
+				Dur:   dur,
+				PID:   1,
+				TID:   tids[lane],
+				Args:  args,
+			})
+		}
+	}
+	enc := json.NewEncoder(w)
+	return enc.Encode(map[string]any{
+		"traceEvents":     events,
+		"displayTimeUnit": "ms",
+		"otherData":       map[string]any{"trace_id": tr.ID, "trace_name": tr.Name},
+	})
+}
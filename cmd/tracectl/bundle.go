@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/euclidtrace/trace"
+	"github.com/euclidtrace/trace/bundle"
+)
+
+// cmdBundle packs a canonical trace JSON file into a .tracebundle container.
+func cmdBundle(args []string) error {
+	fs := flag.NewFlagSet("bundle", flag.ExitOnError)
+	out := fs.String("o", "", "output path (default: trace ID + "+bundle.Ext+")")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: tracectl bundle [-o out%s] <trace.json>", bundle.Ext)
+	}
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	tr, err := trace.FromJSON(data)
+	if err != nil {
+		return err
+	}
+	path := *out
+	if path == "" {
+		path = tr.ID + bundle.Ext
+	}
+	if err := bundle.WriteFile(path, bundle.New(tr)); err != nil {
+		return err
+	}
+	fmt.Println(path)
+	return nil
+}
+
+// cmdInspect summarizes a bundle or a trace JSON file.
+func cmdInspect(args []string) error {
+	fs := flag.NewFlagSet("inspect", flag.ExitOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: tracectl inspect <file>")
+	}
+	path := fs.Arg(0)
+	var tr *trace.Trace
+	var attachments, signatures int
+	if strings.HasSuffix(path, bundle.Ext) {
+		b, err := bundle.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		tr = b.Trace
+		attachments = len(b.Attachments)
+		signatures = len(b.Signatures)
+	} else {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		tr, err = trace.FromJSON(data)
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Printf("id:           %s\n", tr.ID)
+	fmt.Printf("name:         %s\n", tr.Name)
+	fmt.Printf("status:       %s\n", tr.Status)
+	fmt.Printf("steps:        %d\n", len(tr.Steps))
+	fmt.Printf("inputs:       %d\n", len(tr.Inputs))
+	fmt.Printf("content hash: %s\n", tr.ContentHash())
+	if strings.HasSuffix(path, bundle.Ext) {
+		fmt.Printf("attachments:  %d\n", attachments)
+		fmt.Printf("signatures:   %d\n", signatures)
+	}
+	return nil
+}
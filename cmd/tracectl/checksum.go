@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/euclidtrace/trace/sidecar"
+)
+
+// cmdChecksum generates or verifies SHA-256 sidecar files for serialized
+// traces and bundles.
+func cmdChecksum(args []string) error {
+	fs := flag.NewFlagSet("checksum", flag.ExitOnError)
+	verify := fs.Bool("verify", false, "verify existing sidecars instead of writing them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: tracectl checksum [-verify] <file>...")
+	}
+	for _, path := range fs.Args() {
+		if *verify {
+			if err := sidecar.Verify(path); err != nil {
+				return err
+			}
+			fmt.Printf("%s: OK\n", path)
+			continue
+		}
+		sidecarPath, err := sidecar.Write(path)
+		if err != nil {
+			return err
+		}
+		fmt.Println(sidecarPath)
+	}
+	return nil
+}
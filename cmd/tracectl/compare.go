@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/euclidtrace/trace"
+	"github.com/euclidtrace/trace/bundle"
+)
+
+// compareReport is the aggregate of comparing two archives of the same
+// computations produced by different code revisions. Traces match by ID,
+// which is derived from name and inputs, so a pair describes the same
+// computation run under each revision.
+type compareReport struct {
+	OldTraces int `json:"old_traces"`
+	NewTraces int `json:"new_traces"`
+	Matched   int `json:"matched"`
+	// OnlyOld and OnlyNew list trace IDs present in just one archive.
+	OnlyOld []string `json:"only_old,omitempty"`
+	OnlyNew []string `json:"only_new,omitempty"`
+	// ResultChanges lists matched pairs whose final results differ.
+	ResultChanges []resultChange `json:"result_changes,omitempty"`
+	// NewOperations and RemovedOperations aggregate the step operations
+	// that appear only under one revision, across all matched pairs.
+	NewOperations     []string `json:"new_operations,omitempty"`
+	RemovedOperations []string `json:"removed_operations,omitempty"`
+	// TimingRegressions lists matched pairs whose wall time grew past the
+	// slowdown threshold.
+	TimingRegressions []timingRegression `json:"timing_regressions,omitempty"`
+}
+
+type resultChange struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	OldResult string `json:"old_result"`
+	NewResult string `json:"new_result"`
+}
+
+type timingRegression struct {
+	ID    string  `json:"id"`
+	Name  string  `json:"name"`
+	OldMs float64 `json:"old_ms"`
+	NewMs float64 `json:"new_ms"`
+	Ratio float64 `json:"ratio"`
+}
+
+// cmdCompareVersions matches the traces in two archives by input-based ID
+// and reports what a code revision changed: results, the operation
+// vocabulary, and timing.
+func cmdCompareVersions(args []string) error {
+	fs := flag.NewFlagSet("compare-versions", flag.ExitOnError)
+	slowdown := fs.Float64("slowdown", 1.5, "report pairs whose wall time grew by at least this factor")
+	jsonOut := fs.Bool("json", false, "emit a machine-readable JSON report")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: tracectl compare-versions [-slowdown f] [-json] <old-dir> <new-dir>")
+	}
+
+	oldTraces, err := loadArchive(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	newTraces, err := loadArchive(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	report := compareReport{OldTraces: len(oldTraces), NewTraces: len(newTraces)}
+	oldOps := map[string]bool{}
+	newOps := map[string]bool{}
+	for id, oldTr := range oldTraces {
+		newTr, ok := newTraces[id]
+		if !ok {
+			report.OnlyOld = append(report.OnlyOld, id)
+			continue
+		}
+		report.Matched++
+		for _, st := range oldTr.Steps {
+			oldOps[st.Operation] = true
+		}
+		for _, st := range newTr.Steps {
+			newOps[st.Operation] = true
+		}
+		if trace.HashValue(oldTr.Result) != trace.HashValue(newTr.Result) {
+			report.ResultChanges = append(report.ResultChanges, resultChange{
+				ID:        id,
+				Name:      oldTr.Name,
+				OldResult: fmt.Sprintf("%v", oldTr.Result),
+				NewResult: fmt.Sprintf("%v", newTr.Result),
+			})
+		}
+		oldMs := wallMillis(oldTr)
+		newMs := wallMillis(newTr)
+		if oldMs > 0 && newMs >= oldMs**slowdown {
+			report.TimingRegressions = append(report.TimingRegressions, timingRegression{
+				ID:    id,
+				Name:  oldTr.Name,
+				OldMs: oldMs,
+				NewMs: newMs,
+				Ratio: newMs / oldMs,
+			})
+		}
+	}
+	for id := range newTraces {
+		if _, ok := oldTraces[id]; !ok {
+			report.OnlyNew = append(report.OnlyNew, id)
+		}
+	}
+	for op := range newOps {
+		if !oldOps[op] {
+			report.NewOperations = append(report.NewOperations, op)
+		}
+	}
+	for op := range oldOps {
+		if !newOps[op] {
+			report.RemovedOperations = append(report.RemovedOperations, op)
+		}
+	}
+	sort.Strings(report.OnlyOld)
+	sort.Strings(report.OnlyNew)
+	sort.Strings(report.NewOperations)
+	sort.Strings(report.RemovedOperations)
+	sort.Slice(report.ResultChanges, func(i, j int) bool { return report.ResultChanges[i].ID < report.ResultChanges[j].ID })
+	sort.Slice(report.TimingRegressions, func(i, j int) bool { return report.TimingRegressions[i].Ratio > report.TimingRegressions[j].Ratio })
+
+	if *jsonOut {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	fmt.Printf("old archive: %d traces, new archive: %d traces, matched by ID: %d\n",
+		report.OldTraces, report.NewTraces, report.Matched)
+	if len(report.OnlyOld) > 0 {
+		fmt.Printf("only in old archive: %d\n", len(report.OnlyOld))
+	}
+	if len(report.OnlyNew) > 0 {
+		fmt.Printf("only in new archive: %d\n", len(report.OnlyNew))
+	}
+	fmt.Printf("result changes: %d\n", len(report.ResultChanges))
+	for _, rc := range report.ResultChanges {
+		fmt.Printf("  %s (%s): %s -> %s\n", rc.ID, rc.Name, rc.OldResult, rc.NewResult)
+	}
+	if len(report.NewOperations) > 0 {
+		fmt.Printf("new operations: %s\n", strings.Join(report.NewOperations, ", "))
+	}
+	if len(report.RemovedOperations) > 0 {
+		fmt.Printf("removed operations: %s\n", strings.Join(report.RemovedOperations, ", "))
+	}
+	fmt.Printf("timing regressions (>= %.2fx): %d\n", *slowdown, len(report.TimingRegressions))
+	for _, tr := range report.TimingRegressions {
+		fmt.Printf("  %s (%s): %.1fms -> %.1fms (%.2fx)\n", tr.ID, tr.Name, tr.OldMs, tr.NewMs, tr.Ratio)
+	}
+	return nil
+}
+
+// loadArchive reads every trace JSON file and bundle under dir, keyed by
+// trace ID. Duplicate IDs within one archive are an error: the archive
+// would be ambiguous about which run represents the computation.
+func loadArchive(dir string) (map[string]*trace.Trace, error) {
+	traces := map[string]*trace.Trace{}
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		var tr *trace.Trace
+		switch {
+		case strings.HasSuffix(path, bundle.Ext):
+			b, err := bundle.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			tr = b.Trace
+		case strings.HasSuffix(path, ".json"):
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			tr, err = trace.FromJSON(data)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+		default:
+			return nil
+		}
+		if _, ok := traces[tr.ID]; ok {
+			return fmt.Errorf("%s: duplicate trace ID %s in archive", path, tr.ID)
+		}
+		traces[tr.ID] = tr
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return traces, nil
+}
+
+// wallMillis returns the trace's recorded wall time in milliseconds, or
+// zero when the trace never finished.
+func wallMillis(tr *trace.Trace) float64 {
+	if tr.Finished.IsZero() {
+		return 0
+	}
+	return float64(tr.Finished.Sub(tr.Started)) / float64(time.Millisecond)
+}
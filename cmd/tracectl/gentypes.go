@@ -0,0 +1,329 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/euclidtrace/trace"
+)
+
+// cmdGenTypes emits type definitions for the serialized trace format in
+// another language, derived by reflection from the Go structs so the
+// output cannot drift from what ToJSON actually writes. TypeScript
+// output carries interfaces, a JSON-Schema-style definition table, and a
+// structural validator over it.
+func cmdGenTypes(args []string) error {
+	fs := flag.NewFlagSet("gen-types", flag.ExitOnError)
+	lang := fs.String("lang", "ts", "output language (only ts is supported)")
+	out := fs.String("o", "", "output file (default stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("usage: tracectl gen-types [-lang ts] [-o file]")
+	}
+	if *lang != "ts" {
+		return fmt.Errorf("unsupported language %q", *lang)
+	}
+
+	g := newTypeGen()
+	g.add(reflect.TypeOf(trace.Trace{}))
+	src := g.typescript()
+
+	if *out == "" {
+		fmt.Print(src)
+		return nil
+	}
+	return os.WriteFile(*out, []byte(src), 0o644)
+}
+
+// typeGen walks the exported struct graph of the trace package and
+// collects type definitions in discovery order.
+type typeGen struct {
+	order []reflect.Type
+	seen  map[reflect.Type]bool
+}
+
+func newTypeGen() *typeGen {
+	return &typeGen{seen: map[reflect.Type]bool{}}
+}
+
+// add registers a named trace-package type and, for structs, everything
+// reachable from its serialized fields.
+func (g *typeGen) add(t reflect.Type) {
+	if g.seen[t] {
+		return
+	}
+	g.seen[t] = true
+	g.order = append(g.order, t)
+	if t.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || jsonName(f) == "" {
+			continue
+		}
+		g.tsType(f.Type)
+	}
+}
+
+// tsType returns the TypeScript type for a Go type, registering named
+// trace-package types it encounters.
+func (g *typeGen) tsType(t reflect.Type) string {
+	switch {
+	case t == reflect.TypeOf(time.Time{}):
+		return "string"
+	case t == reflect.TypeOf(time.Duration(0)):
+		return "number"
+	}
+	if t.PkgPath() != "" && t.Name() != "" {
+		g.add(t)
+		return t.Name()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string" // []byte serializes as base64
+		}
+		return g.tsType(t.Elem()) + "[]"
+	case reflect.Map:
+		return "Record<string, " + g.tsType(t.Elem()) + ">"
+	case reflect.Pointer:
+		return g.tsType(t.Elem())
+	case reflect.Interface:
+		return "unknown"
+	}
+	return "unknown"
+}
+
+// typescript renders the collected definitions: one interface or alias
+// per type, a schema table mirroring them, and a validator.
+func (g *typeGen) typescript() string {
+	var b strings.Builder
+	b.WriteString("// Code generated by tracectl gen-types; DO NOT EDIT.\n")
+	b.WriteString("//\n// Type definitions for the serialized trace format of\n")
+	b.WriteString("// github.com/euclidtrace/trace; see spec/SPEC.md in that repository.\n\n")
+
+	for i := 0; i < len(g.order); i++ { // g.order grows while rendering
+		t := g.order[i]
+		if t.Kind() != reflect.Struct {
+			fmt.Fprintf(&b, "export type %s = %s;\n\n", t.Name(), kindTS(t.Kind()))
+			continue
+		}
+		fmt.Fprintf(&b, "export interface %s {\n", t.Name())
+		for j := 0; j < t.NumField(); j++ {
+			f := t.Field(j)
+			name := jsonName(f)
+			if !f.IsExported() || name == "" {
+				continue
+			}
+			opt := ""
+			if jsonOmitEmpty(f) {
+				opt = "?"
+			}
+			fmt.Fprintf(&b, "  %s%s: %s;\n", name, opt, g.tsType(f.Type))
+		}
+		b.WriteString("}\n\n")
+	}
+
+	b.WriteString("export const definitions = {\n")
+	for _, t := range g.order {
+		fmt.Fprintf(&b, "  %s: %s,\n", t.Name(), g.schema(t, "  "))
+	}
+	b.WriteString("} as const;\n\n")
+	b.WriteString(tsValidator)
+	return b.String()
+}
+
+// schema renders the JSON-Schema-style entry for one named type.
+func (g *typeGen) schema(t reflect.Type, indent string) string {
+	if t.Kind() != reflect.Struct {
+		return fmt.Sprintf("{ type: %q }", kindTS(t.Kind()))
+	}
+	var b strings.Builder
+	b.WriteString("{\n")
+	fmt.Fprintf(&b, "%s  type: \"object\",\n", indent)
+	fmt.Fprintf(&b, "%s  properties: {\n", indent)
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := jsonName(f)
+		if !f.IsExported() || name == "" {
+			continue
+		}
+		if !jsonOmitEmpty(f) {
+			required = append(required, name)
+		}
+		fmt.Fprintf(&b, "%s    %q: %s,\n", indent, name, g.fieldSchema(f.Type))
+	}
+	fmt.Fprintf(&b, "%s  },\n", indent)
+	sort.Strings(required)
+	fmt.Fprintf(&b, "%s  required: [", indent)
+	for i, name := range required {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%q", name)
+	}
+	b.WriteString("],\n")
+	fmt.Fprintf(&b, "%s}", indent)
+	return b.String()
+}
+
+// fieldSchema renders the inline schema for one field type.
+func (g *typeGen) fieldSchema(t reflect.Type) string {
+	switch {
+	case t == reflect.TypeOf(time.Time{}):
+		return `{ type: "string" }`
+	case t == reflect.TypeOf(time.Duration(0)):
+		return `{ type: "number" }`
+	}
+	if t.PkgPath() != "" && t.Name() != "" {
+		return fmt.Sprintf("{ $ref: %q }", t.Name())
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return `{ type: "boolean" }`
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return `{ type: "number" }`
+	case reflect.String:
+		return `{ type: "string" }`
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return `{ type: "string" }`
+		}
+		return fmt.Sprintf("{ type: \"array\", items: %s }", g.fieldSchema(t.Elem()))
+	case reflect.Map:
+		return fmt.Sprintf("{ type: \"object\", additionalProperties: %s }", g.fieldSchema(t.Elem()))
+	case reflect.Pointer:
+		return g.fieldSchema(t.Elem())
+	}
+	return "{}"
+}
+
+// kindTS maps a non-struct named type's kind to its TypeScript base.
+func kindTS(k reflect.Kind) string {
+	switch k {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	default:
+		return "number"
+	}
+}
+
+// jsonName returns the field's serialized name, or "" when the field is
+// excluded from JSON.
+func jsonName(f reflect.StructField) string {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+func jsonOmitEmpty(f reflect.StructField) bool {
+	tag := f.Tag.Get("json")
+	_, opts, _ := strings.Cut(tag, ",")
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == "omitempty" {
+			return true
+		}
+	}
+	return false
+}
+
+// tsValidator is the emitted structural validator: it walks a value
+// against the definition table and returns the paths that do not
+// conform.
+const tsValidator = `type SchemaNode = {
+  type?: string;
+  $ref?: string;
+  items?: SchemaNode;
+  additionalProperties?: SchemaNode;
+  properties?: Record<string, SchemaNode>;
+  required?: readonly string[];
+};
+
+function check(value: unknown, node: SchemaNode, path: string, errors: string[]): void {
+  if (node.$ref !== undefined) {
+    const target = (definitions as Record<string, SchemaNode>)[node.$ref];
+    if (target === undefined) {
+      errors.push(path + ": unknown definition " + node.$ref);
+      return;
+    }
+    check(value, target, path, errors);
+    return;
+  }
+  if (node.type === undefined) {
+    return;
+  }
+  if (node.type === "array") {
+    if (!Array.isArray(value)) {
+      errors.push(path + ": expected array");
+      return;
+    }
+    if (node.items) {
+      value.forEach((e, i) => check(e, node.items as SchemaNode, path + "[" + i + "]", errors));
+    }
+    return;
+  }
+  if (node.type === "object") {
+    if (typeof value !== "object" || value === null || Array.isArray(value)) {
+      errors.push(path + ": expected object");
+      return;
+    }
+    const obj = value as Record<string, unknown>;
+    for (const name of node.required ?? []) {
+      if (!(name in obj)) {
+        errors.push(path + ": missing required property " + name);
+      }
+    }
+    if (node.properties) {
+      for (const [name, sub] of Object.entries(node.properties)) {
+        if (name in obj) {
+          check(obj[name], sub, path + "." + name, errors);
+        }
+      }
+    } else if (node.additionalProperties) {
+      for (const [name, sub] of Object.entries(obj)) {
+        check(sub, node.additionalProperties, path + "." + name, errors);
+      }
+    }
+    return;
+  }
+  if (typeof value !== node.type) {
+    errors.push(path + ": expected " + node.type);
+  }
+}
+
+/** validateTrace returns the conformance errors of a parsed trace JSON
+ * value against the serialized format; an empty array means the value
+ * is structurally a Trace. */
+export function validateTrace(value: unknown): string[] {
+  const errors: string[] = [];
+  check(value, { $ref: "Trace" }, "trace", errors);
+  return errors;
+}
+`
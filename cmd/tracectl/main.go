@@ -0,0 +1,51 @@
+// Command tracectl works with serialized traces and trace bundles from the
+// command line: packing, inspecting, and verifying computation records.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// commands maps subcommand names to their implementations; each command owns
+// its flag parsing. New subcommands register themselves here.
+var commands = map[string]func(args []string) error{
+	"bundle":           cmdBundle,
+	"checksum":         cmdChecksum,
+	"compare-versions": cmdCompareVersions,
+	"gen-types":        cmdGenTypes,
+	"inspect":          cmdInspect,
+	"verify":           cmdVerify,
+	"verify-batch":     cmdVerifyBatch,
+	"why":              cmdWhy,
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "tracectl: unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "tracectl %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: tracectl <command> [arguments]")
+	fmt.Fprintln(os.Stderr, "commands:")
+	fmt.Fprintln(os.Stderr, "  bundle    pack a trace JSON file into a .tracebundle")
+	fmt.Fprintln(os.Stderr, "  checksum  write or verify SHA-256 sidecar files")
+	fmt.Fprintln(os.Stderr, "  compare-versions  diff two trace archives from different code revisions")
+	fmt.Fprintln(os.Stderr, "  gen-types  emit type definitions for the serialized trace format")
+	fmt.Fprintln(os.Stderr, "  inspect   summarize a .tracebundle or trace JSON file")
+	fmt.Fprintln(os.Stderr, "  verify    verify one trace or bundle, offline-strict with -offline")
+	fmt.Fprintln(os.Stderr, "  verify-batch  validate every trace and bundle under a directory")
+	fmt.Fprintln(os.Stderr, "  why       print the minimal derivation of a result or step")
+}
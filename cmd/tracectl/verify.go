@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/euclidtrace/trace"
+	"github.com/euclidtrace/trace/bundle"
+)
+
+// cmdVerify verifies a single trace JSON file or bundle. For bundles
+// the check covers entry checksums, the step hash chain, detached and
+// step author signatures against the bundle's embedded public keys, and
+// recorded operation versions against its embedded catalog — all from
+// the file alone, with no network access. -offline additionally demands
+// that the bundle be self-contained: a signature without its public key
+// or an operation version without a catalog entry becomes an error
+// instead of an unchecked claim.
+func cmdVerify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	offline := fs.Bool("offline", false, "require the bundle to carry everything its claims need")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: tracectl verify [-offline] <file>")
+	}
+	path := fs.Arg(0)
+
+	if strings.HasSuffix(path, bundle.Ext) {
+		b, err := bundle.ReadFile(path) // checks the index checksums
+		if err != nil {
+			return err
+		}
+		if err := bundle.VerifyOffline(b, *offline); err != nil {
+			return err
+		}
+		fmt.Printf("%s: trace %s verified (%d steps, %d signatures)\n",
+			path, b.Trace.ID, len(b.Trace.Steps), len(b.Signatures))
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	tr, err := trace.FromJSON(data)
+	if err != nil {
+		return err
+	}
+	if err := tr.Verify(); err != nil {
+		return err
+	}
+	fmt.Printf("%s: trace %s verified (%d steps)\n", path, tr.ID, len(tr.Steps))
+	return nil
+}
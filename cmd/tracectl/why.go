@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/euclidtrace/trace"
+)
+
+// cmdWhy prints the minimal derivation of a trace's result or of one step.
+func cmdWhy(args []string) error {
+	fs := flag.NewFlagSet("why", flag.ExitOnError)
+	step := fs.Int("step", trace.ResultRef, "step index to explain (default: the final result)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: tracectl why [-step n] <trace.json>")
+	}
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	tr, err := trace.FromJSON(data)
+	if err != nil {
+		return err
+	}
+	d, err := tr.Why(*step)
+	if err != nil {
+		return err
+	}
+	fmt.Print(d.Render())
+	return nil
+}
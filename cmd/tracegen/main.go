@@ -0,0 +1,263 @@
+// Command tracegen generates traced wrapper implementations for interfaces,
+// recording one step per method call with its arguments and results, so
+// existing computation layers can be instrumented without hand-written
+// boilerplate. It is designed for go:generate:
+//
+//	//go:generate tracegen -type PricingEngine -src engine.go -o engine_traced.go
+//
+// Methods whose first parameter is a context.Context record onto the trace
+// carried by the context; otherwise the wrapper records onto the trace it was
+// constructed with. Embedded interfaces are not expanded.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"strings"
+)
+
+func main() {
+	typeName := flag.String("type", "", "interface type to wrap (required)")
+	src := flag.String("src", "", "source file declaring the interface (required)")
+	out := flag.String("o", "", "output file (default: stdout)")
+	pkg := flag.String("pkg", "", "output package name (default: the source file's package)")
+	flag.Parse()
+	if *typeName == "" || *src == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	code, err := generate(*src, *typeName, *pkg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tracegen: %v\n", err)
+		os.Exit(1)
+	}
+	if *out == "" {
+		os.Stdout.Write(code)
+		return
+	}
+	if err := os.WriteFile(*out, code, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "tracegen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func generate(src, typeName, pkgName string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, src, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	iface := findInterface(file, typeName)
+	if iface == nil {
+		return nil, fmt.Errorf("no interface %q in %s", typeName, src)
+	}
+	if pkgName == "" {
+		pkgName = file.Name.Name
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by tracegen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkgName)
+	fmt.Fprintf(&buf, "import (\n\t\"time\"\n\n\t\"github.com/euclidtrace/trace\"\n")
+	for _, path := range referencedImports(fset, file, iface) {
+		fmt.Fprintf(&buf, "\t%q\n", path)
+	}
+	fmt.Fprintf(&buf, ")\n\n")
+	wrapper := "Traced" + typeName
+	fmt.Fprintf(&buf, "// %s wraps a %s, recording one step per call.\n", wrapper, typeName)
+	fmt.Fprintf(&buf, "type %s struct {\n\tImpl %s\n\tTrace *trace.Trace\n}\n\n", wrapper, typeName)
+	fmt.Fprintf(&buf, "// NewTraced%s returns a traced wrapper around impl.\n", typeName)
+	fmt.Fprintf(&buf, "func NewTraced%s(impl %s, tr *trace.Trace) *%s {\n\treturn &%s{Impl: impl, Trace: tr}\n}\n", typeName, typeName, wrapper, wrapper)
+
+	for _, field := range iface.Methods.List {
+		fn, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) == 0 {
+			continue // embedded interface; not expanded
+		}
+		if err := writeMethod(&buf, fset, wrapper, typeName, field.Names[0].Name, fn); err != nil {
+			return nil, err
+		}
+	}
+	return format.Source(buf.Bytes())
+}
+
+// referencedImports resolves the package qualifiers appearing in the
+// interface's method signatures against the source file's imports, so the
+// generated file imports exactly what the signatures use.
+func referencedImports(fset *token.FileSet, file *ast.File, iface *ast.InterfaceType) []string {
+	qualifiers := map[string]bool{}
+	ast.Inspect(iface, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				qualifiers[ident.Name] = true
+			}
+		}
+		return true
+	})
+	var paths []string
+	for _, imp := range file.Imports {
+		path := strings.Trim(imp.Path.Value, `"`)
+		name := path
+		if i := strings.LastIndex(path, "/"); i >= 0 {
+			name = path[i+1:]
+		}
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		if qualifiers[name] {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+func findInterface(file *ast.File, name string) *ast.InterfaceType {
+	for _, decl := range file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if ts.Name.Name != name {
+				continue
+			}
+			if it, ok := ts.Type.(*ast.InterfaceType); ok {
+				return it
+			}
+		}
+	}
+	return nil
+}
+
+type param struct {
+	name     string
+	typ      string
+	variadic bool
+}
+
+func writeMethod(buf *bytes.Buffer, fset *token.FileSet, wrapper, ifaceName, method string, fn *ast.FuncType) error {
+	params := flatten(fset, fn.Params, "a")
+	results := flatten(fset, fn.Results, "r")
+
+	var sigParams, callArgs []string
+	for _, p := range params {
+		typ := p.typ
+		if p.variadic {
+			sigParams = append(sigParams, p.name+" ..."+typ)
+			callArgs = append(callArgs, p.name+"...")
+		} else {
+			sigParams = append(sigParams, p.name+" "+typ)
+			callArgs = append(callArgs, p.name)
+		}
+	}
+	var sigResults []string
+	for _, r := range results {
+		sigResults = append(sigResults, r.typ)
+	}
+
+	fmt.Fprintf(buf, "\nfunc (w *%s) %s(%s)", wrapper, method, strings.Join(sigParams, ", "))
+	if len(sigResults) > 0 {
+		fmt.Fprintf(buf, " (%s)", strings.Join(sigResults, ", "))
+	}
+	fmt.Fprintf(buf, " {\n")
+
+	// Pick the trace: from a leading context, or the wrapper's own.
+	hasCtx := len(params) > 0 && params[0].typ == "context.Context"
+	if hasCtx {
+		fmt.Fprintf(buf, "\ttr := trace.FromContext(%s)\n\tif tr == nil {\n\t\ttr = w.Trace\n\t}\n", params[0].name)
+	} else {
+		fmt.Fprintf(buf, "\ttr := w.Trace\n")
+	}
+	fmt.Fprintf(buf, "\tstart := time.Now()\n")
+
+	var lhs []string
+	for _, r := range results {
+		lhs = append(lhs, r.name)
+	}
+	fmt.Fprintf(buf, "\t")
+	if len(lhs) > 0 {
+		fmt.Fprintf(buf, "%s := ", strings.Join(lhs, ", "))
+	}
+	fmt.Fprintf(buf, "w.Impl.%s(%s)\n", method, strings.Join(callArgs, ", "))
+
+	fmt.Fprintf(buf, "\tif tr != nil {\n")
+	fmt.Fprintf(buf, "\t\tinputs := map[string]any{")
+	for _, p := range params {
+		if p.typ == "context.Context" {
+			continue
+		}
+		fmt.Fprintf(buf, "%q: %s, ", p.name, p.name)
+	}
+	fmt.Fprintf(buf, "}\n")
+	fmt.Fprintf(buf, "\t\toutputs := map[string]any{")
+	for _, r := range results {
+		if r.typ == "error" {
+			continue
+		}
+		fmt.Fprintf(buf, "%q: %s, ", r.name, r.name)
+	}
+	fmt.Fprintf(buf, "}\n")
+	opts := "trace.WithDuration(time.Since(start))"
+	for _, r := range results {
+		if r.typ == "error" {
+			opts += ", trace.WithStepError(" + r.name + ")"
+			break
+		}
+	}
+	fmt.Fprintf(buf, "\t\ttr.AddStep(%q, inputs, outputs, %s)\n", ifaceName+"."+method, opts)
+	fmt.Fprintf(buf, "\t}\n")
+	if len(lhs) > 0 {
+		fmt.Fprintf(buf, "\treturn %s\n", strings.Join(lhs, ", "))
+	}
+	fmt.Fprintf(buf, "}\n")
+	return nil
+}
+
+// flatten expands a field list into named parameters, inventing names
+// (prefix0, prefix1, ...) where the declaration leaves them out.
+func flatten(fset *token.FileSet, fields *ast.FieldList, prefix string) []param {
+	if fields == nil {
+		return nil
+	}
+	var out []param
+	i := 0
+	for _, f := range fields.List {
+		typ := f.Type
+		variadic := false
+		if ell, ok := typ.(*ast.Ellipsis); ok {
+			typ = ell.Elt
+			variadic = true
+		}
+		typStr := exprString(fset, typ)
+		names := f.Names
+		if len(names) == 0 {
+			out = append(out, param{name: fmt.Sprintf("%s%d", prefix, i), typ: typStr, variadic: variadic})
+			i++
+			continue
+		}
+		for _, n := range names {
+			name := n.Name
+			if name == "_" {
+				name = fmt.Sprintf("%s%d", prefix, i)
+			}
+			out = append(out, param{name: name, typ: typStr, variadic: variadic})
+			i++
+		}
+	}
+	return out
+}
+
+func exprString(fset *token.FileSet, e ast.Expr) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, fset, e)
+	return buf.String()
+}
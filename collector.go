@@ -0,0 +1,183 @@
+package trace
+
+import (
+	"sync"
+)
+
+// Collector owns a process-level memory budget shared by every trace
+// recording under it, so tracing can stay enabled on every request of a
+// busy service without unbounded growth. While usage is inside the
+// budget, traces record normally; once the budget is exceeded, new
+// traces are sampled — one in SampleKeep is recorded, the rest become
+// inert — and steps of already-recording traces are shed. A trace
+// releases its share of the budget when it reaches a terminal state, so
+// pressure subsides as computations finish.
+type Collector struct {
+	budget     int64
+	sampleKeep uint64
+
+	mu        sync.Mutex
+	used      int64
+	active    int
+	sampleSeq uint64
+	dropped   uint64
+	shed      uint64
+}
+
+// CollectorOption configures a Collector.
+type CollectorOption func(*Collector)
+
+// WithSampleKeep sets how many traces are dropped per trace kept while
+// the collector is over budget; the default keeps one in a hundred.
+func WithSampleKeep(oneIn int) CollectorOption {
+	return func(c *Collector) {
+		if oneIn > 0 {
+			c.sampleKeep = uint64(oneIn)
+		}
+	}
+}
+
+// NewCollector returns a collector with a memory budget in bytes.
+func NewCollector(budget int64, opts ...CollectorOption) *Collector {
+	c := &Collector{budget: budget, sampleKeep: 100}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// New begins a trace under the collector's budget. Over budget, it
+// applies sampling: the traces it sheds come back inert — already
+// aborted, so every AddStep and Complete is a no-op — and callers need
+// no conditional around their instrumentation.
+func (c *Collector) New(name string, inputs map[string]any, opts ...Option) *Trace {
+	c.mu.Lock()
+	over := c.used >= c.budget
+	if over {
+		c.sampleSeq++
+		if c.sampleSeq%c.sampleKeep != 0 {
+			c.dropped++
+			c.mu.Unlock()
+			tr := New(name, inputs, opts...)
+			tr.Status = StatusAborted
+			tr.Metadata["collector.sampled_out"] = "true"
+			return tr
+		}
+	}
+	c.active++
+	c.mu.Unlock()
+
+	tr := New(name, inputs, opts...)
+	tr.collector = c
+	cost := traceBaseCost(tr)
+	tr.memCost = cost
+	c.mu.Lock()
+	c.used += cost
+	c.mu.Unlock()
+	return tr
+}
+
+// CollectorStats is a snapshot of the collector's gauges, for export to
+// whatever metrics system the service uses.
+type CollectorStats struct {
+	// BudgetBytes and UsedBytes gauge the memory budget and the
+	// estimated bytes held by active traces.
+	BudgetBytes int64 `json:"budget_bytes"`
+	UsedBytes   int64 `json:"used_bytes"`
+	// ActiveTraces counts traces still recording under the collector.
+	ActiveTraces int `json:"active_traces"`
+	// DroppedTraces counts traces sampled out at creation; ShedSteps
+	// counts steps refused from recording traces while over budget.
+	DroppedTraces uint64 `json:"dropped_traces"`
+	ShedSteps     uint64 `json:"shed_steps"`
+}
+
+// Stats returns the collector's current gauges.
+func (c *Collector) Stats() CollectorStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CollectorStats{
+		BudgetBytes:   c.budget,
+		UsedBytes:     c.used,
+		ActiveTraces:  c.active,
+		DroppedTraces: c.dropped,
+		ShedSteps:     c.shed,
+	}
+}
+
+// admitStep reports whether a step may be recorded and shed-counts the
+// refusals.
+func (c *Collector) admitStep() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.used >= c.budget {
+		c.shed++
+		return false
+	}
+	return true
+}
+
+// admitCollectorLocked applies the collector's backpressure to one step
+// of a recording trace, marking the trace as shed once so readers know
+// the record is incomplete.
+func (tr *Trace) admitCollectorLocked() bool {
+	if tr.collector == nil {
+		return true
+	}
+	if tr.collector.admitStep() {
+		return true
+	}
+	tr.Metadata["collector.shed"] = "true"
+	return false
+}
+
+// accountStepLocked charges a recorded step against the collector
+// budget.
+func (tr *Trace) accountStepLocked(st *Step) {
+	if tr.collector == nil {
+		return
+	}
+	cost := stepCost(st)
+	tr.memCost += cost
+	tr.collector.mu.Lock()
+	tr.collector.used += cost
+	tr.collector.mu.Unlock()
+}
+
+// releaseCollectorLocked returns the trace's share of the budget once
+// it reaches a terminal state.
+func (tr *Trace) releaseCollectorLocked() {
+	if tr.collector == nil {
+		return
+	}
+	c := tr.collector
+	c.mu.Lock()
+	c.used -= tr.memCost
+	c.active--
+	c.mu.Unlock()
+	tr.collector = nil
+	tr.memCost = 0
+}
+
+// traceBaseCost and stepCost estimate memory held by a record from the
+// canonical size of its values plus a fixed structural overhead. The
+// estimates track growth, not exact heap bytes; the budget is a
+// pressure valve, not an allocator.
+func traceBaseCost(tr *Trace) int64 {
+	cost := int64(256) + int64(len(tr.ID)+len(tr.Name))
+	for k, v := range tr.Inputs {
+		cost += int64(len(k)) + int64(len(CanonicalBytes(v)))
+	}
+	return cost
+}
+
+func stepCost(st *Step) int64 {
+	cost := int64(128) + int64(len(st.Operation)+len(st.Description)+len(st.Err))
+	for k, v := range st.Inputs {
+		cost += int64(len(k)) + int64(len(CanonicalBytes(v)))
+	}
+	for k, v := range st.Outputs {
+		cost += int64(len(k)) + int64(len(CanonicalBytes(v)))
+	}
+	return cost
+}
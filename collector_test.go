@@ -0,0 +1,62 @@
+package trace
+
+import "testing"
+
+func TestCollectorRecordsInsideBudget(t *testing.T) {
+	c := NewCollector(1 << 20)
+	tr := c.New("job", map[string]any{"x": 1})
+	tr.AddStep("a", nil, nil)
+	st := c.Stats()
+	if st.ActiveTraces != 1 || st.UsedBytes <= 0 || st.DroppedTraces != 0 {
+		t.Fatalf("stats while recording: %+v", st)
+	}
+	tr.Complete(nil)
+	st = c.Stats()
+	if st.ActiveTraces != 0 || st.UsedBytes != 0 {
+		t.Fatalf("budget not released at terminal state: %+v", st)
+	}
+}
+
+func TestCollectorSamplesOverBudget(t *testing.T) {
+	c := NewCollector(1, WithSampleKeep(3)) // over budget immediately
+	first := c.New("held", nil)             // charges the budget and stays active
+	defer first.Complete(nil)
+
+	var kept, dropped int
+	for i := 0; i < 9; i++ {
+		tr := c.New("job", nil)
+		if tr.Status == StatusAborted {
+			dropped++
+			if tr.Metadata["collector.sampled_out"] != "true" {
+				t.Fatal("sampled-out trace not marked")
+			}
+			if st := tr.AddStep("a", nil, nil); st != nil {
+				t.Fatal("sampled-out trace recorded a step")
+			}
+		} else {
+			kept++
+			tr.Complete(nil)
+		}
+	}
+	if kept != 3 || dropped != 6 {
+		t.Fatalf("kept %d dropped %d, want one in three kept", kept, dropped)
+	}
+	if st := c.Stats(); st.DroppedTraces != 6 {
+		t.Fatalf("DroppedTraces %d", st.DroppedTraces)
+	}
+}
+
+func TestCollectorShedsStepsOverBudget(t *testing.T) {
+	c := NewCollector(1, WithSampleKeep(1)) // keep every trace, but shed steps
+	tr := c.New("job", nil)
+	if st := tr.AddStep("a", nil, nil); st != nil {
+		t.Fatal("step admitted while over budget")
+	}
+	if tr.Metadata["collector.shed"] != "true" {
+		t.Fatal("shed trace not marked incomplete")
+	}
+	tr.Complete(nil)
+	if st := c.Stats(); st.ShedSteps == 0 {
+		t.Fatalf("ShedSteps %d", st.ShedSteps)
+	}
+}
@@ -0,0 +1,185 @@
+// Package columnar stores a trace's steps as parallel arrays with interned
+// operations, keys, and values. For traces with millions of steps, the
+// per-step map[string]any layout is memory-hostile; the columnar layout cuts
+// memory by sharing repeated strings and values, and serializes faster
+// because the encoder walks flat arrays. It exposes the same iteration API
+// shape as Trace, materializing individual steps on demand.
+package columnar
+
+import (
+	"sort"
+	"time"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Columns is the columnar form of one trace's step sequence.
+type Columns struct {
+	TraceID string `json:"trace_id"`
+	Name    string `json:"name"`
+
+	// Per-step parallel arrays, indexed by step.
+	OpIndex   []int32  `json:"op_index"`
+	Times     []int64  `json:"times"` // UnixNano
+	Durations []int64  `json:"durations"`
+	Hashes    []string `json:"hashes"`
+
+	// Sparse per-step fields.
+	Descriptions map[int]string `json:"descriptions,omitempty"`
+	Errs         map[int]string `json:"errors,omitempty"`
+	Refs         map[int][]int  `json:"refs,omitempty"`
+
+	// Input/output key-value pairs: step i owns pairs
+	// [InOffsets[i], InOffsets[i+1]) of InKeys/InVals, likewise for outputs.
+	InOffsets  []int32 `json:"in_offsets"`
+	InKeys     []int32 `json:"in_keys"`
+	InVals     []int32 `json:"in_vals"`
+	OutOffsets []int32 `json:"out_offsets"`
+	OutKeys    []int32 `json:"out_keys"`
+	OutVals    []int32 `json:"out_vals"`
+
+	// Interning tables.
+	Ops    []string `json:"ops"`
+	Keys   []string `json:"keys"`
+	Values []any    `json:"values"`
+
+	opIDs    map[string]int32
+	keyIDs   map[string]int32
+	valueIDs map[string]int32 // by value hash
+}
+
+// FromTrace converts a trace's steps into columnar form.
+func FromTrace(tr *trace.Trace) *Columns {
+	c := &Columns{
+		TraceID:      tr.ID,
+		Name:         tr.Name,
+		Descriptions: map[int]string{},
+		Errs:         map[int]string{},
+		Refs:         map[int][]int{},
+		InOffsets:    []int32{0},
+		OutOffsets:   []int32{0},
+		opIDs:        map[string]int32{},
+		keyIDs:       map[string]int32{},
+		valueIDs:     map[string]int32{},
+	}
+	tr.AllSteps()(func(i int, st *trace.Step) bool {
+		c.append(st)
+		return true
+	})
+	return c
+}
+
+func (c *Columns) append(st *trace.Step) {
+	i := len(c.OpIndex)
+	c.OpIndex = append(c.OpIndex, c.internOp(st.Operation))
+	c.Times = append(c.Times, st.Time.UnixNano())
+	c.Durations = append(c.Durations, int64(st.Duration))
+	c.Hashes = append(c.Hashes, st.Hash)
+	if st.Description != "" {
+		c.Descriptions[i] = st.Description
+	}
+	if st.Err != "" {
+		c.Errs[i] = st.Err
+	}
+	if len(st.Refs) > 0 {
+		c.Refs[i] = st.Refs
+	}
+	for _, k := range sortedKeys(st.Inputs) {
+		c.InKeys = append(c.InKeys, c.internKey(k))
+		c.InVals = append(c.InVals, c.internValue(st.Inputs[k]))
+	}
+	c.InOffsets = append(c.InOffsets, int32(len(c.InKeys)))
+	for _, k := range sortedKeys(st.Outputs) {
+		c.OutKeys = append(c.OutKeys, c.internKey(k))
+		c.OutVals = append(c.OutVals, c.internValue(st.Outputs[k]))
+	}
+	c.OutOffsets = append(c.OutOffsets, int32(len(c.OutKeys)))
+}
+
+// Len returns the number of steps held.
+func (c *Columns) Len() int {
+	return len(c.OpIndex)
+}
+
+// Operation returns step i's operation without materializing the step.
+func (c *Columns) Operation(i int) string {
+	return c.Ops[c.OpIndex[i]]
+}
+
+// Step materializes step i in the ordinary row form.
+func (c *Columns) Step(i int) *trace.Step {
+	st := &trace.Step{
+		Index:       i,
+		Operation:   c.Operation(i),
+		Description: c.Descriptions[i],
+		Err:         c.Errs[i],
+		Refs:        c.Refs[i],
+		Time:        time.Unix(0, c.Times[i]),
+		Duration:    time.Duration(c.Durations[i]),
+		Hash:        c.Hashes[i],
+	}
+	if n := c.InOffsets[i+1] - c.InOffsets[i]; n > 0 {
+		st.Inputs = make(map[string]any, n)
+		for p := c.InOffsets[i]; p < c.InOffsets[i+1]; p++ {
+			st.Inputs[c.Keys[c.InKeys[p]]] = c.Values[c.InVals[p]]
+		}
+	}
+	if n := c.OutOffsets[i+1] - c.OutOffsets[i]; n > 0 {
+		st.Outputs = make(map[string]any, n)
+		for p := c.OutOffsets[i]; p < c.OutOffsets[i+1]; p++ {
+			st.Outputs[c.Keys[c.OutKeys[p]]] = c.Values[c.OutVals[p]]
+		}
+	}
+	return st
+}
+
+// AllSteps mirrors Trace.AllSteps over the columnar layout.
+func (c *Columns) AllSteps() trace.Seq2[int, *trace.Step] {
+	return func(yield func(int, *trace.Step) bool) {
+		for i := 0; i < c.Len(); i++ {
+			if !yield(i, c.Step(i)) {
+				return
+			}
+		}
+	}
+}
+
+func (c *Columns) internOp(op string) int32 {
+	if id, ok := c.opIDs[op]; ok {
+		return id
+	}
+	id := int32(len(c.Ops))
+	c.Ops = append(c.Ops, op)
+	c.opIDs[op] = id
+	return id
+}
+
+func (c *Columns) internKey(k string) int32 {
+	if id, ok := c.keyIDs[k]; ok {
+		return id
+	}
+	id := int32(len(c.Keys))
+	c.Keys = append(c.Keys, k)
+	c.keyIDs[k] = id
+	return id
+}
+
+func (c *Columns) internValue(v any) int32 {
+	hash := trace.HashValue(v)
+	if id, ok := c.valueIDs[hash]; ok {
+		return id
+	}
+	id := int32(len(c.Values))
+	c.Values = append(c.Values, v)
+	c.valueIDs[hash] = id
+	return id
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
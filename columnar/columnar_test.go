@@ -0,0 +1,88 @@
+package columnar
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+func repeated() *trace.Trace {
+	tr := trace.New("batch", nil)
+	for i := 0; i < 3; i++ {
+		tr.AddStep("multiply",
+			map[string]any{"rate": 0.07},
+			map[string]any{"tax": 7.0},
+			trace.WithDescription("per-item tax"))
+	}
+	tr.AddStep("check", nil, nil, trace.WithStepError(errors.New("limit reached")))
+	tr.Complete(21.0)
+	return tr
+}
+
+func TestStepRoundTrip(t *testing.T) {
+	tr := repeated()
+	c := FromTrace(tr)
+	if c.Len() != len(tr.Steps) || c.TraceID != tr.ID {
+		t.Fatalf("len %d id %s", c.Len(), c.TraceID)
+	}
+	for i, want := range tr.Steps {
+		got := c.Step(i)
+		if got.Operation != want.Operation || got.Hash != want.Hash || got.Err != want.Err {
+			t.Fatalf("step %d: %+v", i, got)
+		}
+		if !reflect.DeepEqual(got.Inputs, want.Inputs) || !reflect.DeepEqual(got.Outputs, want.Outputs) {
+			t.Fatalf("step %d maps: %+v", i, got)
+		}
+		if got.Description != want.Description || !got.Time.Equal(want.Time) {
+			t.Fatalf("step %d detail: %+v", i, got)
+		}
+	}
+}
+
+func TestInterningSharesRepeatedStrings(t *testing.T) {
+	c := FromTrace(repeated())
+	// Three identical multiply steps share one op, one key, one value
+	// per column.
+	if len(c.Ops) != 2 {
+		t.Fatalf("ops %v", c.Ops)
+	}
+	if len(c.Keys) != 2 || len(c.Values) != 2 {
+		t.Fatalf("keys %v values %v", c.Keys, c.Values)
+	}
+}
+
+func TestAllStepsMirrorsTrace(t *testing.T) {
+	c := FromTrace(repeated())
+	visited := 0
+	c.AllSteps()(func(i int, st *trace.Step) bool {
+		if st.Index != i {
+			t.Fatalf("index %d at %d", st.Index, i)
+		}
+		visited++
+		return visited < 2 // early stop
+	})
+	if visited != 2 {
+		t.Fatalf("visited %d", visited)
+	}
+}
+
+func TestColumnsSerializeAndBack(t *testing.T) {
+	c := FromTrace(repeated())
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Columns
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Len() != c.Len() || got.Operation(0) != c.Operation(0) {
+		t.Fatalf("round trip %+v", got)
+	}
+	if got.Step(3).Err != "limit reached" {
+		t.Fatalf("error column %+v", got.Step(3))
+	}
+}
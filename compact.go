@@ -0,0 +1,179 @@
+package trace
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Compact returns a copy of the trace in which runs of structurally
+// identical consecutive steps — same operation, same input and output shape,
+// same origin — are coalesced into a single step with a repetition count and
+// per-key lists of the run's values. Loop-heavy traces shrink by orders of
+// magnitude while remaining explainable. Chain hashes are recomputed for the
+// compacted step sequence; the original trace is left untouched.
+func Compact(tr *Trace) *Trace {
+	out := shallowCopy(tr)
+	remap := map[int]int{} // original step index -> compacted step index
+
+	steps := tr.Steps
+	for i := 0; i < len(steps); {
+		j := i + 1
+		key := structuralKey(steps[i])
+		for j < len(steps) && structuralKey(steps[j]) == key {
+			j++
+		}
+		run := steps[i:j]
+		var st *Step
+		if len(run) == 1 {
+			st = copyStep(run[0])
+		} else {
+			st = mergeRun(run)
+		}
+		st.Index = len(out.Steps)
+		st.Refs = remapRefs(run, remap)
+		prev := ""
+		if n := len(out.Steps); n > 0 {
+			prev = out.Steps[n-1].Hash
+		}
+		st.Hash = st.chainHash(prev)
+		out.Steps = append(out.Steps, st)
+		for _, orig := range run {
+			remap[orig.Index] = st.Index
+		}
+		i = j
+	}
+	if out.Status != StatusRecording {
+		out.sealChainLocked()
+	}
+	return out
+}
+
+// structuralKey fingerprints the shape of a step: what it did, which named
+// values it touched, which sub-trace it embeds, how it is graded, and who
+// recorded it — ignoring the values themselves. Severity and author are part of the key so a warning
+// never coalesces into an info-graded step and a run never blends work from
+// two recorders.
+func structuralKey(st *Step) string {
+	var b strings.Builder
+	b.WriteString(st.Operation)
+	b.WriteString("|")
+	b.WriteString(st.Origin)
+	b.WriteString("|")
+	b.WriteString(string(st.Severity))
+	b.WriteString("|")
+	b.WriteString(st.SubTraceRef)
+	if st.Author != nil {
+		fmt.Fprintf(&b, "|by:%s/%s/%s", st.Author.Service, st.Author.Principal, st.Author.KeyID)
+	}
+	for _, k := range sortedKeys(st.Inputs) {
+		b.WriteString("|in:" + k)
+	}
+	for _, k := range sortedKeys(st.Outputs) {
+		b.WriteString("|out:" + k)
+	}
+	return b.String()
+}
+
+// mergeRun coalesces a run of structurally identical steps into one step
+// whose input and output keys each carry the list of values seen across the
+// run, in order. Severity and author — identical across the run by
+// construction of the key — carry over, and the run's notes are
+// concatenated.
+func mergeRun(run []*Step) *Step {
+	first := run[0]
+	st := &Step{
+		Operation:   first.Operation,
+		Description: first.Description,
+		Origin:      first.Origin,
+		Severity:    first.Severity,
+		Author:      first.Author,
+		Repeat:      len(run),
+		Inputs:      map[string]any{},
+		Outputs:     map[string]any{},
+		Time:        first.Time,
+	}
+	for _, k := range sortedKeys(first.Inputs) {
+		values := make([]any, len(run))
+		for i, r := range run {
+			values[i] = r.Inputs[k]
+		}
+		st.Inputs[k] = values
+	}
+	for _, k := range sortedKeys(first.Outputs) {
+		values := make([]any, len(run))
+		for i, r := range run {
+			values[i] = r.Outputs[k]
+		}
+		st.Outputs[k] = values
+	}
+	for _, r := range run {
+		st.Duration += r.Duration
+		st.Notes = append(st.Notes, r.Notes...)
+		if r.Err != "" && st.Err == "" {
+			st.Err = r.Err
+		}
+	}
+	return st
+}
+
+// remapRefs collects the run's references to earlier steps, translated into
+// compacted indexes, deduplicated, and sorted.
+func remapRefs(run []*Step, remap map[int]int) []int {
+	seen := map[int]bool{}
+	var refs []int
+	for _, r := range run {
+		for _, ref := range r.Refs {
+			mapped, ok := remap[ref]
+			if !ok || seen[mapped] {
+				continue
+			}
+			seen[mapped] = true
+			refs = append(refs, mapped)
+		}
+	}
+	sort.Ints(refs)
+	return refs
+}
+
+// shallowCopy duplicates a trace's identity, inputs, metadata, input tags,
+// sub-trace table, and outcome, leaving Steps empty for the caller to fill. Sub-traces
+// are immutable once embedded, so the table entries are shared, not copied.
+func shallowCopy(tr *Trace) *Trace {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	out := &Trace{
+		ID:       tr.ID,
+		Name:     tr.Name,
+		Inputs:   tr.Inputs,
+		Metadata: map[string]string{},
+		Result:   tr.Result,
+		Status:   tr.Status,
+		Error:    tr.Error,
+		Started:  tr.Started,
+		Finished: tr.Finished,
+	}
+	for k, v := range tr.Metadata {
+		out.Metadata[k] = v
+	}
+	if len(tr.SubTraces) > 0 {
+		out.SubTraces = make(map[string]*Trace, len(tr.SubTraces))
+		for hash, sub := range tr.SubTraces {
+			out.SubTraces[hash] = sub
+		}
+	}
+	if len(tr.InputTags) > 0 {
+		out.InputTags = make(map[string][]string, len(tr.InputTags))
+		for input, tags := range tr.InputTags {
+			out.InputTags[input] = append([]string(nil), tags...)
+		}
+	}
+	return out
+}
+
+// copyStep duplicates one step, leaving Index, Refs, and Hash for the caller
+// to rewrite.
+func copyStep(st *Step) *Step {
+	dup := *st
+	return &dup
+}
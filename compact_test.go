@@ -0,0 +1,124 @@
+package trace
+
+import "testing"
+
+func TestCompactCoalescesRuns(t *testing.T) {
+	tr := New("loop", nil)
+	tr.AddStep("init", nil, map[string]any{"acc": 0})
+	for i := 1; i <= 4; i++ {
+		tr.AddStep("add", map[string]any{"i": i}, map[string]any{"acc": i})
+	}
+	tr.AddStep("finish", map[string]any{"acc": 4}, nil, WithRefs(2))
+	tr.Complete(4)
+
+	out := Compact(tr)
+	if len(out.Steps) != 3 {
+		t.Fatalf("%d compacted steps: %+v", len(out.Steps), out.Steps)
+	}
+	run := out.Steps[1]
+	if run.Operation != "add" || run.Repeat != 4 {
+		t.Fatalf("merged run %+v", run)
+	}
+	values, _ := run.Inputs["i"].([]any)
+	if len(values) != 4 || values[0] != 1 || values[3] != 4 {
+		t.Fatalf("run values %v", run.Inputs["i"])
+	}
+	// The finish step's reference into the run is remapped.
+	if len(out.Steps[2].Refs) != 1 || out.Steps[2].Refs[0] != 1 {
+		t.Fatalf("refs %v", out.Steps[2].Refs)
+	}
+	// Chain hashes are recomputed and resealed over the compacted sequence.
+	if err := out.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if out.FinalHash != out.Steps[2].Hash {
+		t.Fatal("compacted trace not resealed")
+	}
+	// The original is untouched.
+	if len(tr.Steps) != 6 {
+		t.Fatalf("Compact mutated the original: %d steps", len(tr.Steps))
+	}
+}
+
+func TestCompactPreservesSeverityAuthorAndNotes(t *testing.T) {
+	author := &Identity{Service: "pricer", Principal: "batch"}
+	tr := New("loop", nil)
+	for i := 0; i < 2; i++ {
+		st := tr.AddStep("clamp", map[string]any{"i": i}, nil, Warn(), WithAuthor(*author))
+		st.WithNote("reviewer", "clamped")
+	}
+	tr.Complete(nil)
+
+	out := Compact(tr)
+	if len(out.Steps) != 1 || out.Steps[0].Repeat != 2 {
+		t.Fatalf("steps %+v", out.Steps)
+	}
+	merged := out.Steps[0]
+	if merged.Severity != SeverityWarning || len(out.Warnings()) != 1 {
+		t.Fatalf("severity lost: %+v", merged)
+	}
+	if merged.Author == nil || merged.Author.Service != "pricer" {
+		t.Fatalf("author lost: %+v", merged)
+	}
+	if len(merged.Notes) != 2 {
+		t.Fatalf("notes lost: %+v", merged.Notes)
+	}
+	if err := out.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCompactKeepsDifferentlyGradedStepsApart(t *testing.T) {
+	tr := New("t", nil)
+	tr.AddStep("op", map[string]any{"a": 1}, nil)
+	tr.AddStep("op", map[string]any{"a": 2}, nil, Warn())
+	tr.Complete(nil)
+	out := Compact(tr)
+	if len(out.Steps) != 2 {
+		t.Fatalf("warning merged into an info step: %+v", out.Steps)
+	}
+}
+
+func TestCompactKeepsSubTraceReferences(t *testing.T) {
+	embed := func(tr *Trace, n int) {
+		sub := New("inner", map[string]any{"n": n})
+		sub.Complete(n)
+		tr.AddSubTrace("embed", sub)
+	}
+	tr := New("outer", nil)
+	embed(tr, 1)
+	embed(tr, 2)
+	tr.Complete(nil)
+
+	out := Compact(tr)
+	// Two same-shaped embed steps point at different sub-computations;
+	// neither the steps nor their references may collapse.
+	if len(out.Steps) != 2 {
+		t.Fatalf("embed steps merged: %+v", out.Steps)
+	}
+	for i, st := range out.Steps {
+		sub, ok := out.SubTrace(st.SubTraceRef)
+		if !ok {
+			t.Fatalf("step %d: dangling sub-trace ref %s", i, st.SubTraceRef)
+		}
+		if sub.Result != i+1 {
+			t.Fatalf("step %d resolved to %v", i, sub.Result)
+		}
+	}
+
+	normalized := Normalize(tr)
+	if _, ok := normalized.SubTrace(normalized.Steps[0].SubTraceRef); !ok {
+		t.Fatal("Normalize dropped the sub-trace table")
+	}
+}
+
+func TestCompactKeepsDistinctShapesApart(t *testing.T) {
+	tr := New("t", nil)
+	tr.AddStep("op", map[string]any{"a": 1}, nil)
+	tr.AddStep("op", map[string]any{"b": 1}, nil) // different input shape
+	tr.Complete(nil)
+	out := Compact(tr)
+	if len(out.Steps) != 2 {
+		t.Fatalf("steps with different shapes merged: %+v", out.Steps)
+	}
+}
@@ -0,0 +1,19 @@
+package trace
+
+import "context"
+
+type ctxKey struct{}
+
+// NewContext returns a context carrying the trace, so instrumentation layers
+// (database wrappers, HTTP clients) can record steps into the computation
+// that is currently running.
+func NewContext(ctx context.Context, tr *Trace) context.Context {
+	return context.WithValue(ctx, ctxKey{}, tr)
+}
+
+// FromContext returns the trace carried by the context, or nil if the context
+// is not part of a traced computation.
+func FromContext(ctx context.Context) *Trace {
+	tr, _ := ctx.Value(ctxKey{}).(*Trace)
+	return tr
+}
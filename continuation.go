@@ -0,0 +1,70 @@
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Continuation is a serializable snapshot of a still-recording trace:
+// identity, inputs, the steps so far, and the hash at the end of the
+// chain. It carries everything needed to keep recording in another
+// process, so computations that span restarts or job-queue hops still
+// produce one coherent trace.
+type Continuation struct {
+	FormatVersion string          `json:"format_version"`
+	LastHash      string          `json:"last_hash"`
+	Trace         json.RawMessage `json:"trace"`
+}
+
+// Suspend snapshots the recording trace as a continuation. The trace
+// itself keeps recording; callers hopping processes serialize the
+// continuation, discard the original, and Resume on the other side.
+func (tr *Trace) Suspend() (*Continuation, error) {
+	tr.mu.Lock()
+	status := tr.Status
+	last := ""
+	if n := len(tr.Steps); n > 0 {
+		last = tr.Steps[n-1].Hash
+	}
+	tr.mu.Unlock()
+	if status != StatusRecording {
+		return nil, fmt.Errorf("trace %s: suspend: trace already %s", tr.ID, status)
+	}
+	data, err := tr.ToJSON()
+	if err != nil {
+		return nil, fmt.Errorf("trace %s: suspend: %w", tr.ID, err)
+	}
+	return &Continuation{
+		FormatVersion: FormatVersion,
+		LastHash:      last,
+		Trace:         data,
+	}, nil
+}
+
+// Resume reconstructs a recording trace from a continuation, verifying
+// the step hash chain and that it ends at the continuation's recorded
+// hash, so steps lost or edited in transit are detected before any new
+// ones are appended.
+func Resume(cont *Continuation) (*Trace, error) {
+	if cont.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("trace: resume: format version %q, want %q", cont.FormatVersion, FormatVersion)
+	}
+	tr, err := FromJSON(cont.Trace)
+	if err != nil {
+		return nil, fmt.Errorf("trace: resume: %w", err)
+	}
+	if tr.Status != StatusRecording {
+		return nil, fmt.Errorf("trace %s: resume: trace already %s", tr.ID, tr.Status)
+	}
+	if err := tr.Verify(); err != nil {
+		return nil, fmt.Errorf("trace: resume: %w", err)
+	}
+	last := ""
+	if n := len(tr.Steps); n > 0 {
+		last = tr.Steps[n-1].Hash
+	}
+	if last != cont.LastHash {
+		return nil, fmt.Errorf("trace %s: resume: chain ends at %s, continuation recorded %s", tr.ID, last, cont.LastHash)
+	}
+	return tr, nil
+}
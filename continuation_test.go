@@ -0,0 +1,65 @@
+package trace
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSuspendResumeKeepsOneCoherentTrace(t *testing.T) {
+	tr := New("job", map[string]any{"n": 2})
+	tr.AddStep("phase1", nil, map[string]any{"acc": 1})
+
+	cont, err := tr.Suspend()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(cont)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The other side of the hop.
+	var wire Continuation
+	if err := json.Unmarshal(data, &wire); err != nil {
+		t.Fatal(err)
+	}
+	resumed, err := Resume(&wire)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumed.ID != tr.ID || len(resumed.Steps) != 1 {
+		t.Fatalf("resumed trace %s with %d steps", resumed.ID, len(resumed.Steps))
+	}
+	resumed.AddStep("phase2", map[string]any{"acc": 1}, map[string]any{"acc": 2})
+	resumed.Complete(2)
+	if err := resumed.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResumeDetectsStepsLostInTransit(t *testing.T) {
+	tr := New("job", nil)
+	tr.AddStep("a", nil, nil)
+	tr.AddStep("b", nil, nil)
+	cont, err := tr.Suspend()
+	if err != nil {
+		t.Fatal(err)
+	}
+	truncated, err := FromJSON(cont.Trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	truncated.Steps = truncated.Steps[:1]
+	cont.Trace, _ = truncated.ToJSON()
+	if _, err := Resume(cont); err == nil {
+		t.Fatal("resumed a continuation missing its last step")
+	}
+}
+
+func TestSuspendRejectsTerminalTraces(t *testing.T) {
+	tr := New("job", nil)
+	tr.Complete(nil)
+	if _, err := tr.Suspend(); err == nil {
+		t.Fatal("suspended a completed trace")
+	}
+}
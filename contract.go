@@ -0,0 +1,77 @@
+package trace
+
+import "fmt"
+
+// Condition is one domain rule attached to an operation name — "divide:
+// denominator != 0", "sqrt: input >= 0". The check returns nil when the
+// rule holds and a descriptive error when it does not.
+type Condition struct {
+	Name  string
+	Check func(inputs, outputs map[string]any) error
+}
+
+// Contracts registers pre- and postconditions per operation name.
+// Preconditions see a step's inputs, postconditions its inputs and
+// outputs; both are evaluated as the step is recorded and embedded as
+// assertion steps, turning implicit domain rules into explicit, checkable
+// trace content.
+type Contracts struct {
+	pre  map[string][]Condition
+	post map[string][]Condition
+}
+
+// NewContracts returns an empty contract registry.
+func NewContracts() *Contracts {
+	return &Contracts{pre: map[string][]Condition{}, post: map[string][]Condition{}}
+}
+
+// Pre registers a precondition on the operation; outputs are not yet
+// available and arrive nil.
+func (c *Contracts) Pre(operation, name string, check func(inputs map[string]any) error) *Contracts {
+	c.pre[operation] = append(c.pre[operation], Condition{
+		Name: name,
+		Check: func(inputs, _ map[string]any) error {
+			return check(inputs)
+		},
+	})
+	return c
+}
+
+// Post registers a postcondition on the operation.
+func (c *Contracts) Post(operation, name string, check func(inputs, outputs map[string]any) error) *Contracts {
+	c.post[operation] = append(c.post[operation], Condition{Name: name, Check: check})
+	return c
+}
+
+// WithContracts attaches the registry to a new trace; every AddStep then
+// evaluates the conditions registered for its operation.
+func WithContracts(c *Contracts) Option {
+	return func(tr *Trace) { tr.contracts = c }
+}
+
+// checkContractsLocked evaluates the conditions for a just-recorded step
+// and embeds each outcome as an assertion step, failed ones with the
+// rule's error.
+func (tr *Trace) checkContractsLocked(st *Step) {
+	if tr.contracts == nil {
+		return
+	}
+	evaluate := func(kind string, conds []Condition) {
+		for _, cond := range conds {
+			err := cond.Check(st.Inputs, st.Outputs)
+			name := fmt.Sprintf("%s:%s:%s", kind, st.Operation, cond.Name)
+			opts := []StepOption{WithRefs(st.Index)}
+			detail := ""
+			if err != nil {
+				detail = err.Error()
+				opts = append(opts, WithStepError(fmt.Errorf("assertion %s failed", name)), WithDescription(detail))
+			}
+			tr.addStepLocked("assert",
+				map[string]any{"name": name},
+				map[string]any{"passed": err == nil},
+				opts...)
+		}
+	}
+	evaluate("pre", tr.contracts.pre[st.Operation])
+	evaluate("post", tr.contracts.post[st.Operation])
+}
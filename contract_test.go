@@ -0,0 +1,45 @@
+package trace
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestContractsEmbedAssertionOutcomes(t *testing.T) {
+	c := NewContracts().
+		Pre("divide", "denominator non-zero", func(inputs map[string]any) error {
+			if inputs["den"] == 0 {
+				return fmt.Errorf("denominator is zero")
+			}
+			return nil
+		}).
+		Post("divide", "quotient finite", func(inputs, outputs map[string]any) error {
+			if _, ok := outputs["q"]; !ok {
+				return fmt.Errorf("no quotient produced")
+			}
+			return nil
+		})
+
+	tr := New("t", nil, WithContracts(c))
+	tr.AddStep("divide", map[string]any{"num": 6, "den": 2}, map[string]any{"q": 3})
+	if failed := tr.FailedAssertions(); len(failed) != 0 {
+		t.Fatalf("healthy step produced failures: %+v", failed)
+	}
+	// One divide step plus two passed assertion steps.
+	if len(tr.Steps) != 3 {
+		t.Fatalf("%d steps", len(tr.Steps))
+	}
+
+	tr.AddStep("divide", map[string]any{"num": 6, "den": 0}, nil)
+	failed := tr.FailedAssertions()
+	if len(failed) != 2 {
+		t.Fatalf("%d failed assertions, want pre and post: %+v", len(failed), failed)
+	}
+	if name, _ := failed[0].Inputs["name"].(string); name != "pre:divide:denominator non-zero" {
+		t.Fatalf("assertion name %q", name)
+	}
+	tr.Complete(nil)
+	if err := tr.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
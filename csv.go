@@ -0,0 +1,84 @@
+package trace
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// csvHeader is the flattened step-row layout shared by the CSV and TSV
+// exports and the store-level bulk variant.
+var csvHeader = []string{
+	"trace_id", "trace_name", "step_index", "operation", "description",
+	"inputs", "outputs", "error", "time", "duration",
+}
+
+// ToCSV writes the trace as flattened step rows — one line per step with
+// inputs and outputs as "key=value; ..." cells — for audit consumers who
+// want the computation in a spreadsheet.
+func (tr *Trace) ToCSV(w io.Writer) error {
+	return tr.writeRows(w, ',')
+}
+
+// ToTSV is ToCSV with tab separators.
+func (tr *Trace) ToTSV(w io.Writer) error {
+	return tr.writeRows(w, '\t')
+}
+
+func (tr *Trace) writeRows(w io.Writer, comma rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	if err := tr.writeStepRows(cw); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteCSVRows appends the trace's step rows (no header) to an open CSV
+// writer; store-level bulk exports use it to emit one sheet for a whole
+// archive.
+func (tr *Trace) WriteCSVRows(cw *csv.Writer) error {
+	return tr.writeStepRows(cw)
+}
+
+func (tr *Trace) writeStepRows(cw *csv.Writer) error {
+	var err error
+	tr.AllSteps()(func(_ int, st *Step) bool {
+		row := []string{
+			tr.ID,
+			tr.Name,
+			fmt.Sprintf("%d", st.Index),
+			st.Operation,
+			st.Description,
+			flattenValues(st.Inputs),
+			flattenValues(st.Outputs),
+			st.Err,
+			st.Time.Format(time.RFC3339Nano),
+			st.Duration.String(),
+		}
+		err = cw.Write(row)
+		return err == nil
+	})
+	return err
+}
+
+// CSVHeader returns the column layout of the step-row exports.
+func CSVHeader() []string {
+	header := make([]string, len(csvHeader))
+	copy(header, csvHeader)
+	return header
+}
+
+func flattenValues(m map[string]any) string {
+	parts := make([]string, 0, len(m))
+	for _, k := range sortedKeys(m) {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, m[k]))
+	}
+	return strings.Join(parts, "; ")
+}
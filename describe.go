@@ -0,0 +1,114 @@
+package trace
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Description templates turn undescribed steps into readable report
+// lines. A template is registered per operation and references step
+// values by name in braces; each placeholder expands to "name (value)",
+// looked up in the step's inputs first and outputs second:
+//
+//	trace.RegisterDescription("divide",
+//		"Dividing {revenue} by {units} to get {unit_price}")
+//
+// renders an undescribed divide step as
+// "Dividing revenue (1200) by units (40) to get unit_price (30)".
+// Synthesis happens at render time only — a step's recorded (and
+// hashed) description is never rewritten.
+
+var (
+	describeMu sync.Mutex
+	templates  = map[string]string{}
+)
+
+// RegisterDescription registers the description template for an
+// operation, replacing any previous template.
+func RegisterDescription(operation, template string) {
+	describeMu.Lock()
+	defer describeMu.Unlock()
+	templates[operation] = template
+}
+
+// Describe returns the step's description, synthesizing one when the
+// step was recorded without: from the operation's registered template
+// if there is one, otherwise generically from the step's values. Report
+// renderers call this instead of reading Description directly.
+func Describe(st *Step) string {
+	if st.Description != "" {
+		return st.Description
+	}
+	describeMu.Lock()
+	template, ok := templates[st.Operation]
+	describeMu.Unlock()
+	if ok {
+		return expandTemplate(template, st)
+	}
+	return genericDescription(st)
+}
+
+// expandTemplate substitutes each {name} placeholder with "name
+// (value)"; a name the step does not carry expands to "name (?)".
+func expandTemplate(template string, st *Step) string {
+	var b strings.Builder
+	for {
+		open := strings.IndexByte(template, '{')
+		if open < 0 {
+			b.WriteString(template)
+			return b.String()
+		}
+		close := strings.IndexByte(template[open:], '}')
+		if close < 0 {
+			b.WriteString(template)
+			return b.String()
+		}
+		b.WriteString(template[:open])
+		name := template[open+1 : open+close]
+		if v, ok := stepValue(st, name); ok {
+			fmt.Fprintf(&b, "%s (%v)", name, v)
+		} else {
+			fmt.Fprintf(&b, "%s (?)", name)
+		}
+		template = template[open+close+1:]
+	}
+}
+
+// genericDescription renders "Applying op to a (1), b (2) to get c (3)"
+// from the step's values alone, for operations with no template.
+func genericDescription(st *Step) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Applying %s", st.Operation)
+	if keys := sortedKeys(st.Inputs); len(keys) > 0 {
+		b.WriteString(" to ")
+		for i, k := range keys {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "%s (%v)", k, st.Inputs[k])
+		}
+	}
+	if keys := sortedKeys(st.Outputs); len(keys) > 0 {
+		b.WriteString(" to get ")
+		for i, k := range keys {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "%s (%v)", k, st.Outputs[k])
+		}
+	}
+	return b.String()
+}
+
+// stepValue looks a placeholder name up in the step's inputs, then its
+// outputs.
+func stepValue(st *Step, name string) (any, bool) {
+	if v, ok := st.Inputs[name]; ok {
+		return v, true
+	}
+	if v, ok := st.Outputs[name]; ok {
+		return v, true
+	}
+	return nil, false
+}
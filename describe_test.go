@@ -0,0 +1,61 @@
+package trace
+
+import "testing"
+
+func TestDescribePrefersRecordedDescription(t *testing.T) {
+	tr := New("t", nil)
+	st := tr.AddStep("divide", nil, nil, WithDescription("hand-written"))
+	if got := Describe(st); got != "hand-written" {
+		t.Fatalf("Describe = %q", got)
+	}
+}
+
+func TestDescribeExpandsRegisteredTemplate(t *testing.T) {
+	RegisterDescription("describe_test.divide",
+		"Dividing {revenue} by {units} to get {unit_price}")
+	tr := New("t", nil)
+	st := tr.AddStep("describe_test.divide",
+		map[string]any{"revenue": 1200, "units": 40},
+		map[string]any{"unit_price": 30})
+	want := "Dividing revenue (1200) by units (40) to get unit_price (30)"
+	if got := Describe(st); got != want {
+		t.Fatalf("Describe = %q, want %q", got, want)
+	}
+	// A placeholder the step does not carry renders as unknown.
+	missing := tr.AddStep("describe_test.divide", map[string]any{"revenue": 10}, nil)
+	if got := Describe(missing); got != "Dividing revenue (10) by units (?) to get unit_price (?)" {
+		t.Fatalf("Describe with missing values = %q", got)
+	}
+}
+
+func TestDescribeFallsBackGenerically(t *testing.T) {
+	tr := New("t", nil)
+	st := tr.AddStep("multiply",
+		map[string]any{"a": 2, "b": 3},
+		map[string]any{"c": 6})
+	if got := Describe(st); got != "Applying multiply to a (2), b (3) to get c (6)" {
+		t.Fatalf("Describe = %q", got)
+	}
+}
+
+func TestNotesAreUnhashedAndAudienceScoped(t *testing.T) {
+	tr := New("t", nil)
+	st := tr.AddStep("calc", nil, nil).
+		WithNote("support", "customer asked about this figure").
+		WithNote("auditor", "rounding follows policy 7")
+	tr.Complete(nil)
+	// Notes may be added after the chain hash exists; they are outside it.
+	st.WithNote("support", "second look requested")
+	if err := tr.Verify(); err != nil {
+		t.Fatalf("notes broke the chain: %v", err)
+	}
+	notes := st.NotesFor("support")
+	if len(notes) != 2 || notes[0].Text != "customer asked about this figure" {
+		t.Fatalf("NotesFor(support): %+v", notes)
+	}
+	data, _ := tr.ToJSON()
+	cp, _ := FromJSON(data)
+	if len(cp.Steps[0].NotesFor("auditor")) != 1 {
+		t.Fatal("notes lost in round trip")
+	}
+}
@@ -0,0 +1,93 @@
+package trace
+
+import (
+	"fmt"
+)
+
+// DeterminismIssue is one step that came out differently across two
+// in-process evaluations of the same computation — the signature of
+// iterating a Go map (or other unordered source) while producing step
+// values.
+type DeterminismIssue struct {
+	StepIndex int
+	Detail    string
+}
+
+func (i DeterminismIssue) String() string {
+	return fmt.Sprintf("step %d: %s", i.StepIndex, i.Detail)
+}
+
+// CheckDeterminism evaluates the computation twice and compares the
+// resulting traces step by step, flagging every step whose recorded
+// content differs between runs. Map iteration order is the usual culprit:
+// a value built by ranging over a map serializes differently run to run,
+// which this surfaces with the offending step and field. run must build
+// and return a fresh trace each call.
+func CheckDeterminism(run func() *Trace) []DeterminismIssue {
+	a, b := run(), run()
+	var issues []DeterminismIssue
+	if a.ID != b.ID {
+		issues = append(issues, DeterminismIssue{StepIndex: -1,
+			Detail: fmt.Sprintf("trace identity differs between runs: %s vs %s (inputs nondeterministic)", a.ID, b.ID)})
+	}
+	n := len(a.Steps)
+	if len(b.Steps) < n {
+		n = len(b.Steps)
+	}
+	for i := 0; i < n; i++ {
+		sa, sb := a.Steps[i], b.Steps[i]
+		if sa.Hash == sb.Hash {
+			continue
+		}
+		issues = append(issues, DeterminismIssue{StepIndex: i, Detail: stepDifference(sa, sb)})
+	}
+	if len(a.Steps) != len(b.Steps) {
+		issues = append(issues, DeterminismIssue{StepIndex: n,
+			Detail: fmt.Sprintf("step count differs between runs: %d vs %d", len(a.Steps), len(b.Steps))})
+	}
+	if HashValue(a.Result) != HashValue(b.Result) {
+		issues = append(issues, DeterminismIssue{StepIndex: len(a.Steps),
+			Detail: fmt.Sprintf("result differs between runs: %v vs %v", a.Result, b.Result)})
+	}
+	return issues
+}
+
+// stepDifference names the first field of the step that differs, with
+// both renderings so the nondeterministic value is visible.
+func stepDifference(a, b *Step) string {
+	if a.Operation != b.Operation {
+		return fmt.Sprintf("operation differs: %q vs %q", a.Operation, b.Operation)
+	}
+	if d := valueDifference("input", a.Inputs, b.Inputs); d != "" {
+		return d
+	}
+	if d := valueDifference("output", a.Outputs, b.Outputs); d != "" {
+		return d
+	}
+	if a.Description != b.Description {
+		return fmt.Sprintf("description differs: %q vs %q", a.Description, b.Description)
+	}
+	if a.Err != b.Err {
+		return fmt.Sprintf("error differs: %q vs %q", a.Err, b.Err)
+	}
+	return "step content differs"
+}
+
+func valueDifference(kind string, a, b map[string]any) string {
+	for _, k := range sortedKeys(a) {
+		av := a[k]
+		bv, ok := b[k]
+		if !ok {
+			return fmt.Sprintf("%s %q present in only one run", kind, k)
+		}
+		if HashValue(av) != HashValue(bv) {
+			return fmt.Sprintf("%s %q differs: %v vs %v", kind, k, av, bv)
+		}
+	}
+	for _, k := range sortedKeys(b) {
+		if _, ok := a[k]; !ok {
+			return fmt.Sprintf("%s %q present in only one run", kind, k)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,172 @@
+package trace
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DiffEntry is one divergence between two traces compared as data-flow
+// graphs.
+type DiffEntry struct {
+	// Kind is "added" (only in b), "removed" (only in a), or "changed"
+	// (same operation and lineage, different values).
+	Kind string `json:"kind"`
+	// AIndex and BIndex locate the steps in each trace; -1 when absent.
+	AIndex  int    `json:"a_index"`
+	BIndex  int    `json:"b_index"`
+	Message string `json:"message"`
+}
+
+// GraphDiff compares two traces as data-flow graphs rather than step
+// sequences: steps are matched by operation and input lineage, so a refactor
+// that merely reorders independent steps produces no entries. Steps that
+// match structurally but carry different values report as "changed"; steps
+// whose shape exists in only one trace report as "added" or "removed".
+func GraphDiff(a, b *Trace) []DiffEntry {
+	m := matchGraphs(a, b)
+	var entries []DiffEntry
+	for _, pair := range m.changed {
+		entries = append(entries, DiffEntry{
+			Kind:   "changed",
+			AIndex: pair[0],
+			BIndex: pair[1],
+			Message: fmt.Sprintf("step %s: values differ (a step %d, b step %d)",
+				a.Steps[pair[0]].Operation, pair[0], pair[1]),
+		})
+	}
+	for _, i := range m.removedA {
+		entries = append(entries, DiffEntry{
+			Kind:    "removed",
+			AIndex:  i,
+			BIndex:  -1,
+			Message: fmt.Sprintf("step %s (a step %d) has no counterpart", a.Steps[i].Operation, i),
+		})
+	}
+	for _, i := range m.addedB {
+		entries = append(entries, DiffEntry{
+			Kind:    "added",
+			AIndex:  -1,
+			BIndex:  i,
+			Message: fmt.Sprintf("step %s (b step %d) has no counterpart", b.Steps[i].Operation, i),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		ki, kj := diffSortKey(entries[i]), diffSortKey(entries[j])
+		return ki < kj
+	})
+	return entries
+}
+
+func diffSortKey(e DiffEntry) int {
+	if e.AIndex >= 0 {
+		return e.AIndex
+	}
+	return e.BIndex
+}
+
+// graphMatch is the full correspondence between two traces' data-flow
+// graphs: identical pairs, value-changed pairs, and steps present on only
+// one side.
+type graphMatch struct {
+	identical [][2]int
+	changed   [][2]int
+	removedA  []int
+	addedB    []int
+}
+
+// matchGraphs pairs steps across two traces by signature: first identically
+// (lineage and values), then structurally (lineage only, values changed).
+func matchGraphs(a, b *Trace) graphMatch {
+	matchedA := make([]bool, len(a.Steps))
+	matchedB := make([]bool, len(b.Steps))
+	var m graphMatch
+	matchBySignature(stepSignatures(a, true), stepSignatures(b, true), matchedA, matchedB, func(ai, bi int) {
+		m.identical = append(m.identical, [2]int{ai, bi})
+	})
+	matchBySignature(stepSignatures(a, false), stepSignatures(b, false), matchedA, matchedB, func(ai, bi int) {
+		m.changed = append(m.changed, [2]int{ai, bi})
+	})
+	for i, ok := range matchedA {
+		if !ok {
+			m.removedA = append(m.removedA, i)
+		}
+	}
+	for i, ok := range matchedB {
+		if !ok {
+			m.addedB = append(m.addedB, i)
+		}
+	}
+	return m
+}
+
+// matchBySignature greedily pairs unmatched steps with equal signatures, in
+// index order, invoking onMatch for each new pair.
+func matchBySignature(aSigs, bSigs []string, matchedA, matchedB []bool, onMatch func(ai, bi int)) {
+	bBySig := map[string][]int{}
+	for i, sig := range bSigs {
+		if !matchedB[i] {
+			bBySig[sig] = append(bBySig[sig], i)
+		}
+	}
+	for ai, sig := range aSigs {
+		if matchedA[ai] {
+			continue
+		}
+		candidates := bBySig[sig]
+		for len(candidates) > 0 && matchedB[candidates[0]] {
+			candidates = candidates[1:]
+		}
+		bBySig[sig] = candidates
+		if len(candidates) == 0 {
+			continue
+		}
+		bi := candidates[0]
+		bBySig[sig] = candidates[1:]
+		matchedA[ai] = true
+		matchedB[bi] = true
+		if onMatch != nil {
+			onMatch(ai, bi)
+		}
+	}
+}
+
+// stepSignatures computes a semantic signature per step: the operation, the
+// signatures of the steps it derives from, the input names, and — when
+// withValues is set — the input and output value hashes. Lineage is folded
+// in recursively, so a step is identified by how its inputs were produced,
+// not by its position.
+func stepSignatures(tr *Trace, withValues bool) []string {
+	sigs := make([]string, len(tr.Steps))
+	for i, st := range tr.Steps {
+		var b strings.Builder
+		b.WriteString(st.Operation)
+		refSigs := make([]string, 0, len(st.Refs))
+		for _, ref := range st.Refs {
+			if ref >= 0 && ref < i {
+				refSigs = append(refSigs, sigs[ref])
+			}
+		}
+		sort.Strings(refSigs)
+		for _, rs := range refSigs {
+			b.WriteString("|ref:" + rs)
+		}
+		for _, k := range sortedKeys(st.Inputs) {
+			if withValues {
+				b.WriteString("|in:" + k + "=" + HashValue(st.Inputs[k]))
+			} else {
+				b.WriteString("|in:" + k)
+			}
+		}
+		if withValues {
+			for _, k := range sortedKeys(st.Outputs) {
+				b.WriteString("|out:" + k + "=" + HashValue(st.Outputs[k]))
+			}
+		}
+		sum := sha256.Sum256([]byte(b.String()))
+		sigs[i] = hex.EncodeToString(sum[:])
+	}
+	return sigs
+}
@@ -0,0 +1,117 @@
+package trace
+
+import "testing"
+
+func TestGraphDiffIgnoresReorderedIndependentSteps(t *testing.T) {
+	a := New("t", nil)
+	a.AddStep("left", map[string]any{"x": 1}, map[string]any{"l": 2})
+	a.AddStep("right", map[string]any{"y": 3}, map[string]any{"r": 4})
+	b := New("t", nil)
+	b.AddStep("right", map[string]any{"y": 3}, map[string]any{"r": 4})
+	b.AddStep("left", map[string]any{"x": 1}, map[string]any{"l": 2})
+	if entries := GraphDiff(a, b); len(entries) != 0 {
+		t.Fatalf("reordering reported divergences: %+v", entries)
+	}
+}
+
+func TestGraphDiffReportsChangedAddedRemoved(t *testing.T) {
+	a := New("t", nil)
+	a.AddStep("tax", map[string]any{"rate": 0.2}, map[string]any{"tax": 20.0})
+	a.AddStep("audit", nil, map[string]any{"ok": true})
+	b := New("t", nil)
+	b.AddStep("tax", map[string]any{"rate": 0.21}, map[string]any{"tax": 21.0})
+	b.AddStep("round", nil, map[string]any{"net": 79.0})
+
+	entries := GraphDiff(a, b)
+	kinds := map[string]int{}
+	for _, e := range entries {
+		kinds[e.Kind]++
+	}
+	if kinds["changed"] != 1 || kinds["removed"] != 1 || kinds["added"] != 1 {
+		t.Fatalf("diff entries %+v", entries)
+	}
+}
+
+func TestStructurallyEquivalent(t *testing.T) {
+	build := func(x, y float64) *Trace {
+		tr := New("calc", map[string]any{"x": x, "y": y})
+		tr.AddStep("mul", map[string]any{"x": x, "y": y}, map[string]any{"p": x * y})
+		tr.AddStep("round", map[string]any{"p": x * y}, nil, WithRefs(0))
+		return tr
+	}
+	if !StructurallyEquivalent(build(2, 3), build(5, 7)) {
+		t.Fatal("same procedure on different data reported inequivalent")
+	}
+	other := New("calc", map[string]any{"x": 1.0, "y": 2.0})
+	other.AddStep("div", map[string]any{"x": 1.0, "y": 2.0}, nil)
+	other.AddStep("round", nil, nil, WithRefs(0))
+	if StructurallyEquivalent(build(1, 2), other) {
+		t.Fatal("different operations reported equivalent")
+	}
+}
+
+func TestNormalizeStablizesCommutativeGroups(t *testing.T) {
+	build := func(flip bool) *Trace {
+		tr := New("t", map[string]any{"n": 1})
+		ops := []string{"alpha", "beta"}
+		if flip {
+			ops[0], ops[1] = ops[1], ops[0]
+		}
+		for _, op := range ops {
+			tr.AddStep(op, map[string]any{"k": 1}, nil)
+		}
+		tr.Complete(1)
+		return tr
+	}
+	na, nb := Normalize(build(false)), Normalize(build(true))
+	if na.ContentHash() != nb.ContentHash() {
+		t.Fatal("parallel orderings normalize differently")
+	}
+	// Data-flow edges pin the order: a referencing step cannot float above
+	// its producer.
+	tr := New("t", nil)
+	tr.AddStep("produce", nil, map[string]any{"v": 1})
+	tr.AddStep("consume", map[string]any{"v": 1}, nil, WithRefs(0))
+	n := Normalize(tr)
+	if n.Steps[0].Operation != "produce" {
+		t.Fatalf("normalize reordered across a data-flow edge: %+v", n.Steps)
+	}
+}
+
+func TestNormalizeCanonicalizesNumericSpellings(t *testing.T) {
+	a := New("t", map[string]any{"x": 1})
+	a.Complete(int64(5))
+	b := New("t", map[string]any{"x": 1.0})
+	b.Complete(5.0)
+	if Normalize(a).ContentHash() != Normalize(b).ContentHash() {
+		t.Fatal("1 and 1.0 normalize differently")
+	}
+}
+
+func TestCheckDeterminismFlagsUnstableValues(t *testing.T) {
+	stable := func() *Trace {
+		tr := New("t", map[string]any{"x": 1})
+		tr.AddStep("op", map[string]any{"x": 1}, map[string]any{"y": 2})
+		tr.Complete(2)
+		return tr
+	}
+	if issues := CheckDeterminism(stable); len(issues) != 0 {
+		t.Fatalf("stable run flagged: %+v", issues)
+	}
+
+	counter := 0
+	unstable := func() *Trace {
+		counter++
+		tr := New("t", map[string]any{"x": 1})
+		tr.AddStep("op", nil, map[string]any{"y": counter})
+		tr.Complete(counter)
+		return tr
+	}
+	issues := CheckDeterminism(unstable)
+	if len(issues) != 2 {
+		t.Fatalf("issues %+v", issues)
+	}
+	if issues[0].StepIndex != 0 || issues[1].Detail == "" {
+		t.Fatalf("issues %+v", issues)
+	}
+}
@@ -0,0 +1,65 @@
+package trace
+
+import (
+	"os"
+	"runtime"
+)
+
+// EnvCaptureOption configures what WithEnvironmentCapture records.
+type EnvCaptureOption func(*envCapture)
+
+type envCapture struct {
+	hostname bool
+	envVars  []string
+}
+
+// CaptureHostname additionally records the machine hostname. It is opt-in
+// because hostnames can be sensitive in shared trace archives.
+func CaptureHostname() EnvCaptureOption {
+	return func(c *envCapture) { c.hostname = true }
+}
+
+// CaptureEnv additionally records the named environment variables.
+func CaptureEnv(keys ...string) EnvCaptureOption {
+	return func(c *envCapture) { c.envVars = append(c.envVars, keys...) }
+}
+
+// WithEnvironmentCapture records an environment snapshot — Go version,
+// OS/arch, configured locale, and optionally hostname and selected
+// environment variables — into trace metadata at creation, so "same inputs,
+// different result" investigations can rule out environment drift.
+func WithEnvironmentCapture(opts ...EnvCaptureOption) Option {
+	var cfg envCapture
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return func(tr *Trace) {
+		tr.Metadata["env.go_version"] = runtime.Version()
+		tr.Metadata["env.os"] = runtime.GOOS
+		tr.Metadata["env.arch"] = runtime.GOARCH
+		if locale := lookupLocale(); locale != "" {
+			tr.Metadata["env.locale"] = locale
+		}
+		if cfg.hostname {
+			if host, err := os.Hostname(); err == nil {
+				tr.Metadata["env.hostname"] = host
+			}
+		}
+		for _, key := range cfg.envVars {
+			if v, ok := os.LookupEnv(key); ok {
+				tr.Metadata["env.var."+key] = v
+			}
+		}
+	}
+}
+
+// lookupLocale returns the configured locale using the conventional POSIX
+// precedence, or "" when none is set.
+func lookupLocale() string {
+	for _, key := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+		if v := os.Getenv(key); v != "" {
+			return v
+		}
+	}
+	return ""
+}
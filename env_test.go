@@ -0,0 +1,53 @@
+package trace
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestEnvironmentCapture(t *testing.T) {
+	t.Setenv("TRACE_ENV_TEST", "v1")
+	tr := New("t", nil, WithEnvironmentCapture(CaptureEnv("TRACE_ENV_TEST", "TRACE_ENV_UNSET")))
+	if tr.Metadata["env.go_version"] != runtime.Version() || tr.Metadata["env.os"] != runtime.GOOS {
+		t.Fatalf("metadata %v", tr.Metadata)
+	}
+	if tr.Metadata["env.var.TRACE_ENV_TEST"] != "v1" {
+		t.Fatalf("selected env var missing: %v", tr.Metadata)
+	}
+	if _, ok := tr.Metadata["env.var.TRACE_ENV_UNSET"]; ok {
+		t.Fatal("unset env var recorded")
+	}
+	// Hostname is opt-in.
+	if _, ok := tr.Metadata["env.hostname"]; ok {
+		t.Fatal("hostname captured without CaptureHostname")
+	}
+}
+
+func TestEnvironmentMetadataIsUnhashed(t *testing.T) {
+	tr := New("t", nil, WithEnvironmentCapture())
+	tr.AddStep("op", nil, nil)
+	tr.Complete(nil)
+	if err := tamper(t, tr, func(tr *Trace) { delete(tr.Metadata, "env.go_version") }); err != nil {
+		t.Fatalf("environment snapshot should sit outside the chain: %v", err)
+	}
+}
+
+func TestFloatReproducibilityTolerance(t *testing.T) {
+	tr := New("t", nil, WithFloatReproducibility())
+	tr.AddStep("mul", nil, map[string]any{"p": 1e6})
+	tr.Complete(2e6)
+	tol := tr.ReproducibilityTolerance()
+	want := (1e6 + 2e6) * 0x1p-52
+	if tol != want {
+		t.Fatalf("tolerance %g, want %g", tol, want)
+	}
+	if tr.Metadata["system.fp.tolerance"] == "" || tr.Metadata["system.fp.model"] != "IEEE-754 binary64" {
+		t.Fatalf("fp metadata %v", tr.Metadata)
+	}
+	// Untracked traces report zero.
+	plain := New("t", nil)
+	plain.AddStep("mul", nil, map[string]any{"p": 1.0})
+	if plain.ReproducibilityTolerance() != 0 {
+		t.Fatal("tolerance accumulated without tracking")
+	}
+}
@@ -0,0 +1,31 @@
+package trace
+
+import "sort"
+
+// StructurallyEquivalent reports whether two traces performed the same
+// shaped computation: the same named inputs, and the same multiset of steps
+// when each step is identified by its operation, input names, and the shape
+// of the lineage that produced its inputs — ignoring all concrete values.
+// It verifies that two implementations of an algorithm follow the same
+// procedure, even when run on different data.
+func StructurallyEquivalent(a, b *Trace) bool {
+	if len(a.Steps) != len(b.Steps) || len(a.Inputs) != len(b.Inputs) {
+		return false
+	}
+	aKeys, bKeys := sortedKeys(a.Inputs), sortedKeys(b.Inputs)
+	for i := range aKeys {
+		if aKeys[i] != bKeys[i] {
+			return false
+		}
+	}
+	aSigs := stepSignatures(a, false)
+	bSigs := stepSignatures(b, false)
+	sort.Strings(aSigs)
+	sort.Strings(bSigs)
+	for i := range aSigs {
+		if aSigs[i] != bSigs[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,181 @@
+package trace
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToDOT renders the trace's data-flow graph in Graphviz DOT: one node per
+// input and per step, with edges following Refs and input-name consumption.
+func (tr *Trace) ToDOT() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", tr.Name)
+	b.WriteString("  rankdir=LR;\n  node [shape=box, fontname=\"monospace\"];\n")
+	for _, k := range sortedKeys(tr.Inputs) {
+		fmt.Fprintf(&b, "  %q [label=%q, style=filled, fillcolor=lightgray];\n",
+			"in_"+k, fmt.Sprintf("%s = %v", k, tr.Inputs[k]))
+	}
+	for _, st := range tr.Steps {
+		fmt.Fprintf(&b, "  %q [label=%q];\n", stepNodeID("s", st.Index), stepLabel(st))
+	}
+	for _, st := range tr.Steps {
+		for _, ref := range st.Refs {
+			fmt.Fprintf(&b, "  %q -> %q;\n", stepNodeID("s", ref), stepNodeID("s", st.Index))
+		}
+		for k := range st.Inputs {
+			if _, ok := tr.Inputs[k]; ok {
+				fmt.Fprintf(&b, "  %q -> %q;\n", "in_"+k, stepNodeID("s", st.Index))
+			}
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// ToMermaid renders the same data-flow graph as a Mermaid flowchart, for
+// embedding in markdown documents and review tools.
+func (tr *Trace) ToMermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for _, k := range sortedKeys(tr.Inputs) {
+		fmt.Fprintf(&b, "  in_%s[\"%s = %v\"]\n", sanitizeMermaidID(k), k, tr.Inputs[k])
+	}
+	for _, st := range tr.Steps {
+		fmt.Fprintf(&b, "  s%d[\"%s\"]\n", st.Index, stepLabel(st))
+	}
+	for _, st := range tr.Steps {
+		for _, ref := range st.Refs {
+			fmt.Fprintf(&b, "  s%d --> s%d\n", ref, st.Index)
+		}
+		for k := range st.Inputs {
+			if _, ok := tr.Inputs[k]; ok {
+				fmt.Fprintf(&b, "  in_%s --> s%d\n", sanitizeMermaidID(k), st.Index)
+			}
+		}
+	}
+	return b.String()
+}
+
+// diffColors maps diff kinds to fill colors shared by both diff renderers:
+// green for added, red for removed, orange for changed.
+var diffColors = map[string]string{
+	"added":   "palegreen",
+	"removed": "lightcoral",
+	"changed": "orange",
+}
+
+// DiffDOT renders a merged data-flow graph of two traces in DOT with
+// color-coded nodes: unchanged steps plain, changed orange, steps only in a
+// red, steps only in b green — so reviewers see structurally where two
+// computations diverged.
+func DiffDOT(a, b *Trace) string {
+	m := matchGraphs(a, b)
+	bToNode := diffNodeMap(m)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "digraph %q {\n", a.Name+" vs "+b.Name)
+	sb.WriteString("  rankdir=LR;\n  node [shape=box, fontname=\"monospace\"];\n")
+	for _, pair := range m.identical {
+		fmt.Fprintf(&sb, "  %q [label=%q];\n", stepNodeID("a", pair[0]), stepLabel(a.Steps[pair[0]]))
+	}
+	for _, pair := range m.changed {
+		fmt.Fprintf(&sb, "  %q [label=%q, style=filled, fillcolor=%s];\n",
+			stepNodeID("a", pair[0]), stepLabel(a.Steps[pair[0]])+" | b: "+stepLabel(b.Steps[pair[1]]), diffColors["changed"])
+	}
+	for _, i := range m.removedA {
+		fmt.Fprintf(&sb, "  %q [label=%q, style=filled, fillcolor=%s];\n",
+			stepNodeID("a", i), stepLabel(a.Steps[i]), diffColors["removed"])
+	}
+	for _, i := range m.addedB {
+		fmt.Fprintf(&sb, "  %q [label=%q, style=filled, fillcolor=%s];\n",
+			stepNodeID("b", i), stepLabel(b.Steps[i]), diffColors["added"])
+	}
+	// Edges follow a's lineage for a-side nodes and b's lineage for added
+	// nodes, mapped onto the merged node set.
+	for _, st := range a.Steps {
+		for _, ref := range st.Refs {
+			fmt.Fprintf(&sb, "  %q -> %q;\n", stepNodeID("a", ref), stepNodeID("a", st.Index))
+		}
+	}
+	for _, i := range m.addedB {
+		for _, ref := range b.Steps[i].Refs {
+			fmt.Fprintf(&sb, "  %q -> %q;\n", bToNode[ref], stepNodeID("b", i))
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// DiffMermaid renders the merged diff graph as a Mermaid flowchart with the
+// same color coding as DiffDOT.
+func DiffMermaid(a, b *Trace) string {
+	m := matchGraphs(a, b)
+	bToNode := diffNodeMap(m)
+
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+	for _, pair := range m.identical {
+		fmt.Fprintf(&sb, "  a%d[\"%s\"]\n", pair[0], stepLabel(a.Steps[pair[0]]))
+	}
+	for _, pair := range m.changed {
+		fmt.Fprintf(&sb, "  a%d[\"%s\"]\n  style a%d fill:%s\n",
+			pair[0], stepLabel(a.Steps[pair[0]]), pair[0], diffColors["changed"])
+	}
+	for _, i := range m.removedA {
+		fmt.Fprintf(&sb, "  a%d[\"%s\"]\n  style a%d fill:%s\n", i, stepLabel(a.Steps[i]), i, diffColors["removed"])
+	}
+	for _, i := range m.addedB {
+		fmt.Fprintf(&sb, "  b%d[\"%s\"]\n  style b%d fill:%s\n", i, stepLabel(b.Steps[i]), i, diffColors["added"])
+	}
+	for _, st := range a.Steps {
+		for _, ref := range st.Refs {
+			fmt.Fprintf(&sb, "  a%d --> a%d\n", ref, st.Index)
+		}
+	}
+	for _, i := range m.addedB {
+		for _, ref := range b.Steps[i].Refs {
+			fmt.Fprintf(&sb, "  %s --> b%d\n", bToNode[ref], i)
+		}
+	}
+	return sb.String()
+}
+
+// diffNodeMap maps b-side step indexes onto merged-graph node IDs: matched
+// steps render as their a-side node, added steps as their own b-side node.
+func diffNodeMap(m graphMatch) map[int]string {
+	bToNode := map[int]string{}
+	for _, pair := range m.identical {
+		bToNode[pair[1]] = stepNodeID("a", pair[0])
+	}
+	for _, pair := range m.changed {
+		bToNode[pair[1]] = stepNodeID("a", pair[0])
+	}
+	for _, i := range m.addedB {
+		bToNode[i] = stepNodeID("b", i)
+	}
+	return bToNode
+}
+
+func stepNodeID(side string, index int) string {
+	return fmt.Sprintf("%s%d", side, index)
+}
+
+// stepLabel summarizes a step for graph rendering: the operation and its
+// named outputs.
+func stepLabel(st *Step) string {
+	label := fmt.Sprintf("%d: %s", st.Index, st.Operation)
+	for _, k := range sortedKeys(st.Outputs) {
+		label += fmt.Sprintf("\\n%s = %v", k, st.Outputs[k])
+	}
+	return label
+}
+
+func sanitizeMermaidID(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		}
+		return '_'
+	}, s)
+}
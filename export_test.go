@@ -0,0 +1,147 @@
+package trace
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func exportableTrace() *Trace {
+	tr := New("report", map[string]any{"x": 1})
+	tr.AddStep("load", map[string]any{"x": 1}, map[string]any{"rows": 2},
+		WithDuration(2*time.Millisecond))
+	tr.AddStep("sum", map[string]any{"rows": 2}, map[string]any{"total": 3},
+		WithRefs(0), WithDuration(time.Millisecond))
+	tr.Complete(3)
+	return tr
+}
+
+func TestToDOTAndMermaid(t *testing.T) {
+	tr := exportableTrace()
+	dot := tr.ToDOT()
+	if !strings.Contains(dot, "digraph") || !strings.Contains(dot, "load") || !strings.Contains(dot, "->") {
+		t.Fatalf("dot:\n%s", dot)
+	}
+	mmd := tr.ToMermaid()
+	if !strings.HasPrefix(mmd, "flowchart") || !strings.Contains(mmd, "sum") {
+		t.Fatalf("mermaid:\n%s", mmd)
+	}
+}
+
+func TestDiffRenderings(t *testing.T) {
+	a := exportableTrace()
+	b := New("report", map[string]any{"x": 1})
+	b.AddStep("load", map[string]any{"x": 1}, map[string]any{"rows": 5})
+	b.Complete(5)
+	if out := DiffDOT(a, b); !strings.Contains(out, "digraph") {
+		t.Fatalf("diff dot:\n%s", out)
+	}
+	if out := DiffMermaid(a, b); !strings.HasPrefix(out, "flowchart") {
+		t.Fatalf("diff mermaid:\n%s", out)
+	}
+}
+
+func TestToCSVRows(t *testing.T) {
+	tr := exportableTrace()
+	var buf bytes.Buffer
+	if err := tr.ToCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 { // header + two steps
+		t.Fatalf("%d rows", len(records))
+	}
+	if len(records[0]) != len(CSVHeader()) {
+		t.Fatalf("header %v", records[0])
+	}
+	var tsv bytes.Buffer
+	if err := tr.ToTSV(&tsv); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(tsv.String(), "\t") {
+		t.Fatal("TSV output carries no tabs")
+	}
+}
+
+func TestChromeTraceRoundTrip(t *testing.T) {
+	tr := exportableTrace()
+	var buf bytes.Buffer
+	if err := tr.ToChromeTraceEvents(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("chrome export is not JSON: %v", err)
+	}
+	back, err := FromChromeTraceEvents(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ops := map[string]bool{}
+	for _, st := range back.Steps {
+		ops[st.Operation] = true
+	}
+	if !ops["load"] || !ops["sum"] {
+		t.Fatalf("imported steps %+v", back.Steps)
+	}
+}
+
+func TestFromChromeTraceEventsNesting(t *testing.T) {
+	events := `[
+		{"name":"thread_name","ph":"M","tid":1,"args":{"name":"worker"}},
+		{"name":"outer","ph":"B","ts":0,"tid":1},
+		{"name":"inner","ph":"X","ts":10,"dur":5,"tid":1},
+		{"name":"outer","ph":"E","ts":100,"tid":1}
+	]`
+	tr, err := FromChromeTraceEvents(strings.NewReader(events))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var outer, inner *Step
+	for _, st := range tr.Steps {
+		switch st.Operation {
+		case "outer":
+			outer = st
+		case "inner":
+			inner = st
+		}
+	}
+	if outer == nil || inner == nil {
+		t.Fatalf("steps %+v", tr.Steps)
+	}
+	if inner.Origin != "worker" {
+		t.Fatalf("thread name not mapped to origin: %q", inner.Origin)
+	}
+	if len(inner.Refs) != 1 || inner.Refs[0] != outer.Index {
+		t.Fatalf("nesting not preserved: inner refs %v, outer index %d", inner.Refs, outer.Index)
+	}
+}
+
+func TestSpeedscopeSVGAndGantt(t *testing.T) {
+	tr := exportableTrace()
+	var buf bytes.Buffer
+	if err := tr.ToSpeedscope(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("speedscope output is not JSON: %v", err)
+	}
+	if doc["$schema"] == "" {
+		t.Fatal("speedscope schema missing")
+	}
+	svg := tr.ToSVGTimeline()
+	if !strings.HasPrefix(svg, "<svg") || !strings.Contains(svg, "load") {
+		t.Fatalf("svg:\n%s", svg)
+	}
+	gantt := tr.ToMermaidGantt()
+	if !strings.Contains(gantt, "gantt") || !strings.Contains(gantt, "sum") {
+		t.Fatalf("gantt:\n%s", gantt)
+	}
+}
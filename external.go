@@ -0,0 +1,48 @@
+package trace
+
+import (
+	"fmt"
+	"time"
+)
+
+// ExternalOption configures RecordExternalCall.
+type ExternalOption func(*externalConfig)
+
+type externalConfig struct {
+	attachPayloads bool
+}
+
+// AttachPayloads additionally stores the full request and response bodies as
+// trace attachments ("external/<step>-request", "external/<step>-response"),
+// for cases where hashes alone are not enough evidence.
+func AttachPayloads() ExternalOption {
+	return func(c *externalConfig) { c.attachPayloads = true }
+}
+
+// RecordExternalCall records a call to an external system (HTTP, RPC) as an
+// "external.call" step: the endpoint, request and response hashes, status,
+// and latency. Not every input of a computation originates inside the
+// process; this keeps the outside contributions in the provenance record.
+func RecordExternalCall(tr *Trace, system, endpoint string, request, response []byte, status string, latency time.Duration, err error, opts ...ExternalOption) *Step {
+	var cfg externalConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	inputs := map[string]any{
+		"system":       system,
+		"endpoint":     endpoint,
+		"request_hash": HashValue(request),
+	}
+	outputs := map[string]any{
+		"response_hash": HashValue(response),
+		"status":        status,
+	}
+	st := tr.AddStep("external.call", inputs, outputs,
+		WithDuration(latency), WithStepError(err),
+		WithDescription(fmt.Sprintf("%s call to %s", system, endpoint)))
+	if st != nil && cfg.attachPayloads {
+		tr.Attach(fmt.Sprintf("external/%d-request", st.Index), request)
+		tr.Attach(fmt.Sprintf("external/%d-response", st.Index), response)
+	}
+	return st
+}
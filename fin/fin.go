@@ -0,0 +1,134 @@
+// Package fin provides traced financial functions — NPV, IRR, loan
+// amortization — that emit one step per period (discount factor, cash
+// flow, accumulated value), so a financial model's output arrives with a
+// complete, regulator-readable derivation instead of a bare number.
+package fin
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/euclidtrace/trace"
+)
+
+// NPV records the net present value of the cash flows at the given
+// per-period discount rate. Cash flow 0 is at time zero; each period
+// records its discount factor, present value, and the running total.
+func NPV(tr *trace.Trace, rate float64, cashflows []float64) float64 {
+	total := 0.0
+	for t, cf := range cashflows {
+		factor := 1 / math.Pow(1+rate, float64(t))
+		pv := cf * factor
+		next := total + pv
+		tr.AddStep("fin.npv.period",
+			map[string]any{"period": t, "cash_flow": cf, "rate": rate},
+			map[string]any{"discount_factor": factor, "present_value": pv, "accumulated": next},
+			trace.WithDescription(fmt.Sprintf("1/(1+%g)^%d = %g", rate, t, factor)))
+		total = next
+	}
+	tr.AddStep("fin.npv",
+		map[string]any{"rate": rate, "periods": len(cashflows)},
+		map[string]any{"npv": total})
+	return total
+}
+
+// irrMaxIterations bounds the bisection; with the bracket below it is far
+// more than needed for the tolerance.
+const (
+	irrMaxIterations = 200
+	irrTolerance     = 1e-9
+)
+
+// IRR records the internal rate of return found by bisection, one step
+// per iteration showing the bracketing rates and the NPV at the midpoint.
+// The cash flows must change sign somewhere for a root to exist.
+func IRR(tr *trace.Trace, cashflows []float64) (float64, error) {
+	lo, hi := -0.9999, 10.0
+	npvAt := func(rate float64) float64 {
+		total := 0.0
+		for t, cf := range cashflows {
+			total += cf / math.Pow(1+rate, float64(t))
+		}
+		return total
+	}
+	flo, fhi := npvAt(lo), npvAt(hi)
+	if (flo < 0) == (fhi < 0) {
+		err := fmt.Errorf("fin: IRR not bracketed in [%g, %g]", lo, hi)
+		tr.AddStep("fin.irr", map[string]any{"periods": len(cashflows)}, nil,
+			trace.WithStepError(err))
+		return 0, err
+	}
+	for i := 0; i < irrMaxIterations; i++ {
+		mid := (lo + hi) / 2
+		fmid := npvAt(mid)
+		tr.AddStep("fin.irr.iteration",
+			map[string]any{"iteration": i, "lo": lo, "hi": hi},
+			map[string]any{"rate": mid, "npv": fmid})
+		if math.Abs(fmid) < irrTolerance || hi-lo < irrTolerance {
+			tr.AddStep("fin.irr",
+				map[string]any{"iterations": i + 1, "tolerance": irrTolerance},
+				map[string]any{"irr": mid})
+			return mid, nil
+		}
+		if (fmid < 0) == (flo < 0) {
+			lo, flo = mid, fmid
+		} else {
+			hi = mid
+		}
+	}
+	err := fmt.Errorf("fin: IRR did not converge in %d iterations", irrMaxIterations)
+	tr.AddStep("fin.irr", map[string]any{"periods": len(cashflows)}, nil,
+		trace.WithStepError(err))
+	return 0, err
+}
+
+// Payment is one row of an amortization schedule.
+type Payment struct {
+	Period    int     `json:"period"`
+	Payment   float64 `json:"payment"`
+	Interest  float64 `json:"interest"`
+	Principal float64 `json:"principal"`
+	Balance   float64 `json:"balance"`
+}
+
+// Amortize records a level-payment amortization schedule: the payment
+// formula as its own step, then one step per period splitting the payment
+// into interest and principal and carrying the remaining balance.
+func Amortize(tr *trace.Trace, principal, ratePerPeriod float64, periods int) ([]Payment, error) {
+	if periods < 1 {
+		err := fmt.Errorf("fin: amortize over %d periods", periods)
+		tr.AddStep("fin.amortize", map[string]any{"principal": principal}, nil,
+			trace.WithStepError(err))
+		return nil, err
+	}
+	var payment float64
+	if ratePerPeriod == 0 {
+		payment = principal / float64(periods)
+	} else {
+		payment = principal * ratePerPeriod / (1 - math.Pow(1+ratePerPeriod, -float64(periods)))
+	}
+	tr.AddStep("fin.amortize.payment",
+		map[string]any{"principal": principal, "rate": ratePerPeriod, "periods": periods},
+		map[string]any{"payment": payment},
+		trace.WithDescription("P*r / (1 - (1+r)^-n)"))
+
+	schedule := make([]Payment, 0, periods)
+	balance := principal
+	for t := 1; t <= periods; t++ {
+		interest := balance * ratePerPeriod
+		principalPart := payment - interest
+		if t == periods {
+			// Final period absorbs rounding drift so the balance closes at
+			// exactly zero.
+			principalPart = balance
+			payment = interest + principalPart
+		}
+		balance -= principalPart
+		row := Payment{Period: t, Payment: payment, Interest: interest, Principal: principalPart, Balance: balance}
+		schedule = append(schedule, row)
+		tr.AddStep("fin.amortize.period",
+			map[string]any{"period": t, "rate": ratePerPeriod},
+			map[string]any{"payment": payment, "interest": interest, "principal": principalPart, "balance": balance})
+	}
+	return schedule, nil
+}
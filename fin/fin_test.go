@@ -0,0 +1,102 @@
+package fin
+
+import (
+	"math"
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+func TestNPVRecordsEveryPeriod(t *testing.T) {
+	tr := trace.New("npv", nil)
+	got := NPV(tr, 0.1, []float64{-100, 60, 60})
+	want := -100 + 60/1.1 + 60/(1.1*1.1)
+	if math.Abs(got-want) > 1e-12 {
+		t.Fatalf("npv %g, want %g", got, want)
+	}
+	// One step per period plus the total.
+	if len(tr.Steps) != 4 {
+		t.Fatalf("%d steps", len(tr.Steps))
+	}
+	if tr.Steps[0].Outputs["discount_factor"] != 1.0 {
+		t.Fatalf("period 0 not at time zero: %+v", tr.Steps[0])
+	}
+	if tr.Steps[3].Operation != "fin.npv" {
+		t.Fatalf("total step %+v", tr.Steps[3])
+	}
+}
+
+func TestIRRFindsTheRootAndShowsIterations(t *testing.T) {
+	tr := trace.New("irr", nil)
+	cashflows := []float64{-100, 60, 60}
+	irr, err := IRR(tr, cashflows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// NPV at the reported rate is ~zero.
+	if npv := NPV(trace.New("check", nil), irr, cashflows); math.Abs(npv) > 1e-6 {
+		t.Fatalf("NPV(%g) = %g", irr, npv)
+	}
+	var iterations int
+	for _, st := range tr.Steps {
+		if st.Operation == "fin.irr.iteration" {
+			iterations++
+		}
+	}
+	if iterations == 0 {
+		t.Fatal("no iteration steps recorded")
+	}
+	last := tr.Steps[len(tr.Steps)-1]
+	if last.Operation != "fin.irr" || last.Outputs["irr"] != irr {
+		t.Fatalf("final step %+v", last)
+	}
+}
+
+func TestIRRRequiresASignChange(t *testing.T) {
+	tr := trace.New("irr", nil)
+	if _, err := IRR(tr, []float64{100, 60, 60}); err == nil {
+		t.Fatal("found an IRR for all-positive cash flows")
+	}
+	if tr.Steps[0].Err == "" {
+		t.Fatal("error step not recorded")
+	}
+}
+
+func TestAmortizeClosesAtZero(t *testing.T) {
+	tr := trace.New("loan", nil)
+	schedule, err := Amortize(tr, 10000, 0.01, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schedule) != 12 {
+		t.Fatalf("%d rows", len(schedule))
+	}
+	if schedule[11].Balance != 0 {
+		t.Fatalf("final balance %g", schedule[11].Balance)
+	}
+	var principal float64
+	for _, row := range schedule {
+		principal += row.Principal
+	}
+	if math.Abs(principal-10000) > 1e-9 {
+		t.Fatalf("principal paid %g", principal)
+	}
+	// Payment formula step plus one per period.
+	if len(tr.Steps) != 13 {
+		t.Fatalf("%d steps", len(tr.Steps))
+	}
+}
+
+func TestAmortizeZeroRateAndBadPeriods(t *testing.T) {
+	tr := trace.New("loan", nil)
+	schedule, err := Amortize(tr, 1200, 0, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schedule[0].Payment != 100 || schedule[0].Interest != 0 {
+		t.Fatalf("zero-rate row %+v", schedule[0])
+	}
+	if _, err := Amortize(tr, 1200, 0.01, 0); err == nil {
+		t.Fatal("amortized over zero periods")
+	}
+}
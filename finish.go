@@ -0,0 +1,29 @@
+package trace
+
+// OnFinish registers fn to run once the trace reaches a terminal state
+// — completed, failed, or aborted. Callbacks run on the goroutine that
+// finished the trace, after its lock is released, so they may use any
+// trace method (ContentHash, ToJSON) on what is by then an immutable
+// record. A trace that is never finished never runs its callbacks.
+func OnFinish(fn func(tr *Trace)) Option {
+	return func(tr *Trace) {
+		tr.onFinish = append(tr.onFinish, fn)
+	}
+}
+
+// runFinishHooks runs and clears the registered finish callbacks. It is
+// deferred before the lock release in Complete, Fail, and Abort, so it
+// fires unlocked; a second terminal call finds the list already empty.
+func (tr *Trace) runFinishHooks() {
+	tr.mu.Lock()
+	if tr.Status == StatusRecording {
+		tr.mu.Unlock()
+		return
+	}
+	fns := tr.onFinish
+	tr.onFinish = nil
+	tr.mu.Unlock()
+	for _, fn := range fns {
+		fn(tr)
+	}
+}
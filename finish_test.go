@@ -0,0 +1,44 @@
+package trace
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOnFinishRunsOncePerTrace(t *testing.T) {
+	var got []Status
+	tr := New("job", nil, OnFinish(func(tr *Trace) { got = append(got, tr.Status) }))
+	tr.AddStep("a", nil, nil)
+	tr.Complete(1)
+	tr.Complete(2) // second terminal call must not re-fire
+	tr.Fail(errors.New("late"))
+	if len(got) != 1 || got[0] != StatusCompleted {
+		t.Fatalf("callbacks fired with %v", got)
+	}
+}
+
+func TestOnFinishSeesTheImmutableRecord(t *testing.T) {
+	var hash string
+	tr := New("job", nil, OnFinish(func(tr *Trace) {
+		// The callback runs after the lock is released; locked methods
+		// must be safe to call.
+		hash = tr.ContentHash()
+		if err := tr.Verify(); err != nil {
+			t.Errorf("trace not verifiable inside OnFinish: %v", err)
+		}
+	}))
+	tr.AddStep("a", nil, nil)
+	tr.Fail(errors.New("boom"))
+	if hash == "" || hash != tr.ContentHash() {
+		t.Fatalf("callback saw hash %q, trace has %q", hash, tr.ContentHash())
+	}
+}
+
+func TestOnFinishFiresOnAbort(t *testing.T) {
+	fired := false
+	tr := New("job", nil, OnFinish(func(*Trace) { fired = true }))
+	tr.Abort(errors.New("deadline"))
+	if !fired {
+		t.Fatal("abort did not fire the callback")
+	}
+}
@@ -0,0 +1,134 @@
+// Package fixed provides integer-backed fixed-point decimal values with
+// traced arithmetic and overflow detection, for embedded and financial
+// systems that forbid floats entirely. A value is Units * 10^-Scale; all
+// arithmetic stays in int64, and any overflow is recorded on the trace as
+// an error step rather than wrapping silently.
+package fixed
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Value is a fixed-point decimal: Units scaled down by 10^Scale.
+// New(12345, 2) is 123.45.
+type Value struct {
+	Units int64 `json:"units"`
+	Scale int   `json:"scale"`
+}
+
+// New returns Units * 10^-Scale.
+func New(units int64, scale int) Value {
+	if scale < 0 {
+		scale = 0
+	}
+	return Value{Units: units, Scale: scale}
+}
+
+func (v Value) String() string {
+	if v.Scale == 0 {
+		return fmt.Sprintf("%d", v.Units)
+	}
+	sign := ""
+	units := v.Units
+	if units < 0 {
+		sign = "-"
+		units = -units
+	}
+	digits := fmt.Sprintf("%0*d", v.Scale+1, units)
+	point := len(digits) - v.Scale
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// Parse reads a decimal string ("123.45", "-0.07") into a fixed-point
+// value whose scale is the number of fractional digits. Anything but an
+// optionally signed run of digits with at most one decimal point is
+// rejected — for money, a partial parse is worse than no parse.
+func Parse(s string) (Value, error) {
+	text, neg := strings.CutPrefix(s, "-")
+	whole, frac, _ := strings.Cut(text, ".")
+	if whole+frac == "" || !allDigits(whole) || !allDigits(frac) {
+		return Value{}, fmt.Errorf("fixed: parse %q: not a decimal number", s)
+	}
+	units, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return Value{}, fmt.Errorf("fixed: parse %q: %w", s, err)
+	}
+	if neg {
+		units = -units
+	}
+	return Value{Units: units, Scale: len(frac)}, nil
+}
+
+// allDigits reports whether s is entirely ASCII digits; the empty string
+// qualifies, so "123." and ".45" parse.
+func allDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// ErrOverflow reports arithmetic that left the int64 range.
+type ErrOverflow struct {
+	Op   string
+	A, B Value
+}
+
+func (e *ErrOverflow) Error() string {
+	return fmt.Sprintf("fixed: %s(%s, %s) overflows", e.Op, e.A, e.B)
+}
+
+// addCheck adds with overflow detection.
+func addCheck(a, b int64) (int64, bool) {
+	sum := a + b
+	if (a > 0 && b > 0 && sum < 0) || (a < 0 && b < 0 && sum >= 0) {
+		return 0, false
+	}
+	return sum, true
+}
+
+// mulCheck multiplies with overflow detection.
+func mulCheck(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	p := a * b
+	if p/b != a {
+		return 0, false
+	}
+	return p, true
+}
+
+// rescale raises v's scale, reporting overflow of the widened units.
+func rescale(v Value, scale int) (Value, bool) {
+	units := v.Units
+	for s := v.Scale; s < scale; s++ {
+		var ok bool
+		units, ok = mulCheck(units, 10)
+		if !ok {
+			return Value{}, false
+		}
+	}
+	return Value{Units: units, Scale: scale}, true
+}
+
+// align brings two values to their larger scale.
+func align(a, b Value) (Value, Value, bool) {
+	scale := a.Scale
+	if b.Scale > scale {
+		scale = b.Scale
+	}
+	ra, ok := rescale(a, scale)
+	if !ok {
+		return Value{}, Value{}, false
+	}
+	rb, ok := rescale(b, scale)
+	if !ok {
+		return Value{}, Value{}, false
+	}
+	return ra, rb, true
+}
@@ -0,0 +1,124 @@
+package fixed
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+func TestParseAndString(t *testing.T) {
+	cases := []struct {
+		in   string
+		want Value
+	}{
+		{"123.45", New(12345, 2)},
+		{"-0.07", New(-7, 2)},
+		{"42", New(42, 0)},
+		{"0.5", New(5, 1)},
+	}
+	for _, c := range cases {
+		got, err := Parse(c.in)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", c.in, err)
+		}
+		if got != c.want {
+			t.Fatalf("Parse(%q) = %+v, want %+v", c.in, got, c.want)
+		}
+		if got.String() != c.in {
+			t.Fatalf("String() = %q, want %q", got.String(), c.in)
+		}
+	}
+	if _, err := Parse("abc"); err == nil {
+		t.Fatal("parsed garbage")
+	}
+}
+
+func TestParseRejectsMalformedInput(t *testing.T) {
+	// Partial parses are wrong answers, not near misses: "1e5" is not 1
+	// and "12 34" is not 12.
+	for _, in := range []string{
+		"1e5", "1.2.3", "0x10", "12 34", " 42", "42 ",
+		"", ".", "-", "-.", "+42", "--1", "1.-2",
+		"99999999999999999999", // past int64
+	} {
+		if got, err := Parse(in); err == nil {
+			t.Fatalf("Parse(%q) = %+v, want error", in, got)
+		}
+	}
+	// A bare trailing or leading point is still a well-formed decimal.
+	if got, err := Parse("123."); err != nil || got != New(123, 0) {
+		t.Fatalf("Parse(\"123.\") = %+v, %v", got, err)
+	}
+	if got, err := Parse("-.45"); err != nil || got != New(-45, 2) {
+		t.Fatalf("Parse(\"-.45\") = %+v, %v", got, err)
+	}
+}
+
+func TestArithmeticAlignsScales(t *testing.T) {
+	tr := trace.New("t", nil)
+	sum, err := Add(tr, New(12345, 2), New(5, 1)) // 123.45 + 0.5
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum != New(12395, 2) {
+		t.Fatalf("sum %+v", sum)
+	}
+	diff, err := Sub(tr, New(100, 2), New(250, 2)) // 1.00 - 2.50
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != New(-150, 2) {
+		t.Fatalf("diff %+v", diff)
+	}
+	p, err := Mul(tr, New(150, 2), New(2, 0)) // 1.50 * 2
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p != New(300, 2) {
+		t.Fatalf("product %+v", p)
+	}
+	q, err := Div(tr, New(100, 2), New(3, 0)) // 1.00 / 3, truncated at scale 2
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q != New(33, 2) {
+		t.Fatalf("quotient %+v", q)
+	}
+	if len(tr.Steps) != 4 || tr.Steps[0].Operation != "fixed.add" {
+		t.Fatalf("steps %+v", tr.Steps)
+	}
+	tr.Complete(nil)
+	if err := tr.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOverflowIsRecordedNotWrapped(t *testing.T) {
+	tr := trace.New("t", nil)
+	big := New(math.MaxInt64, 0)
+	if _, err := Add(tr, big, New(1, 0)); err == nil {
+		t.Fatal("addition wrapped silently")
+	}
+	var overflow *ErrOverflow
+	_, err := Mul(tr, big, New(2, 0))
+	if !errors.As(err, &overflow) || overflow.Op != "mul" {
+		t.Fatalf("err %v", err)
+	}
+	for _, st := range tr.Steps {
+		if st.Err == "" {
+			t.Fatalf("overflow step carries no error: %+v", st)
+		}
+	}
+}
+
+func TestDivByZeroRecordsError(t *testing.T) {
+	tr := trace.New("t", nil)
+	if _, err := Div(tr, New(100, 2), New(0, 0)); err == nil {
+		t.Fatal("divided by zero")
+	}
+	if tr.Steps[0].Err == "" {
+		t.Fatal("error step not recorded")
+	}
+}
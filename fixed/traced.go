@@ -0,0 +1,87 @@
+package fixed
+
+import (
+	"fmt"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Add records a + b at the operands' common scale. Overflow is recorded
+// as an error step and returned.
+func Add(tr *trace.Trace, a, b Value) (Value, error) {
+	ra, rb, ok := align(a, b)
+	if ok {
+		var units int64
+		units, ok = addCheck(ra.Units, rb.Units)
+		if ok {
+			out := Value{Units: units, Scale: ra.Scale}
+			record(tr, "fixed.add", a, b, out)
+			return out, nil
+		}
+	}
+	return Value{}, overflow(tr, "add", a, b)
+}
+
+// Sub records a - b at the operands' common scale.
+func Sub(tr *trace.Trace, a, b Value) (Value, error) {
+	neg := Value{Units: -b.Units, Scale: b.Scale}
+	if b.Units == -b.Units && b.Units != 0 { // MinInt64 cannot negate
+		return Value{}, overflow(tr, "sub", a, b)
+	}
+	ra, rb, ok := align(a, neg)
+	if ok {
+		var units int64
+		units, ok = addCheck(ra.Units, rb.Units)
+		if ok {
+			out := Value{Units: units, Scale: ra.Scale}
+			record(tr, "fixed.sub", a, b, out)
+			return out, nil
+		}
+	}
+	return Value{}, overflow(tr, "sub", a, b)
+}
+
+// Mul records a * b; the scales add.
+func Mul(tr *trace.Trace, a, b Value) (Value, error) {
+	units, ok := mulCheck(a.Units, b.Units)
+	if !ok {
+		return Value{}, overflow(tr, "mul", a, b)
+	}
+	out := Value{Units: units, Scale: a.Scale + b.Scale}
+	record(tr, "fixed.mul", a, b, out)
+	return out, nil
+}
+
+// Div records a / b at a's scale, truncating toward zero. Division by
+// zero is recorded as an error step.
+func Div(tr *trace.Trace, a, b Value) (Value, error) {
+	if b.Units == 0 {
+		err := fmt.Errorf("fixed: division of %s by zero", a)
+		tr.AddStep("fixed.div",
+			map[string]any{"a": a, "b": b}, nil,
+			trace.WithStepError(err))
+		return Value{}, err
+	}
+	// Widen the numerator by b's scale so the quotient lands on a's scale.
+	num, ok := rescale(a, a.Scale+b.Scale)
+	if !ok {
+		return Value{}, overflow(tr, "div", a, b)
+	}
+	out := Value{Units: num.Units / b.Units, Scale: a.Scale}
+	record(tr, "fixed.div", a, b, out)
+	return out, nil
+}
+
+func record(tr *trace.Trace, op string, a, b, out Value) {
+	tr.AddStep(op,
+		map[string]any{"a": a, "b": b},
+		map[string]any{"result": out, "units": out.Units, "scale": out.Scale})
+}
+
+func overflow(tr *trace.Trace, op string, a, b Value) error {
+	err := &ErrOverflow{Op: op, A: a, B: b}
+	tr.AddStep("fixed."+op,
+		map[string]any{"a": a, "b": b}, nil,
+		trace.WithStepError(err))
+	return err
+}
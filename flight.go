@@ -0,0 +1,137 @@
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FlightRecorder keeps the last N terminal traces per computation name
+// in memory, so the computations leading up to an incident can be
+// captured on demand — from a signal handler, a debug endpoint serving
+// Dump, or a deferred dump around a panic. It holds finished traces
+// only; a recorder is an observation ring, not a store.
+type FlightRecorder struct {
+	size int
+
+	mu    sync.Mutex
+	rings map[string]*flightRing
+}
+
+// flightRing is one name's bounded history, oldest at start.
+type flightRing struct {
+	traces []*Trace
+	start  int
+}
+
+// NewFlightRecorder keeps up to perName recent traces for each name.
+func NewFlightRecorder(perName int) *FlightRecorder {
+	if perName < 1 {
+		perName = 1
+	}
+	return &FlightRecorder{size: perName, rings: map[string]*flightRing{}}
+}
+
+// Record retains a finished trace, evicting the oldest of its name when
+// the ring is full. Traces still recording are ignored.
+func (r *FlightRecorder) Record(tr *Trace) {
+	if tr == nil || tr.Status == StatusRecording {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ring, ok := r.rings[tr.Name]
+	if !ok {
+		ring = &flightRing{}
+		r.rings[tr.Name] = ring
+	}
+	if len(ring.traces) < r.size {
+		ring.traces = append(ring.traces, tr)
+		return
+	}
+	ring.traces[ring.start] = tr
+	ring.start = (ring.start + 1) % r.size
+}
+
+// Recent returns a name's retained traces, oldest first.
+func (r *FlightRecorder) Recent(name string) []*Trace {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ring, ok := r.rings[name]
+	if !ok {
+		return nil
+	}
+	out := make([]*Trace, 0, len(ring.traces))
+	for i := 0; i < len(ring.traces); i++ {
+		out = append(out, ring.traces[(ring.start+i)%len(ring.traces)])
+	}
+	return out
+}
+
+// Names returns the computation names with retained traces, sorted.
+func (r *FlightRecorder) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make([]string, 0, len(r.rings))
+	for name := range r.rings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Dump writes everything the recorder holds as one JSON object, names
+// mapping to their traces oldest first — the payload for a debug
+// endpoint or an incident snapshot.
+func (r *FlightRecorder) Dump(w io.Writer) error {
+	out := map[string][]*Trace{}
+	for _, name := range r.Names() {
+		out[name] = r.Recent(name)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// DumpOnSignal dumps to a timestamped file under dir whenever the
+// signal arrives — typically SIGUSR1 — and keeps doing so until the
+// returned stop function is called.
+func (r *FlightRecorder) DumpOnSignal(sig os.Signal, dir string) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sig)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ch:
+				r.dumpFile(dir)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
+
+// dumpFile writes one timestamped dump; failures are reported on
+// stderr since a signal handler has no caller to return them to.
+func (r *FlightRecorder) dumpFile(dir string) {
+	path := fmt.Sprintf("%s/flight-%s.json", dir, time.Now().UTC().Format("20060102T150405"))
+	f, err := os.Create(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "trace: flight recorder dump: %v\n", err)
+		return
+	}
+	if err := r.Dump(f); err != nil {
+		fmt.Fprintf(os.Stderr, "trace: flight recorder dump: %v\n", err)
+	}
+	f.Close()
+}
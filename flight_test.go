@@ -0,0 +1,58 @@
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestFlightRecorderKeepsRecentPerName(t *testing.T) {
+	r := NewFlightRecorder(2)
+	for i := 0; i < 3; i++ {
+		tr := New("job", map[string]any{"i": i})
+		tr.Complete(i)
+		r.Record(tr)
+	}
+	other := New("other", nil)
+	other.Complete(nil)
+	r.Record(other)
+
+	recent := r.Recent("job")
+	if len(recent) != 2 {
+		t.Fatalf("%d recent traces, want ring of 2", len(recent))
+	}
+	if recent[0].Inputs["i"] != float64(1) && recent[0].Inputs["i"] != 1 {
+		t.Fatalf("oldest retained trace has i=%v, want 1", recent[0].Inputs["i"])
+	}
+	if names := r.Names(); len(names) != 2 || names[0] != "job" || names[1] != "other" {
+		t.Fatalf("Names: %v", names)
+	}
+}
+
+func TestFlightRecorderIgnoresRecordingTraces(t *testing.T) {
+	r := NewFlightRecorder(4)
+	tr := New("job", nil)
+	r.Record(tr) // still recording
+	r.Record(nil)
+	if got := r.Recent("job"); got != nil {
+		t.Fatalf("recorded a non-terminal trace: %v", got)
+	}
+}
+
+func TestFlightRecorderDump(t *testing.T) {
+	r := NewFlightRecorder(4)
+	tr := New("job", nil)
+	tr.Complete(1)
+	r.Record(tr)
+	var buf bytes.Buffer
+	if err := r.Dump(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var out map[string][]*Trace
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out["job"]) != 1 || out["job"][0].ID != tr.ID {
+		t.Fatalf("dump content: %v", out)
+	}
+}
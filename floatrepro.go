@@ -0,0 +1,73 @@
+package trace
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+)
+
+// machineEpsilon is the unit roundoff of IEEE-754 binary64: the relative
+// error bound of one correctly rounded operation.
+const machineEpsilon = 0x1p-52
+
+// WithFloatReproducibility records the floating-point environment in
+// metadata — Go version, architecture, whether the compiler may contract
+// multiply-adds into FMA on this architecture — and accumulates a rough
+// rounding-error estimate over the step chain: each float64 a step
+// produces contributes one unit roundoff of its magnitude. When the trace
+// finishes, the accumulated estimate lands in system.fp.tolerance, the
+// band within which a re-run on a different platform should agree.
+func WithFloatReproducibility() Option {
+	return func(tr *Trace) {
+		tr.fpTrack = true
+		tr.Metadata["system.fp.go_version"] = runtime.Version()
+		tr.Metadata["system.fp.goarch"] = runtime.GOARCH
+		tr.Metadata["system.fp.fma"] = fmaHint()
+		tr.Metadata["system.fp.model"] = "IEEE-754 binary64"
+	}
+}
+
+// fmaHint reports whether the compiler may fuse multiply-adds on this
+// architecture, which changes low-order bits relative to platforms that
+// round each operation.
+func fmaHint() string {
+	switch runtime.GOARCH {
+	case "arm64", "ppc64", "ppc64le", "s390x", "riscv64", "loong64":
+		return "contraction-possible"
+	default:
+		return "round-per-operation"
+	}
+}
+
+// accumulateFloatLocked adds one unit roundoff per float64 the step
+// produced.
+func (tr *Trace) accumulateFloatLocked(st *Step) {
+	if !tr.fpTrack {
+		return
+	}
+	for _, v := range st.Outputs {
+		if f, ok := v.(float64); ok && !math.IsNaN(f) && !math.IsInf(f, 0) {
+			tr.fpTolerance += math.Abs(f) * machineEpsilon
+		}
+	}
+}
+
+// finalizeFloatLocked folds the result into the estimate and records the
+// tolerance in metadata as the trace reaches a terminal state.
+func (tr *Trace) finalizeFloatLocked() {
+	if !tr.fpTrack {
+		return
+	}
+	if f, ok := tr.Result.(float64); ok && !math.IsNaN(f) && !math.IsInf(f, 0) {
+		tr.fpTolerance += math.Abs(f) * machineEpsilon
+	}
+	tr.Metadata["system.fp.tolerance"] = fmt.Sprintf("%g", tr.fpTolerance)
+}
+
+// ReproducibilityTolerance returns the accumulated rounding-error
+// estimate; zero when the trace does not track float reproducibility.
+func (tr *Trace) ReproducibilityTolerance() float64 {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	return tr.fpTolerance
+}
@@ -0,0 +1,56 @@
+package trace
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToMermaidGantt renders the trace as a Mermaid Gantt chart — one section
+// per origin, one task per step with its measured start and duration — a
+// schedule-like view of multi-phase computations. Failed steps are marked
+// critical; paste the output into any Mermaid renderer.
+func (tr *Trace) ToMermaidGantt() string {
+	tr.mu.Lock()
+	steps := make([]*Step, len(tr.Steps))
+	copy(steps, tr.Steps)
+	tr.mu.Unlock()
+
+	lanes, order := timelineLanes(steps)
+	start, _ := timelineSpan(steps)
+
+	var b strings.Builder
+	b.WriteString("gantt\n")
+	fmt.Fprintf(&b, "    title %s\n", sanitizeGanttText(tr.Name))
+	b.WriteString("    dateFormat x\n")
+	b.WriteString("    axisFormat %S.%Ls\n")
+	for _, lane := range order {
+		fmt.Fprintf(&b, "    section %s\n", sanitizeGanttText(lane))
+		for _, st := range lanes[lane] {
+			label := st.Operation
+			if st.Description != "" {
+				label += " — " + st.Description
+			}
+			tags := ""
+			if st.Err != "" {
+				tags = "crit, "
+			}
+			from := st.Time.Sub(start).Milliseconds()
+			ms := st.Duration.Milliseconds()
+			if ms < 1 {
+				ms = 1
+			}
+			fmt.Fprintf(&b, "    %s :%sstep%d, %d, %dms\n",
+				sanitizeGanttText(label), tags, st.Index, from, ms)
+		}
+	}
+	return b.String()
+}
+
+// sanitizeGanttText strips characters Mermaid treats as task-line syntax.
+func sanitizeGanttText(s string) string {
+	s = strings.NewReplacer(":", ";", "#", "", "\n", " ").Replace(s)
+	if s == "" {
+		s = "(unnamed)"
+	}
+	return s
+}
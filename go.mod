@@ -0,0 +1,3 @@
+module github.com/euclidtrace/trace
+
+go 1.21
@@ -0,0 +1,225 @@
+// Package graph runs computations declared as a DAG of named nodes and emits
+// a complete trace automatically: one step per node, with input references
+// wired from the node's dependencies, so instrumentation cannot drift from
+// the computation structure.
+package graph
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/euclidtrace/trace"
+)
+
+// NodeFunc computes one node's value from its named dependencies.
+type NodeFunc func(inputs map[string]any) (any, error)
+
+type node struct {
+	name        string
+	operation   string
+	description string
+	deps        []string
+	fn          NodeFunc
+}
+
+// Graph is a declarative computation: nodes with dependencies on trace
+// inputs or on other nodes. Declare nodes with Add, then call Run.
+type Graph struct {
+	name  string
+	nodes map[string]*node
+}
+
+// New creates an empty graph for the named computation.
+func New(name string) *Graph {
+	return &Graph{name: name, nodes: map[string]*node{}}
+}
+
+// NodeOption configures a declared node.
+type NodeOption func(*node)
+
+// WithOperation sets the operation recorded on the node's step; it defaults
+// to the node name.
+func WithOperation(op string) NodeOption {
+	return func(n *node) { n.operation = op }
+}
+
+// WithDescription sets the description recorded on the node's step.
+func WithDescription(d string) NodeOption {
+	return func(n *node) { n.description = d }
+}
+
+// Add declares a node. Dependencies name either trace inputs or other nodes;
+// their values are passed to fn under those names.
+func (g *Graph) Add(name string, fn NodeFunc, deps []string, opts ...NodeOption) error {
+	if _, exists := g.nodes[name]; exists {
+		return fmt.Errorf("graph: node %q declared twice", name)
+	}
+	n := &node{name: name, operation: name, deps: deps, fn: fn}
+	for _, opt := range opts {
+		opt(n)
+	}
+	g.nodes[name] = n
+	return nil
+}
+
+// RunOption configures one evaluation of a graph.
+type RunOption func(*runConfig)
+
+type runConfig struct {
+	workers int
+}
+
+// Parallel evaluates independent nodes concurrently on up to workers
+// goroutines. The emitted trace is guaranteed to be identical to sequential
+// execution: steps appear in topological-rank order, not completion order.
+func Parallel(workers int) RunOption {
+	return func(c *runConfig) { c.workers = workers }
+}
+
+// Run evaluates the graph over the inputs in topological order and returns
+// the resulting trace. Each node becomes one step whose Refs point at the
+// steps that produced its dependencies. The value of the graph's sink node
+// becomes the trace result; with several sinks the result is a map of their
+// values. Ready nodes are evaluated in name order, so the emitted trace is
+// deterministic — including under Parallel.
+func (g *Graph) Run(inputs map[string]any, opts ...RunOption) (*trace.Trace, error) {
+	var cfg runConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	tr := trace.New(g.name, inputs)
+	order, err := g.topoOrder(inputs)
+	if err != nil {
+		tr.Fail(err)
+		return tr, err
+	}
+
+	values := map[string]any{}
+	for k, v := range inputs {
+		values[k] = v
+	}
+
+	var results map[string]nodeResult
+	if cfg.workers > 1 {
+		results = g.evaluateParallel(order, values, cfg.workers)
+	} else {
+		results = evaluateSequential(order, values)
+	}
+
+	// Record steps strictly in topological order, stopping at the first
+	// failed node exactly as a sequential run would.
+	producedBy := map[string]int{} // node name -> step index
+	for _, n := range order {
+		res := results[n.name]
+		nodeInputs := map[string]any{}
+		var refs []int
+		for _, dep := range n.deps {
+			nodeInputs[dep] = values[dep]
+			if idx, ok := producedBy[dep]; ok {
+				refs = append(refs, idx)
+			}
+		}
+		if res.err != nil {
+			tr.AddStep(n.operation, nodeInputs, nil,
+				trace.WithRefs(refs...), trace.WithStepError(res.err), trace.WithDescription(n.description))
+			wrapped := fmt.Errorf("graph: node %q: %w", n.name, res.err)
+			tr.Fail(wrapped)
+			return tr, wrapped
+		}
+		st := tr.AddStep(n.operation, nodeInputs, map[string]any{n.name: res.value},
+			trace.WithRefs(refs...), trace.WithDescription(n.description))
+		values[n.name] = res.value
+		producedBy[n.name] = st.Index
+	}
+
+	sinks := g.sinks()
+	var result any
+	switch len(sinks) {
+	case 0:
+		result = nil
+	case 1:
+		result = values[sinks[0]]
+	default:
+		m := map[string]any{}
+		for _, s := range sinks {
+			m[s] = values[s]
+		}
+		result = m
+	}
+	tr.Complete(result)
+	return tr, nil
+}
+
+// topoOrder returns the nodes in a deterministic topological order (Kahn's
+// algorithm, breaking ties by node name), or an error on unknown
+// dependencies or cycles.
+func (g *Graph) topoOrder(inputs map[string]any) ([]*node, error) {
+	indegree := map[string]int{}
+	dependents := map[string][]string{}
+	for _, n := range g.nodes {
+		for _, dep := range n.deps {
+			if _, isNode := g.nodes[dep]; isNode {
+				indegree[n.name]++
+				dependents[dep] = append(dependents[dep], n.name)
+				continue
+			}
+			if _, isInput := inputs[dep]; !isInput {
+				return nil, fmt.Errorf("graph: node %q depends on %q, which is neither a node nor an input", n.name, dep)
+			}
+		}
+	}
+
+	var ready []string
+	for name := range g.nodes {
+		if indegree[name] == 0 {
+			ready = append(ready, name)
+		}
+	}
+	sort.Strings(ready)
+
+	var order []*node
+	for len(ready) > 0 {
+		name := ready[0]
+		ready = ready[1:]
+		order = append(order, g.nodes[name])
+		released := dependents[name]
+		sort.Strings(released)
+		for _, dep := range released {
+			indegree[dep]--
+			if indegree[dep] == 0 {
+				ready = insertSorted(ready, dep)
+			}
+		}
+	}
+	if len(order) != len(g.nodes) {
+		return nil, fmt.Errorf("graph: cycle among %d undischarged nodes", len(g.nodes)-len(order))
+	}
+	return order, nil
+}
+
+// sinks returns, in name order, the nodes no other node depends on.
+func (g *Graph) sinks() []string {
+	depended := map[string]bool{}
+	for _, n := range g.nodes {
+		for _, dep := range n.deps {
+			depended[dep] = true
+		}
+	}
+	var sinks []string
+	for name := range g.nodes {
+		if !depended[name] {
+			sinks = append(sinks, name)
+		}
+	}
+	sort.Strings(sinks)
+	return sinks
+}
+
+func insertSorted(names []string, name string) []string {
+	i := sort.SearchStrings(names, name)
+	names = append(names, "")
+	copy(names[i+1:], names[i:])
+	names[i] = name
+	return names
+}
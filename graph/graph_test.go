@@ -0,0 +1,125 @@
+package graph
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+func pricing(t *testing.T) *Graph {
+	t.Helper()
+	g := New("pricing")
+	add := func(name string, fn NodeFunc, deps []string, opts ...NodeOption) {
+		if err := g.Add(name, fn, deps, opts...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	add("tax", func(in map[string]any) (any, error) {
+		return in["gross"].(float64) * in["rate"].(float64), nil
+	}, []string{"gross", "rate"})
+	add("net", func(in map[string]any) (any, error) {
+		return in["gross"].(float64) - in["tax"].(float64), nil
+	}, []string{"gross", "tax"}, WithDescription("gross minus tax"))
+	return g
+}
+
+func TestRunWiresStepsFromTheDeclaration(t *testing.T) {
+	tr, err := pricing(t).Run(map[string]any{"gross": 100.0, "rate": 0.2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.Status != trace.StatusCompleted || tr.Result != 80.0 {
+		t.Fatalf("outcome %s %v", tr.Status, tr.Result)
+	}
+	if len(tr.Steps) != 2 || tr.Steps[0].Operation != "tax" || tr.Steps[1].Operation != "net" {
+		t.Fatalf("steps %+v", tr.Steps)
+	}
+	net := tr.Steps[1]
+	if len(net.Refs) != 1 || net.Refs[0] != 0 || net.Inputs["tax"] != 20.0 {
+		t.Fatalf("net step not wired from tax: %+v", net)
+	}
+	if net.Description != "gross minus tax" {
+		t.Fatalf("description %q", net.Description)
+	}
+	if err := tr.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParallelMatchesSequential(t *testing.T) {
+	run := func(opts ...RunOption) string {
+		g := New("fan")
+		for _, name := range []string{"a", "b", "c", "d"} {
+			name := name
+			if err := g.Add(name, func(in map[string]any) (any, error) {
+				return name + "-" + in["x"].(string), nil
+			}, []string{"x"}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		tr, err := g.Run(map[string]any{"x": "v"}, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tr.ContentHash()
+	}
+	if run() != run(Parallel(3)) {
+		t.Fatal("parallel evaluation changed the trace")
+	}
+}
+
+func TestRunFailsAtTheFirstFailedNode(t *testing.T) {
+	g := New("t")
+	boom := errors.New("bad rate")
+	if err := g.Add("tax", func(map[string]any) (any, error) { return nil, boom }, []string{"gross"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Add("net", func(map[string]any) (any, error) { return 0, nil }, []string{"tax"}); err != nil {
+		t.Fatal(err)
+	}
+	tr, err := g.Run(map[string]any{"gross": 1.0})
+	if err == nil || !errors.Is(err, boom) {
+		t.Fatalf("err %v", err)
+	}
+	if tr.Status != trace.StatusFailed || len(tr.Steps) != 1 || tr.Steps[0].Err == "" {
+		t.Fatalf("trace %s, steps %+v", tr.Status, tr.Steps)
+	}
+}
+
+func TestDeclarationErrors(t *testing.T) {
+	g := New("t")
+	if err := g.Add("a", func(map[string]any) (any, error) { return 1, nil }, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Add("a", nil, nil); err == nil {
+		t.Fatal("declared a node twice")
+	}
+	if err := g.Add("b", func(map[string]any) (any, error) { return 1, nil }, []string{"missing"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Run(nil); err == nil {
+		t.Fatal("ran with an unresolvable dependency")
+	}
+
+	cyclic := New("t")
+	_ = cyclic.Add("x", func(map[string]any) (any, error) { return 1, nil }, []string{"y"})
+	_ = cyclic.Add("y", func(map[string]any) (any, error) { return 1, nil }, []string{"x"})
+	if _, err := cyclic.Run(nil); err == nil {
+		t.Fatal("ran a cyclic graph")
+	}
+}
+
+func TestMultipleSinksYieldAMapResult(t *testing.T) {
+	g := New("t")
+	_ = g.Add("a", func(map[string]any) (any, error) { return 1, nil }, nil)
+	_ = g.Add("b", func(map[string]any) (any, error) { return 2, nil }, nil)
+	tr, err := g.Run(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, ok := tr.Result.(map[string]any)
+	if !ok || result["a"] != 1 || result["b"] != 2 {
+		t.Fatalf("result %v", tr.Result)
+	}
+}
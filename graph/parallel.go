@@ -0,0 +1,87 @@
+package graph
+
+import (
+	"fmt"
+	"sync"
+)
+
+// nodeResult is the outcome of evaluating one node.
+type nodeResult struct {
+	value any
+	err   error
+}
+
+// evaluateSequential runs the nodes one by one in topological order, filling
+// values with each node's output, and stops at the first failure.
+func evaluateSequential(order []*node, values map[string]any) map[string]nodeResult {
+	results := map[string]nodeResult{}
+	for _, n := range order {
+		nodeInputs := map[string]any{}
+		for _, dep := range n.deps {
+			nodeInputs[dep] = values[dep]
+		}
+		out, err := n.fn(nodeInputs)
+		results[n.name] = nodeResult{value: out, err: err}
+		if err != nil {
+			break
+		}
+		values[n.name] = out
+	}
+	return results
+}
+
+// evaluateParallel runs independent nodes concurrently on up to workers
+// goroutines, respecting dependency edges. Nodes downstream of a failed node
+// are not run; they inherit the dependency's error. values is only read for
+// graph inputs here — node outputs are communicated through results under
+// the mutex, and the caller merges them back in topological order.
+func (g *Graph) evaluateParallel(order []*node, values map[string]any, workers int) map[string]nodeResult {
+	var (
+		mu      sync.Mutex
+		results = map[string]nodeResult{}
+		done    = map[string]chan struct{}{}
+		sem     = make(chan struct{}, workers)
+		wg      sync.WaitGroup
+	)
+	for _, n := range order {
+		done[n.name] = make(chan struct{})
+	}
+
+	for _, n := range order {
+		n := n
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(done[n.name])
+
+			nodeInputs := map[string]any{}
+			for _, dep := range n.deps {
+				if ch, isNode := done[dep]; isNode {
+					<-ch
+					mu.Lock()
+					depRes := results[dep]
+					mu.Unlock()
+					if depRes.err != nil {
+						mu.Lock()
+						results[n.name] = nodeResult{err: fmt.Errorf("dependency %q failed: %w", dep, depRes.err)}
+						mu.Unlock()
+						return
+					}
+					nodeInputs[dep] = depRes.value
+				} else {
+					nodeInputs[dep] = values[dep]
+				}
+			}
+
+			sem <- struct{}{}
+			out, err := n.fn(nodeInputs)
+			<-sem
+
+			mu.Lock()
+			results[n.name] = nodeResult{value: out, err: err}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
+}
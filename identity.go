@@ -0,0 +1,77 @@
+package trace
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+)
+
+// Identity names the component that recorded a step: the service, the
+// acting principal, and the signing key, so multi-service composite
+// traces show which component produced each step.
+type Identity struct {
+	Service   string `json:"service,omitempty"`
+	Principal string `json:"principal,omitempty"`
+	KeyID     string `json:"key_id,omitempty"`
+}
+
+type identityKey struct{}
+
+// NewIdentityContext returns a context carrying the identity; steps
+// recorded with WithAuthorFromContext pick it up automatically.
+func NewIdentityContext(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// IdentityFromContext returns the identity carried by the context, if any.
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
+
+// WithAuthor records the step's author identity. Identity sits outside
+// the chain hash — it says who recorded the step, not what was computed —
+// and is bound to the step content by SignStep instead.
+func WithAuthor(id Identity) StepOption {
+	return func(st *Step) { st.Author = &id }
+}
+
+// WithAuthorFromContext records the identity carried by the context, if
+// one is present.
+func WithAuthorFromContext(ctx context.Context) StepOption {
+	return func(st *Step) {
+		if id, ok := IdentityFromContext(ctx); ok {
+			st.Author = &id
+		}
+	}
+}
+
+// SignStep signs the step's chain hash and author with the author's
+// Ed25519 key. The chain hash covers the step's content and position, so
+// the signature binds this author to this step in this trace.
+func SignStep(st *Step, key ed25519.PrivateKey) error {
+	if st.Author == nil {
+		return fmt.Errorf("trace: step %d: sign: no author identity", st.Index)
+	}
+	st.AuthorSig = ed25519.Sign(key, st.signedContent())
+	return nil
+}
+
+// VerifyStepAuthor checks the step's author signature against the public
+// key for the author's key ID.
+func VerifyStepAuthor(st *Step, pub ed25519.PublicKey) error {
+	if st.Author == nil {
+		return fmt.Errorf("trace: step %d: no author identity", st.Index)
+	}
+	if len(st.AuthorSig) == 0 {
+		return fmt.Errorf("trace: step %d: author %s unsigned", st.Index, st.Author.KeyID)
+	}
+	if !ed25519.Verify(pub, st.signedContent(), st.AuthorSig) {
+		return fmt.Errorf("trace: step %d: author signature by key %q does not verify", st.Index, st.Author.KeyID)
+	}
+	return nil
+}
+
+func (st *Step) signedContent() []byte {
+	return []byte(fmt.Sprintf("%s|%s|%s|%s", st.Hash, st.Author.Service, st.Author.Principal, st.Author.KeyID))
+}
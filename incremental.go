@@ -0,0 +1,105 @@
+package trace
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Segment is one sealed interval of an incremental computation: the
+// interval's completed trace plus a chain hash binding it to every
+// segment before it, so a continuously updated result has an auditable
+// derivation per interval and the sequence of intervals cannot be
+// reordered or thinned without detection.
+type Segment struct {
+	Seq         int    `json:"seq"`
+	Trace       *Trace `json:"trace"`
+	ContentHash string `json:"content_hash"`
+	PrevChain   string `json:"prev_chain,omitempty"`
+	Chain       string `json:"chain"`
+}
+
+// Incremental records a long-lived computation — a running aggregate, a
+// streaming fold — as a chain of sealed segments. Steps go to the current
+// interval's trace; Seal completes the interval, chains it to its
+// predecessors, and opens the next one.
+type Incremental struct {
+	name    string
+	seq     int
+	chain   string
+	current *Trace
+}
+
+// NewIncremental begins an incremental computation. Inputs seed the first
+// interval's trace.
+func NewIncremental(name string, inputs map[string]any, opts ...Option) *Incremental {
+	inc := &Incremental{name: name}
+	inc.current = inc.open(inputs, opts...)
+	return inc
+}
+
+// Trace returns the current interval's recording trace.
+func (inc *Incremental) Trace() *Trace {
+	return inc.current
+}
+
+// Seal completes the current interval with its result, chains the sealed
+// segment to the previous one, and opens the next interval carrying the
+// running result forward as an input.
+func (inc *Incremental) Seal(result any, opts ...Option) *Segment {
+	inc.current.Complete(result)
+	seg := &Segment{
+		Seq:         inc.seq,
+		Trace:       inc.current,
+		ContentHash: inc.current.ContentHash(),
+		PrevChain:   inc.chain,
+	}
+	seg.Chain = segmentChain(seg.PrevChain, seg.ContentHash)
+	inc.chain = seg.Chain
+	inc.seq++
+	inc.current = inc.open(map[string]any{"carried_result": result}, opts...)
+	return seg
+}
+
+// open starts the trace for one interval, stamping its position in the
+// chain into metadata so a segment trace is self-describing.
+func (inc *Incremental) open(inputs map[string]any, opts ...Option) *Trace {
+	tr := New(fmt.Sprintf("%s#%d", inc.name, inc.seq), inputs, opts...)
+	tr.SetMetadata("system.segment_seq", fmt.Sprintf("%d", inc.seq))
+	if inc.chain != "" {
+		tr.SetMetadata("system.segment_prev", inc.chain)
+	}
+	return tr
+}
+
+func segmentChain(prev, contentHash string) string {
+	h := sha256.Sum256([]byte(prev + "|" + contentHash))
+	return hex.EncodeToString(h[:])
+}
+
+// VerifySegments checks a sequence of sealed segments: sequential
+// numbering, each trace verifying and matching its recorded content hash,
+// and the chain hashes linking correctly, so a dropped, reordered, or
+// edited interval is detected.
+func VerifySegments(segments []*Segment) error {
+	prev := ""
+	for i, seg := range segments {
+		if seg.Seq != i {
+			return fmt.Errorf("trace: segment at position %d carries seq %d", i, seg.Seq)
+		}
+		if err := seg.Trace.Verify(); err != nil {
+			return fmt.Errorf("trace: segment %d: %w", i, err)
+		}
+		if got := seg.Trace.ContentHash(); got != seg.ContentHash {
+			return fmt.Errorf("trace: segment %d content hash mismatch: recorded %s, trace is %s", i, seg.ContentHash, got)
+		}
+		if seg.PrevChain != prev {
+			return fmt.Errorf("trace: segment %d chains to %s, previous segment is %s", i, seg.PrevChain, prev)
+		}
+		if got := segmentChain(seg.PrevChain, seg.ContentHash); got != seg.Chain {
+			return fmt.Errorf("trace: segment %d chain hash mismatch", i)
+		}
+		prev = seg.Chain
+	}
+	return nil
+}
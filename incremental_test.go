@@ -0,0 +1,45 @@
+package trace
+
+import "testing"
+
+func TestIncrementalChainsSegments(t *testing.T) {
+	inc := NewIncremental("running-sum", map[string]any{"seed": 0})
+	var segments []*Segment
+	sum := 0
+	for i := 1; i <= 3; i++ {
+		sum += i
+		inc.Trace().AddStep("add", map[string]any{"x": i}, map[string]any{"sum": sum})
+		segments = append(segments, inc.Seal(sum))
+	}
+	if err := VerifySegments(segments); err != nil {
+		t.Fatal(err)
+	}
+	if segments[1].PrevChain != segments[0].Chain {
+		t.Fatal("segments do not chain")
+	}
+	// The next interval carries the running result forward.
+	if inc.Trace().Inputs["carried_result"] != 6 {
+		t.Fatalf("carried inputs %v", inc.Trace().Inputs)
+	}
+	if inc.Trace().Metadata["system.segment_prev"] != segments[2].Chain {
+		t.Fatal("open interval does not record its chain position")
+	}
+}
+
+func TestVerifySegmentsDetectsThinningAndEdits(t *testing.T) {
+	inc := NewIncremental("s", nil)
+	var segments []*Segment
+	for i := 0; i < 3; i++ {
+		segments = append(segments, inc.Seal(i))
+	}
+	if err := VerifySegments([]*Segment{segments[0], segments[2]}); err == nil {
+		t.Fatal("dropped segment went undetected")
+	}
+	if err := VerifySegments([]*Segment{segments[1], segments[0]}); err == nil {
+		t.Fatal("reordered segments went undetected")
+	}
+	segments[1].ContentHash = segments[0].ContentHash
+	if err := VerifySegments(segments); err == nil {
+		t.Fatal("edited content hash went undetected")
+	}
+}
@@ -0,0 +1,89 @@
+// Package interval provides interval arithmetic values and traced
+// operations on them, so numeric traces carry rigorous error bounds
+// through every step and the final result comes with a guaranteed
+// enclosure rather than a point estimate.
+package interval
+
+import (
+	"fmt"
+	"math"
+)
+
+// Interval is a closed real interval [Lo, Hi]. Every operation returns an
+// interval guaranteed to contain the true result whenever the operands
+// contain their true values.
+type Interval struct {
+	Lo float64 `json:"lo"`
+	Hi float64 `json:"hi"`
+}
+
+// New returns [lo, hi], normalizing a reversed pair.
+func New(lo, hi float64) Interval {
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	return Interval{Lo: lo, Hi: hi}
+}
+
+// Point returns the degenerate interval [x, x].
+func Point(x float64) Interval {
+	return Interval{Lo: x, Hi: x}
+}
+
+func (iv Interval) String() string {
+	return fmt.Sprintf("[%g, %g]", iv.Lo, iv.Hi)
+}
+
+// Width returns Hi - Lo, the bound on the result's error.
+func (iv Interval) Width() float64 {
+	return iv.Hi - iv.Lo
+}
+
+// Mid returns the interval's midpoint.
+func (iv Interval) Mid() float64 {
+	return (iv.Lo + iv.Hi) / 2
+}
+
+// Contains reports whether x lies in the interval.
+func (iv Interval) Contains(x float64) bool {
+	return iv.Lo <= x && x <= iv.Hi
+}
+
+// ContainsZero reports whether the interval spans zero.
+func (iv Interval) ContainsZero() bool {
+	return iv.Contains(0)
+}
+
+// Add returns the enclosure of a + b.
+func (iv Interval) Add(other Interval) Interval {
+	return Interval{Lo: iv.Lo + other.Lo, Hi: iv.Hi + other.Hi}
+}
+
+// Sub returns the enclosure of a - b.
+func (iv Interval) Sub(other Interval) Interval {
+	return Interval{Lo: iv.Lo - other.Hi, Hi: iv.Hi - other.Lo}
+}
+
+// Mul returns the enclosure of a * b: the extrema over all endpoint
+// products.
+func (iv Interval) Mul(other Interval) Interval {
+	products := [4]float64{
+		iv.Lo * other.Lo, iv.Lo * other.Hi,
+		iv.Hi * other.Lo, iv.Hi * other.Hi,
+	}
+	lo, hi := products[0], products[0]
+	for _, p := range products[1:] {
+		lo = math.Min(lo, p)
+		hi = math.Max(hi, p)
+	}
+	return Interval{Lo: lo, Hi: hi}
+}
+
+// Div returns the enclosure of a / b. Division by an interval spanning
+// zero has no finite enclosure and is reported as an error.
+func (iv Interval) Div(other Interval) (Interval, error) {
+	if other.ContainsZero() {
+		return Interval{}, fmt.Errorf("interval: division by %s, which spans zero", other)
+	}
+	return iv.Mul(Interval{Lo: 1 / other.Hi, Hi: 1 / other.Lo}), nil
+}
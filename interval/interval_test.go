@@ -0,0 +1,65 @@
+package interval
+
+import (
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+func TestIntervalArithmetic(t *testing.T) {
+	a, b := New(1, 2), New(3, 5)
+	if got := a.Add(b); got != New(4, 7) {
+		t.Fatalf("add %v", got)
+	}
+	if got := a.Sub(b); got != New(-4, -1) {
+		t.Fatalf("sub %v", got)
+	}
+	// Multiplication takes extrema over endpoint products, including
+	// sign flips.
+	if got := New(-2, 3).Mul(New(-1, 4)); got != New(-8, 12) {
+		t.Fatalf("mul %v", got)
+	}
+	q, err := a.Div(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q != New(1.0/5, 2.0/3) {
+		t.Fatalf("div %v", q)
+	}
+	if _, err := a.Div(New(-1, 1)); err == nil {
+		t.Fatal("divided by an interval spanning zero")
+	}
+}
+
+func TestIntervalQueries(t *testing.T) {
+	if New(2, 1) != New(1, 2) {
+		t.Fatal("reversed pair not normalized")
+	}
+	iv := New(1, 3)
+	if iv.Width() != 2 || iv.Mid() != 2 || !iv.Contains(3) || iv.ContainsZero() {
+		t.Fatalf("queries on %v", iv)
+	}
+	if p := Point(5); p.Width() != 0 || !p.Contains(5) {
+		t.Fatalf("point %v", p)
+	}
+}
+
+func TestTracedOperationsRecordWidths(t *testing.T) {
+	tr := trace.New("bounds", nil)
+	sum := Add(tr, New(1, 2), New(3, 5))
+	Mul(tr, sum, Point(2))
+	st := tr.Steps[0]
+	if st.Operation != "interval.add" || st.Outputs["width"] != 3.0 {
+		t.Fatalf("step %+v", st)
+	}
+	if _, err := Div(tr, New(1, 2), New(-1, 1)); err == nil {
+		t.Fatal("traced division by a zero-spanning interval")
+	}
+	if tr.Steps[2].Err == "" {
+		t.Fatal("error step not recorded")
+	}
+	tr.Complete(nil)
+	if err := tr.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,50 @@
+package interval
+
+import (
+	"github.com/euclidtrace/trace"
+)
+
+// Traced operations record each interval computation as a step whose
+// outputs carry the result and its width, so the growth of the error
+// bound is visible step by step.
+
+// Add records a + b on the trace and returns the enclosure.
+func Add(tr *trace.Trace, a, b Interval) Interval {
+	out := a.Add(b)
+	record(tr, "interval.add", a, b, out)
+	return out
+}
+
+// Sub records a - b on the trace and returns the enclosure.
+func Sub(tr *trace.Trace, a, b Interval) Interval {
+	out := a.Sub(b)
+	record(tr, "interval.sub", a, b, out)
+	return out
+}
+
+// Mul records a * b on the trace and returns the enclosure.
+func Mul(tr *trace.Trace, a, b Interval) Interval {
+	out := a.Mul(b)
+	record(tr, "interval.mul", a, b, out)
+	return out
+}
+
+// Div records a / b on the trace. Division by an interval spanning zero
+// records an error step and returns the error.
+func Div(tr *trace.Trace, a, b Interval) (Interval, error) {
+	out, err := a.Div(b)
+	if err != nil {
+		tr.AddStep("interval.div",
+			map[string]any{"a": a, "b": b}, nil,
+			trace.WithStepError(err))
+		return Interval{}, err
+	}
+	record(tr, "interval.div", a, b, out)
+	return out, nil
+}
+
+func record(tr *trace.Trace, op string, a, b, out Interval) {
+	tr.AddStep(op,
+		map[string]any{"a": a, "b": b},
+		map[string]any{"result": out, "width": out.Width()})
+}
@@ -0,0 +1,28 @@
+package trace
+
+// Seq and Seq2 mirror the standard library's iter.Seq and iter.Seq2. They
+// are declared here because the module still builds with Go 1.21; once the
+// toolchain baseline reaches 1.23 these become aliases and every iterator in
+// the module is directly rangeable.
+type Seq[V any] func(yield func(V) bool)
+
+// Seq2 is the two-value form of Seq.
+type Seq2[K, V any] func(yield func(K, V) bool)
+
+// AllSteps returns an iterator over the trace's steps in index order,
+// yielding (index, step). It snapshots the step list, so the computation may
+// keep recording while analysis code streams through it without
+// materializing further slices.
+func (tr *Trace) AllSteps() Seq2[int, *Step] {
+	tr.mu.Lock()
+	steps := make([]*Step, len(tr.Steps))
+	copy(steps, tr.Steps)
+	tr.mu.Unlock()
+	return func(yield func(int, *Step) bool) {
+		for i, st := range steps {
+			if !yield(i, st) {
+				return
+			}
+		}
+	}
+}
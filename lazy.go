@@ -0,0 +1,56 @@
+package trace
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+)
+
+// LazyValue defers producing an expensive value until a trace actually
+// needs its bytes. The producer runs at most once, the first time the
+// value is hashed or serialized — which is the moment its step enters a
+// recorded trace. Steps offered to traces that never record them — a
+// sampled-out trace from a Collector, a shed step under backpressure —
+// never invoke the producer, so the fast path pays nothing:
+//
+//	tr.AddStep("plan", nil, map[string]any{
+//		"plan": trace.NewLazyValue(func() any { return explain(query) }),
+//	})
+//
+// Once materialized, a lazy value hashes and serializes exactly as its
+// payload would, so a reloaded trace verifies against the same chain.
+type LazyValue struct {
+	once sync.Once
+	done atomic.Bool
+	fn   func() any
+	v    any
+}
+
+// NewLazyValue wraps a producer whose result is materialized on first
+// use. The producer must be deterministic for the lifetime of the
+// trace: hashing and serialization may run at different times and must
+// see the same payload.
+func NewLazyValue(fn func() any) *LazyValue {
+	return &LazyValue{fn: fn}
+}
+
+// Value materializes and returns the payload.
+func (l *LazyValue) Value() any {
+	l.once.Do(func() {
+		l.v = l.fn()
+		l.fn = nil
+		l.done.Store(true)
+	})
+	return l.v
+}
+
+// Materialized reports whether the producer has run.
+func (l *LazyValue) Materialized() bool {
+	return l.done.Load()
+}
+
+// MarshalJSON serializes the materialized payload, so exported traces
+// carry the real value and reloading needs no knowledge of laziness.
+func (l *LazyValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.Value())
+}
@@ -0,0 +1,67 @@
+package trace
+
+import "testing"
+
+func TestLazyValueMaterializesOnce(t *testing.T) {
+	calls := 0
+	lv := NewLazyValue(func() any { calls++; return 42 })
+	if lv.Materialized() {
+		t.Fatal("materialized before first use")
+	}
+	if got := lv.Value(); got != 42 {
+		t.Fatalf("Value() = %v", got)
+	}
+	lv.Value()
+	if calls != 1 {
+		t.Fatalf("producer ran %d times", calls)
+	}
+	if !lv.Materialized() {
+		t.Fatal("not marked materialized")
+	}
+}
+
+func TestLazyValueHashesAsItsPayload(t *testing.T) {
+	lv := NewLazyValue(func() any { return map[string]any{"rows": 3} })
+	if HashValue(lv) != HashValue(map[string]any{"rows": 3}) {
+		t.Fatal("lazy value hashes differently from its payload")
+	}
+}
+
+func TestLazyValueRoundTripsThroughJSON(t *testing.T) {
+	tr := New("t", nil)
+	tr.AddStep("load", nil, map[string]any{
+		"table": NewLazyValue(func() any { return []any{1.0, 2.0} }),
+	})
+	tr.Complete(nil)
+	data, err := tr.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cp, err := FromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp.ContentHash() != tr.ContentHash() {
+		t.Fatal("content hash changed across round trip")
+	}
+	if err := cp.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLazyValueNeverRunsForSampledOutTraces(t *testing.T) {
+	c := NewCollector(1, WithSampleKeep(1<<30)) // over budget, drop everything
+	held := c.New("held", nil)
+	defer held.Complete(nil)
+	tr := c.New("job", nil)
+	if tr.Status != StatusAborted {
+		t.Fatal("expected a sampled-out trace")
+	}
+	ran := false
+	tr.AddStep("expensive", nil, map[string]any{
+		"dump": NewLazyValue(func() any { ran = true; return "huge" }),
+	})
+	if ran {
+		t.Fatal("producer ran for a trace that records nothing")
+	}
+}
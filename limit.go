@@ -0,0 +1,136 @@
+package trace
+
+import (
+	"sort"
+	"time"
+)
+
+// LimitMode selects what happens to steps recorded past a trace's step
+// limit; see WithStepLimit.
+type LimitMode string
+
+const (
+	// LimitDrop discards steps past the limit and records how many were
+	// dropped when the trace finishes.
+	LimitDrop LimitMode = "drop"
+	// LimitKeepEnds keeps the first steps up to the limit minus a tail
+	// reservation, plus the most recent steps, eliding the middle with a
+	// marker — the shape of the computation's start and finish survives.
+	LimitKeepEnds LimitMode = "keep-ends"
+	// LimitSummarize aggregates steps past the limit into one summary step
+	// with per-operation counts.
+	LimitSummarize LimitMode = "summarize"
+)
+
+// WithStepLimit caps the number of recorded steps and selects the policy
+// applied past the cap, so extremely long computations degrade gracefully
+// instead of growing without bound or erroring. LimitKeepEnds reserves a
+// tenth of the limit (at least one step) for the tail; WithStepLimitTail
+// overrides that.
+func WithStepLimit(max int, mode LimitMode) Option {
+	return func(tr *Trace) {
+		tr.limit = max
+		tr.limitMode = mode
+	}
+}
+
+// WithStepLimitTail sets how many trailing steps LimitKeepEnds retains.
+func WithStepLimitTail(m int) Option {
+	return func(tr *Trace) { tr.limitTail = m }
+}
+
+type tailStep struct {
+	operation string
+	inputs    map[string]any
+	outputs   map[string]any
+	opts      []StepOption
+	time      time.Time
+}
+
+// limitState tracks overflow bookkeeping for a step-limited trace.
+type limitState struct {
+	dropped    int
+	droppedOps map[string]int
+	tail       []tailStep
+}
+
+// admitStepLocked decides whether a step may be recorded inline; when it
+// returns false the step has been accounted for under the limit policy.
+func (tr *Trace) admitStepLocked(operation string, inputs, outputs map[string]any, opts []StepOption) bool {
+	if tr.limit <= 0 {
+		return true
+	}
+	head := tr.limit
+	if tr.limitMode == LimitKeepEnds {
+		head = tr.limit - tr.tailSize()
+	}
+	if len(tr.Steps) < head {
+		return true
+	}
+	tr.overflow.dropped++
+	switch tr.limitMode {
+	case LimitSummarize:
+		if tr.overflow.droppedOps == nil {
+			tr.overflow.droppedOps = map[string]int{}
+		}
+		tr.overflow.droppedOps[operation]++
+	case LimitKeepEnds:
+		tr.overflow.tail = append(tr.overflow.tail, tailStep{
+			operation: operation, inputs: inputs, outputs: outputs, opts: opts, time: time.Now(),
+		})
+		if len(tr.overflow.tail) > tr.tailSize() {
+			tr.overflow.tail = tr.overflow.tail[1:]
+		}
+	}
+	return false
+}
+
+func (tr *Trace) tailSize() int {
+	if tr.limitTail > 0 {
+		return tr.limitTail
+	}
+	m := tr.limit / 10
+	if m < 1 {
+		m = 1
+	}
+	return m
+}
+
+// finalizeLimitLocked materializes the overflow bookkeeping when the trace
+// finishes: the retained tail, an elision marker, or a summary step.
+func (tr *Trace) finalizeLimitLocked() {
+	if tr.limit <= 0 || tr.overflow.dropped == 0 {
+		return
+	}
+	switch tr.limitMode {
+	case LimitKeepEnds:
+		elided := tr.overflow.dropped - len(tr.overflow.tail)
+		if elided > 0 {
+			tr.addStepLocked("trace.steps_elided",
+				map[string]any{"count": elided}, nil,
+				WithDescription("middle steps elided by step limit"))
+		}
+		for _, ts := range tr.overflow.tail {
+			st := tr.addStepLocked(ts.operation, ts.inputs, ts.outputs, ts.opts...)
+			st.Time = ts.time
+		}
+		tr.overflow.tail = nil
+	case LimitSummarize:
+		ops := make([]string, 0, len(tr.overflow.droppedOps))
+		for op := range tr.overflow.droppedOps {
+			ops = append(ops, op)
+		}
+		sort.Strings(ops)
+		counts := map[string]any{"count": tr.overflow.dropped}
+		for _, op := range ops {
+			counts["op."+op] = tr.overflow.droppedOps[op]
+		}
+		tr.addStepLocked("trace.steps_summary", nil, counts,
+			WithDescription("steps past the limit aggregated into a summary"))
+	default: // LimitDrop
+		tr.addStepLocked("trace.steps_dropped",
+			map[string]any{"count": tr.overflow.dropped}, nil,
+			WithDescription("steps dropped by step limit"))
+	}
+	tr.overflow.dropped = 0
+}
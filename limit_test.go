@@ -0,0 +1,68 @@
+package trace
+
+import "testing"
+
+func TestStepLimitDrop(t *testing.T) {
+	tr := New("t", nil, WithStepLimit(3, LimitDrop))
+	for i := 0; i < 10; i++ {
+		tr.AddStep("iter", map[string]any{"i": i}, nil)
+	}
+	tr.Complete(nil)
+	if len(tr.Steps) != 4 { // 3 retained + the dropped-count marker
+		t.Fatalf("%d steps: %+v", len(tr.Steps), tr.Steps)
+	}
+	last := tr.Steps[3]
+	if last.Inputs["count"] != 7 {
+		t.Fatalf("drop marker: %s %v", last.Operation, last.Inputs)
+	}
+	if err := tr.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStepLimitKeepEnds(t *testing.T) {
+	tr := New("t", nil, WithStepLimit(4, LimitKeepEnds), WithStepLimitTail(2))
+	for i := 0; i < 10; i++ {
+		tr.AddStep("iter", map[string]any{"i": i}, nil)
+	}
+	tr.Complete(nil)
+	// Head up to the limit, an elision marker, then the retained tail.
+	var elided bool
+	for _, st := range tr.Steps {
+		if st.Operation == "trace.steps_elided" {
+			elided = true
+		}
+	}
+	if !elided {
+		t.Fatalf("no elision marker in %+v", tr.Steps)
+	}
+	lastIter := tr.Steps[len(tr.Steps)-1]
+	if lastIter.Inputs["i"] != 9 {
+		t.Fatalf("tail does not end at the last step: %v", lastIter.Inputs)
+	}
+	if err := tr.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStepLimitSummarize(t *testing.T) {
+	tr := New("t", nil, WithStepLimit(2, LimitSummarize))
+	for i := 0; i < 5; i++ {
+		tr.AddStep("iter", nil, nil)
+	}
+	tr.AddStep("other", nil, nil)
+	tr.Complete(nil)
+	last := tr.Steps[len(tr.Steps)-1]
+	if len(tr.Steps) != 3 {
+		t.Fatalf("%d steps", len(tr.Steps))
+	}
+	if last.Operation != "trace.steps_summary" {
+		t.Fatalf("summary step operation %q", last.Operation)
+	}
+	if last.Outputs["count"] != 4 || last.Outputs["op.iter"] != 3 || last.Outputs["op.other"] != 1 {
+		t.Fatalf("summary step outputs: %#v", last.Outputs)
+	}
+	if err := tr.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
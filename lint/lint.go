@@ -0,0 +1,175 @@
+// Package lint checks traces for explainability defects — inputs that never
+// feed a step, outputs nothing consumes, missing descriptions — producing
+// structured findings that CI can gate on. Lint findings are about trace
+// quality, not correctness; a trace with findings may still verify.
+package lint
+
+import (
+	"fmt"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Severity ranks a finding.
+type Severity string
+
+const (
+	SeverityInfo    Severity = "info"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// Finding is one rule violation located in a trace.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	// StepIndex locates the offending step, or -1 for trace-level findings.
+	StepIndex int    `json:"step_index"`
+	Message   string `json:"message"`
+}
+
+// Check runs every lint rule over the trace and returns the findings in
+// step order, trace-level findings first.
+func Check(tr *trace.Trace) []Finding {
+	var findings []Finding
+	findings = append(findings, checkUnusedInputs(tr)...)
+	findings = append(findings, checkUnconsumedOutputs(tr)...)
+	findings = append(findings, checkDescriptions(tr)...)
+	findings = append(findings, checkDuplicates(tr)...)
+	findings = append(findings, checkTimestamps(tr)...)
+	return findings
+}
+
+// checkUnusedInputs flags declared trace inputs that no step consumed,
+// matching by input name or by value hash.
+func checkUnusedInputs(tr *trace.Trace) []Finding {
+	var findings []Finding
+	for name, value := range tr.Inputs {
+		hash := trace.HashValue(value)
+		used := false
+		for _, st := range tr.Steps {
+			for k, v := range st.Inputs {
+				if k == name || trace.HashValue(v) == hash {
+					used = true
+					break
+				}
+			}
+			if used {
+				break
+			}
+		}
+		if !used {
+			findings = append(findings, Finding{
+				Rule:      "unused-input",
+				Severity:  SeverityWarning,
+				StepIndex: -1,
+				Message:   fmt.Sprintf("declared input %q is never consumed by any step", name),
+			})
+		}
+	}
+	return findings
+}
+
+// checkUnconsumedOutputs flags step outputs that no later step consumed and
+// that do not match the final result.
+func checkUnconsumedOutputs(tr *trace.Trace) []Finding {
+	var findings []Finding
+	resultHash := trace.HashValue(tr.Result)
+	for i, st := range tr.Steps {
+		for name, value := range st.Outputs {
+			hash := trace.HashValue(value)
+			if hash == resultHash {
+				continue
+			}
+			consumed := false
+			for _, later := range tr.Steps[i+1:] {
+				for _, ref := range later.Refs {
+					if ref == st.Index {
+						consumed = true
+					}
+				}
+				for _, v := range later.Inputs {
+					if trace.HashValue(v) == hash {
+						consumed = true
+					}
+				}
+				if consumed {
+					break
+				}
+			}
+			if !consumed {
+				findings = append(findings, Finding{
+					Rule:      "unconsumed-output",
+					Severity:  SeverityWarning,
+					StepIndex: st.Index,
+					Message:   fmt.Sprintf("output %q of step %d (%s) is never consumed and is not the result", name, st.Index, st.Operation),
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// checkDescriptions flags steps without a human-readable description.
+func checkDescriptions(tr *trace.Trace) []Finding {
+	var findings []Finding
+	for _, st := range tr.Steps {
+		if st.Description == "" {
+			findings = append(findings, Finding{
+				Rule:      "missing-description",
+				Severity:  SeverityInfo,
+				StepIndex: st.Index,
+				Message:   fmt.Sprintf("step %d (%s) has no description", st.Index, st.Operation),
+			})
+		}
+	}
+	return findings
+}
+
+// checkDuplicates flags runs of consecutive steps with the same operation
+// and identical input values, a common sign of accidental double recording.
+func checkDuplicates(tr *trace.Trace) []Finding {
+	var findings []Finding
+	for i := 1; i < len(tr.Steps); i++ {
+		prev, cur := tr.Steps[i-1], tr.Steps[i]
+		if cur.Operation == prev.Operation && sameValues(cur.Inputs, prev.Inputs) {
+			findings = append(findings, Finding{
+				Rule:      "duplicate-step",
+				Severity:  SeverityWarning,
+				StepIndex: cur.Index,
+				Message:   fmt.Sprintf("step %d repeats step %d (%s) with identical inputs", cur.Index, prev.Index, cur.Operation),
+			})
+		}
+	}
+	return findings
+}
+
+// checkTimestamps flags steps recorded out of time order.
+func checkTimestamps(tr *trace.Trace) []Finding {
+	var findings []Finding
+	for i := 1; i < len(tr.Steps); i++ {
+		prev, cur := tr.Steps[i-1], tr.Steps[i]
+		if cur.Time.Before(prev.Time) {
+			findings = append(findings, Finding{
+				Rule:      "non-monotonic-timestamp",
+				Severity:  SeverityWarning,
+				StepIndex: cur.Index,
+				Message:   fmt.Sprintf("step %d is timestamped before step %d", cur.Index, prev.Index),
+			})
+		}
+	}
+	return findings
+}
+
+func sameValues(a, b map[string]any) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		bv, ok := b[k]
+		if !ok || trace.HashValue(v) != trace.HashValue(bv) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,76 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+func findingsByRule(findings []Finding) map[string][]Finding {
+	out := map[string][]Finding{}
+	for _, f := range findings {
+		out[f.Rule] = append(out[f.Rule], f)
+	}
+	return out
+}
+
+func TestCheckFlagsExplainabilityDefects(t *testing.T) {
+	tr := trace.New("t", map[string]any{"used": 1, "forgotten": 2})
+	tr.AddStep("load", map[string]any{"used": 1}, map[string]any{"dangling": 99})
+	tr.AddStep("work", map[string]any{"k": 5}, map[string]any{"v": 6})
+	tr.AddStep("work", map[string]any{"k": 5}, map[string]any{"v": 6})
+	tr.Complete(6)
+
+	byRule := findingsByRule(Check(tr))
+	if got := byRule["unused-input"]; len(got) != 1 || got[0].StepIndex != -1 {
+		t.Fatalf("unused-input %v", got)
+	}
+	// "dangling" feeds nothing and is not the result; "v" matches the result.
+	if got := byRule["unconsumed-output"]; len(got) != 1 || got[0].StepIndex != 0 {
+		t.Fatalf("unconsumed-output %v", got)
+	}
+	if got := byRule["missing-description"]; len(got) != 3 || got[0].Severity != SeverityInfo {
+		t.Fatalf("missing-description %v", got)
+	}
+	if got := byRule["duplicate-step"]; len(got) != 1 || got[0].StepIndex != 2 {
+		t.Fatalf("duplicate-step %v", got)
+	}
+}
+
+func TestCheckCleanTrace(t *testing.T) {
+	tr := trace.New("t", map[string]any{"x": 1})
+	tr.AddStep("double", map[string]any{"x": 1}, map[string]any{"y": 2},
+		trace.WithDescription("double the input"))
+	tr.Complete(2)
+	if findings := Check(tr); len(findings) != 0 {
+		t.Fatalf("findings on a clean trace: %v", findings)
+	}
+}
+
+func TestScoreBreakdown(t *testing.T) {
+	tr := trace.New("t", map[string]any{"x": 1})
+	tr.SetUserMetadata("owner", "pricing")
+	tr.SetUserMetadata("ticket", "T-1")
+	tr.SetUserMetadata("review", "r2")
+	tr.AddStep("load", map[string]any{"x": 1}, map[string]any{"y": 2},
+		trace.WithDescription("load"))
+	tr.AddStep("double", map[string]any{"y": 2}, map[string]any{"z": 4},
+		trace.WithDescription("double"), trace.WithRefs(0))
+	tr.Assert("bounded", true, "")
+	tr.Complete(4)
+
+	b := Score(tr)
+	if b.Descriptions != 23 { // 2 of 3 steps described: 35 * 2/3, rounded
+		t.Fatalf("descriptions %d", b.Descriptions)
+	}
+	if b.Assertions != 20 || b.Metadata != 15 {
+		t.Fatalf("breakdown %+v", b)
+	}
+	if b.Total != b.Descriptions+b.Refs+b.Assertions+b.Metadata {
+		t.Fatalf("total %d does not sum: %+v", b.Total, b)
+	}
+
+	if empty := Score(trace.New("t", nil)); empty.Total != 0 {
+		t.Fatalf("empty trace scored %+v", empty)
+	}
+}
@@ -0,0 +1,121 @@
+package lint
+
+import "github.com/euclidtrace/trace"
+
+// ScoreWeights allots the 100 points of the explainability score across
+// its components.
+const (
+	weightDescriptions = 35
+	weightRefs         = 30
+	weightAssertions   = 20
+	weightMetadata     = 15
+)
+
+// ScoreBreakdown itemizes an explainability score so a team can see
+// which dimension to improve.
+type ScoreBreakdown struct {
+	// Total is the 0–100 score.
+	Total int `json:"total"`
+	// Descriptions is the points earned for description coverage: the
+	// fraction of steps carrying a description.
+	Descriptions int `json:"descriptions"`
+	// Refs is the points for reference completeness: the fraction of
+	// non-initial steps that declare where their inputs came from.
+	Refs int `json:"refs"`
+	// Assertions is the points for assertion presence: full credit from
+	// one assertion per ten steps.
+	Assertions int `json:"assertions"`
+	// Metadata is the points for metadata completeness beyond the
+	// automatic build keys.
+	Metadata int `json:"metadata"`
+}
+
+// Score grades a trace's explainability from 0 to 100: description
+// coverage (35 points), reference completeness (30), assertion presence
+// (20), and metadata completeness (15). The score measures how well the
+// trace explains itself to a later reader, not whether it verifies; CI
+// can gate on a minimum.
+func Score(tr *trace.Trace) ScoreBreakdown {
+	var b ScoreBreakdown
+	b.Descriptions = scale(weightDescriptions, descriptionCoverage(tr))
+	b.Refs = scale(weightRefs, refCompleteness(tr))
+	b.Assertions = scale(weightAssertions, assertionPresence(tr))
+	b.Metadata = scale(weightMetadata, metadataCompleteness(tr))
+	b.Total = b.Descriptions + b.Refs + b.Assertions + b.Metadata
+	return b
+}
+
+// descriptionCoverage is the fraction of steps with a description. An
+// empty trace earns nothing: it explains nothing.
+func descriptionCoverage(tr *trace.Trace) float64 {
+	if len(tr.Steps) == 0 {
+		return 0
+	}
+	described := 0
+	for _, st := range tr.Steps {
+		if st.Description != "" {
+			described++
+		}
+	}
+	return float64(described) / float64(len(tr.Steps))
+}
+
+// refCompleteness is the fraction of steps past the first that declare
+// refs — where their inputs came from. A single-step trace has nothing
+// to connect and earns full credit.
+func refCompleteness(tr *trace.Trace) float64 {
+	if len(tr.Steps) <= 1 {
+		if len(tr.Steps) == 0 {
+			return 0
+		}
+		return 1
+	}
+	connected := 0
+	for _, st := range tr.Steps[1:] {
+		if len(st.Refs) > 0 {
+			connected++
+		}
+	}
+	return float64(connected) / float64(len(tr.Steps)-1)
+}
+
+// assertionPresence grants full credit at one assertion per ten steps,
+// proportionally below that.
+func assertionPresence(tr *trace.Trace) float64 {
+	if len(tr.Steps) == 0 {
+		return 0
+	}
+	assertions := 0
+	for _, st := range tr.Steps {
+		if st.Operation == "assert" {
+			assertions++
+		}
+	}
+	wanted := (len(tr.Steps) + 9) / 10
+	if assertions >= wanted {
+		return 1
+	}
+	return float64(assertions) / float64(wanted)
+}
+
+// metadataCompleteness rewards metadata an operator added deliberately:
+// full credit from three keys outside the automatic build, env, and
+// system namespaces.
+func metadataCompleteness(tr *trace.Trace) float64 {
+	deliberate := 0
+	for k := range tr.Metadata {
+		if !trace.IsSystemMetadata(k) {
+			deliberate++
+		}
+	}
+	if deliberate >= 3 {
+		return 1
+	}
+	return float64(deliberate) / 3
+}
+
+// scale converts a 0–1 fraction into points of the given weight,
+// rounding half up so earned credit is not lost to truncation.
+func scale(weight int, fraction float64) int {
+	return int(fraction*float64(weight) + 0.5)
+}
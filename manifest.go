@@ -0,0 +1,66 @@
+package trace
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// FormatVersion identifies the trace serialization format produced by this
+// package. It is recorded in manifests and bumped on incompatible changes.
+const FormatVersion = "1"
+
+// Manifest is the compact reproduction record of a trace: everything needed
+// to re-run the computation and check the outcome, without shipping the full
+// step sequence. Manifests suit lightweight archival where whole traces are
+// too heavy.
+type Manifest struct {
+	TraceID       string            `json:"trace_id"`
+	Name          string            `json:"name"`
+	FormatVersion string            `json:"format_version"`
+	InputHashes   map[string]string `json:"input_hashes"`
+	Operations    []OperationRef    `json:"operations,omitempty"`
+	ModuleVersion string            `json:"module_version,omitempty"`
+	Revision      string            `json:"revision,omitempty"`
+	Status        Status            `json:"status"`
+	ContentHash   string            `json:"content_hash"`
+}
+
+// OperationRef identifies one operation used by the trace, with its semantic
+// version when the operation was registered with one.
+type OperationRef struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// Manifest builds the reproducibility manifest for the trace.
+func (tr *Trace) Manifest() Manifest {
+	m := Manifest{
+		TraceID:       tr.ID,
+		Name:          tr.Name,
+		FormatVersion: FormatVersion,
+		InputHashes:   map[string]string{},
+		ModuleVersion: BuildInfoMetadata()["build.module_version"],
+		Revision:      BuildInfoMetadata()["build.vcs_revision"],
+		ContentHash:   tr.ContentHash(),
+	}
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	m.Status = tr.Status
+	for k, v := range tr.Inputs {
+		m.InputHashes[k] = HashValue(v)
+	}
+	seen := map[string]bool{}
+	for _, st := range tr.Steps {
+		if !seen[st.Operation] {
+			seen[st.Operation] = true
+			m.Operations = append(m.Operations, OperationRef{Name: st.Operation})
+		}
+	}
+	sort.Slice(m.Operations, func(i, j int) bool { return m.Operations[i].Name < m.Operations[j].Name })
+	return m
+}
+
+// ToJSON serializes the manifest for archival.
+func (m Manifest) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(m, "", "  ")
+}
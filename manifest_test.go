@@ -0,0 +1,68 @@
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestManifestRecordsInputsAndOperations(t *testing.T) {
+	tr := New("calc", map[string]any{"gross": 100.0, "rate": 0.2})
+	tr.AddStep("tax", nil, nil)
+	tr.AddStep("subtract", nil, nil)
+	tr.AddStep("tax", nil, nil)
+	tr.Complete(80.0)
+
+	m := tr.Manifest()
+	if m.TraceID != tr.ID || m.FormatVersion != FormatVersion || m.Status != StatusCompleted {
+		t.Fatalf("manifest identity %+v", m)
+	}
+	if m.ContentHash != tr.ContentHash() {
+		t.Fatal("manifest does not pin the content hash")
+	}
+	if m.InputHashes["gross"] != HashValue(100.0) || m.InputHashes["rate"] != HashValue(0.2) {
+		t.Fatalf("input hashes %v", m.InputHashes)
+	}
+	// Deduplicated and sorted.
+	if len(m.Operations) != 2 || m.Operations[0].Name != "subtract" || m.Operations[1].Name != "tax" {
+		t.Fatalf("operations %v", m.Operations)
+	}
+
+	data, err := m.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back Manifest
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if back.ContentHash != m.ContentHash {
+		t.Fatal("manifest does not round-trip")
+	}
+}
+
+func TestContextCarriesTrace(t *testing.T) {
+	tr := New("t", nil)
+	ctx := NewContext(context.Background(), tr)
+	if FromContext(ctx) != tr {
+		t.Fatal("trace lost in context")
+	}
+	if FromContext(context.Background()) != nil {
+		t.Fatal("untraced context returned a trace")
+	}
+}
+
+func TestAllStepsSnapshots(t *testing.T) {
+	tr := New("t", nil)
+	for i := 0; i < 3; i++ {
+		tr.AddStep("iter", map[string]any{"i": i}, nil)
+	}
+	var got []int
+	tr.AllSteps()(func(i int, st *Step) bool {
+		got = append(got, i)
+		return i < 1 // early stop is honored
+	})
+	if len(got) != 2 || got[0] != 0 || got[1] != 1 {
+		t.Fatalf("yielded %v", got)
+	}
+}
@@ -0,0 +1,88 @@
+package trace
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Metadata namespaces. Auto-captured metadata lives in reserved system
+// namespaces (env.*, build.*, checkpoint.*, projection.*); user-supplied
+// keys go under user.* and compliance annotations under compliance.*, so
+// the two can never collide.
+const (
+	NamespaceUser       = "user"
+	NamespaceCompliance = "compliance"
+)
+
+// systemNamespaces are the prefixes the package itself writes; user code
+// may read but not set them.
+var systemNamespaces = []string{"env", "build", "checkpoint", "projection", "system", "tenant"}
+
+// MetadataNamespace returns the namespace of a key — the segment before
+// the first dot — or "" for an un-namespaced key.
+func MetadataNamespace(key string) string {
+	if i := strings.IndexByte(key, '.'); i > 0 {
+		return key[:i]
+	}
+	return ""
+}
+
+// IsSystemMetadata reports whether the key lives in a reserved system
+// namespace.
+func IsSystemMetadata(key string) bool {
+	ns := MetadataNamespace(key)
+	for _, s := range systemNamespaces {
+		if ns == s {
+			return true
+		}
+	}
+	return false
+}
+
+// SetNamespacedMetadata validates and records a metadata key in the given
+// namespace. The namespace must not be system-reserved and the key must
+// name something.
+func (tr *Trace) SetNamespacedMetadata(namespace, key, value string) error {
+	if key == "" {
+		return fmt.Errorf("trace %s: empty metadata key", tr.ID)
+	}
+	full := namespace + "." + key
+	if IsSystemMetadata(full) {
+		return fmt.Errorf("trace %s: metadata namespace %q is reserved", tr.ID, namespace)
+	}
+	tr.SetMetadata(full, value)
+	return nil
+}
+
+// SetUserMetadata records a key in the user namespace.
+func (tr *Trace) SetUserMetadata(key, value string) error {
+	return tr.SetNamespacedMetadata(NamespaceUser, key, value)
+}
+
+// MetadataInNamespace returns the metadata entries in one namespace, keys
+// kept fully qualified so callers can merge selections from several
+// namespaces.
+func (tr *Trace) MetadataInNamespace(namespace string) map[string]string {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	out := map[string]string{}
+	for k, v := range tr.Metadata {
+		if MetadataNamespace(k) == namespace {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// SelectMetadata returns the metadata entries whose namespace is in the
+// given set — the filter exporters and queries use to scope what they
+// emit.
+func (tr *Trace) SelectMetadata(namespaces ...string) map[string]string {
+	out := map[string]string{}
+	for _, ns := range namespaces {
+		for k, v := range tr.MetadataInNamespace(ns) {
+			out[k] = v
+		}
+	}
+	return out
+}
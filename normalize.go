@@ -0,0 +1,139 @@
+package trace
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Normalize returns the trace in canonical form for comparison: volatile
+// fields (timestamps, durations, environment and build metadata) are
+// stripped, values are put through canonical JSON formatting so 1 and 1.0
+// compare equal, and commutative groups of steps — consecutive steps with no
+// data-flow edges between them, as produced by parallel execution — are
+// reordered into a stable order. Equal, Diff, and golden tests should
+// compare normalized traces so they see semantics, not incidental ordering.
+func Normalize(tr *Trace) *Trace {
+	out := shallowCopy(tr)
+	out.Started = time.Time{}
+	out.Finished = time.Time{}
+	for k := range out.Metadata {
+		if strings.HasPrefix(k, "env.") || strings.HasPrefix(k, "build.") {
+			delete(out.Metadata, k)
+		}
+	}
+	out.Inputs = canonicalMap(tr.Inputs)
+	out.Result = canonicalValue(tr.Result)
+
+	steps := make([]*Step, len(tr.Steps))
+	for i, st := range tr.Steps {
+		dup := copyStep(st)
+		dup.Time = time.Time{}
+		dup.Duration = 0
+		dup.Attempts = nil
+		dup.Inputs = canonicalMap(dup.Inputs)
+		dup.Outputs = canonicalMap(dup.Outputs)
+		steps[i] = dup
+	}
+
+	remap := map[int]int{}
+	for _, group := range commutativeGroups(steps) {
+		sort.SliceStable(group, func(i, j int) bool {
+			return stepSortKey(group[i]) < stepSortKey(group[j])
+		})
+		for _, st := range group {
+			idx := len(out.Steps)
+			remap[st.Index] = idx
+			st.Index = idx
+			out.Steps = append(out.Steps, st)
+		}
+	}
+	for _, st := range out.Steps {
+		for i, ref := range st.Refs {
+			if mapped, ok := remap[ref]; ok {
+				st.Refs[i] = mapped
+			}
+		}
+		sort.Ints(st.Refs)
+		prev := ""
+		if st.Index > 0 {
+			prev = out.Steps[st.Index-1].Hash
+		}
+		st.Hash = st.chainHash(prev)
+	}
+	return out
+}
+
+// commutativeGroups partitions the step sequence into maximal runs with no
+// internal data-flow edges; steps within one run may be reordered freely
+// without changing the computation's meaning.
+func commutativeGroups(steps []*Step) [][]*Step {
+	var groups [][]*Step
+	var current []*Step
+	inCurrent := map[int]bool{}
+	flush := func() {
+		if len(current) > 0 {
+			groups = append(groups, current)
+			current = nil
+			inCurrent = map[int]bool{}
+		}
+	}
+	for _, st := range steps {
+		for _, ref := range st.Refs {
+			if inCurrent[ref] {
+				flush()
+				break
+			}
+		}
+		current = append(current, st)
+		inCurrent[st.Index] = true
+	}
+	flush()
+	return groups
+}
+
+// stepSortKey orders steps within a commutative group by what they did and
+// which values they touched.
+func stepSortKey(st *Step) string {
+	var b strings.Builder
+	b.WriteString(st.Operation)
+	b.WriteString("|")
+	b.WriteString(st.Origin)
+	for _, k := range sortedKeys(st.Inputs) {
+		b.WriteString("|in:" + k + "=" + HashValue(st.Inputs[k]))
+	}
+	for _, k := range sortedKeys(st.Outputs) {
+		b.WriteString("|out:" + k + "=" + HashValue(st.Outputs[k]))
+	}
+	return b.String()
+}
+
+// canonicalValue reformats a value through JSON, so numeric and composite
+// values of different in-memory types compare equal when they serialize
+// identically.
+func canonicalValue(v any) any {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
+
+func canonicalMap(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = canonicalValue(v)
+	}
+	return out
+}
@@ -0,0 +1,32 @@
+package trace
+
+// Note is commentary on a step addressed to a human audience —
+// "reviewer", "auditor", "oncall". Notes are distinct from Description:
+// a description is part of the step's hashed content and visible to
+// machine consumers, while notes sit outside every hash, never feed
+// value identity or verification, and appear only in human-facing
+// reports. That makes them the right place to explain a non-obvious
+// step after the fact without disturbing determinism.
+type Note struct {
+	Audience string `json:"audience"`
+	Text     string `json:"text"`
+}
+
+// WithNote attaches a note addressed to an audience. Because notes are
+// unhashed, it may be called on a step at any time, including after the
+// trace has finished; the returned step allows chaining off AddStep.
+func (st *Step) WithNote(audience, text string) *Step {
+	st.Notes = append(st.Notes, Note{Audience: audience, Text: text})
+	return st
+}
+
+// NotesFor returns the step's notes addressed to the given audience.
+func (st *Step) NotesFor(audience string) []Note {
+	var out []Note
+	for _, n := range st.Notes {
+		if n.Audience == audience {
+			out = append(out, n)
+		}
+	}
+	return out
+}
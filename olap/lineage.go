@@ -0,0 +1,98 @@
+package olap
+
+import (
+	"fmt"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Contributor identifies one source leaf behind a result cell: the fact
+// row or partition ID when the leaf carried one, otherwise its
+// coordinates, with the weight it contributed. Compacted aggregations
+// appear as a single contributor holding the leaf count and data hash.
+type Contributor struct {
+	ID     string  `json:"id,omitempty"`
+	Coords string  `json:"coords,omitempty"`
+	Weight float64 `json:"weight,omitempty"`
+	// Count and Hash stand in for the enumeration when the aggregation was
+	// compacted.
+	Count int    `json:"count,omitempty"`
+	Hash  string `json:"hash,omitempty"`
+}
+
+// Lineage maps each result cell of a traced OLAP query to the source
+// leaves that contributed to it — the answer to "which records make up
+// this number".
+type Lineage map[string][]Contributor
+
+// ExtractLineage walks the trace's OLAP aggregation sub-traces and
+// returns the lineage of every result cell. Cells aggregated more than
+// once accumulate contributors in step order.
+func ExtractLineage(tr *trace.Trace) Lineage {
+	lineage := Lineage{}
+	for _, st := range tr.Steps {
+		if st.SubTraceRef == "" {
+			continue
+		}
+		sub, ok := tr.SubTrace(st.SubTraceRef)
+		if !ok {
+			continue
+		}
+		cell, ok := sub.Inputs["cell"].(string)
+		if !ok {
+			continue
+		}
+		for _, leaf := range sub.Steps {
+			if leaf.Operation != "olap.leaf" {
+				continue
+			}
+			lineage[cell] = append(lineage[cell], leafContributor(leaf))
+		}
+	}
+	return lineage
+}
+
+func leafContributor(st *trace.Step) Contributor {
+	c := Contributor{}
+	if id, ok := st.Inputs["id"].(string); ok {
+		c.ID = id
+	}
+	if coords, ok := st.Inputs["coords"].(string); ok {
+		c.Coords = coords
+	}
+	if w, ok := asFloat(st.Inputs["weight"]); ok {
+		c.Weight = w
+	}
+	if n, ok := asFloat(st.Inputs["count"]); ok {
+		c.Count = int(n)
+	}
+	if h, ok := st.Inputs["leaves_hash"].(string); ok {
+		c.Hash = h
+	}
+	return c
+}
+
+// asFloat reads a numeric step value whether it is live (int, float64) or
+// came back from a JSON round trip (float64 only).
+func asFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func (c Contributor) String() string {
+	switch {
+	case c.Count > 0:
+		return fmt.Sprintf("%d leaves (hash %s)", c.Count, c.Hash)
+	case c.ID != "":
+		return c.ID
+	default:
+		return c.Coords
+	}
+}
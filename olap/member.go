@@ -0,0 +1,118 @@
+package olap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Member is a position in a dimension hierarchy: the dimension and the
+// member path from its root, e.g. [Time].[2024].[Q1]. Member prints in
+// that canonical bracketed form, so value hashing and serialization are
+// stable, and OLAP traces carry members as structure rather than strings.
+type Member struct {
+	Dimension string   `json:"dimension"`
+	Path      []string `json:"path,omitempty"`
+}
+
+// NewMember builds a member from its dimension and path segments.
+func NewMember(dimension string, path ...string) Member {
+	return Member{Dimension: dimension, Path: path}
+}
+
+// ParseMember reads the canonical bracketed form. The first segment is
+// the dimension; the rest are the path.
+func ParseMember(s string) (Member, error) {
+	var segments []string
+	rest := strings.TrimSpace(s)
+	for rest != "" {
+		if rest[0] != '[' {
+			return Member{}, fmt.Errorf("olap: parse member %q: expected '['", s)
+		}
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			return Member{}, fmt.Errorf("olap: parse member %q: unterminated segment", s)
+		}
+		segments = append(segments, rest[1:end])
+		rest = rest[end+1:]
+		if rest != "" {
+			if rest[0] != '.' {
+				return Member{}, fmt.Errorf("olap: parse member %q: expected '.' between segments", s)
+			}
+			rest = rest[1:]
+		}
+	}
+	if len(segments) == 0 {
+		return Member{}, fmt.Errorf("olap: parse member %q: empty", s)
+	}
+	return Member{Dimension: segments[0], Path: segments[1:]}, nil
+}
+
+// String returns the canonical bracketed form.
+func (m Member) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s]", m.Dimension)
+	for _, seg := range m.Path {
+		fmt.Fprintf(&b, ".[%s]", seg)
+	}
+	return b.String()
+}
+
+// Level returns the member's depth below the dimension root; the root
+// itself is level 0.
+func (m Member) Level() int {
+	return len(m.Path)
+}
+
+// Parent returns the member one level up, and false at the dimension
+// root.
+func (m Member) Parent() (Member, bool) {
+	if len(m.Path) == 0 {
+		return Member{}, false
+	}
+	return Member{Dimension: m.Dimension, Path: m.Path[:len(m.Path)-1]}, true
+}
+
+// IsAncestorOf reports whether m lies strictly above other in the same
+// dimension's hierarchy.
+func (m Member) IsAncestorOf(other Member) bool {
+	if m.Dimension != other.Dimension || len(m.Path) >= len(other.Path) {
+		return false
+	}
+	for i, seg := range m.Path {
+		if other.Path[i] != seg {
+			return false
+		}
+	}
+	return true
+}
+
+// RollUp records a value rolling up the hierarchy from a member to one of
+// its ancestors, one olap.rollup step per level, so the trace shows the
+// exact path an aggregated value took. The destination must be an
+// ancestor of the source (or the source itself, recording nothing).
+func RollUp(tr *trace.Trace, from, to Member, value float64) error {
+	if from.String() == to.String() {
+		return nil
+	}
+	if !to.IsAncestorOf(from) {
+		return fmt.Errorf("olap: %s is not an ancestor of %s", to, from)
+	}
+	current := from
+	for {
+		parent, ok := current.Parent()
+		if !ok {
+			break
+		}
+		tr.AddStep("olap.rollup",
+			map[string]any{"from": current, "value": value},
+			map[string]any{"to": parent},
+			trace.WithDescription(fmt.Sprintf("%s → %s", current, parent)))
+		current = parent
+		if current.String() == to.String() {
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package olap
+
+import (
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+func TestParseMemberRoundTrip(t *testing.T) {
+	for _, text := range []string{
+		"[Time]",
+		"[Time].[2024]",
+		"[Time].[2024].[Q1]",
+		"[Geography].[EMEA North]",
+	} {
+		m, err := ParseMember(text)
+		if err != nil {
+			t.Fatalf("%s: %v", text, err)
+		}
+		if m.String() != text {
+			t.Fatalf("%s round-tripped as %s", text, m)
+		}
+	}
+	for _, bad := range []string{"", "Time", "[Time", "[Time]x[2024]", "[Time].2024"} {
+		if _, err := ParseMember(bad); err == nil {
+			t.Fatalf("parsed %q", bad)
+		}
+	}
+}
+
+func TestMemberHierarchy(t *testing.T) {
+	q1 := NewMember("Time", "2024", "Q1")
+	year := NewMember("Time", "2024")
+	root := NewMember("Time")
+	if q1.Level() != 2 || root.Level() != 0 {
+		t.Fatalf("levels %d %d", q1.Level(), root.Level())
+	}
+	if p, ok := q1.Parent(); !ok || p.String() != year.String() {
+		t.Fatalf("parent %v %v", p, ok)
+	}
+	if _, ok := root.Parent(); ok {
+		t.Fatal("root has a parent")
+	}
+	if !year.IsAncestorOf(q1) || !root.IsAncestorOf(q1) {
+		t.Fatal("ancestry not recognized")
+	}
+	if q1.IsAncestorOf(year) || year.IsAncestorOf(year) {
+		t.Fatal("ancestry inverted or reflexive")
+	}
+	if NewMember("Geography", "2024").IsAncestorOf(q1) {
+		t.Fatal("ancestry across dimensions")
+	}
+}
+
+func TestRollUp(t *testing.T) {
+	tr := trace.New("report", nil)
+	q1 := NewMember("Time", "2024", "Q1")
+	root := NewMember("Time")
+	if err := RollUp(tr, q1, root, 300); err != nil {
+		t.Fatal(err)
+	}
+	if len(tr.Steps) != 2 {
+		t.Fatalf("steps %+v", tr.Steps)
+	}
+	if tr.Steps[0].Description != "[Time].[2024].[Q1] → [Time].[2024]" {
+		t.Fatalf("first hop %q", tr.Steps[0].Description)
+	}
+	// Rolling up to the source itself records nothing; a non-ancestor is
+	// an error.
+	if err := RollUp(tr, q1, q1, 300); err != nil || len(tr.Steps) != 2 {
+		t.Fatalf("self rollup: %v, steps %d", err, len(tr.Steps))
+	}
+	if err := RollUp(tr, q1, NewMember("Time", "2023"), 300); err == nil {
+		t.Fatal("rolled up to a non-ancestor")
+	}
+}
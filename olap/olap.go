@@ -0,0 +1,165 @@
+// Package olap traces cube aggregations, in keeping with the module's
+// EuclidOLAP heritage: summing or averaging across dimension members
+// records one sub-trace per aggregated cell showing exactly which leaf
+// cells and weights contributed, with compaction once an aggregation gets
+// too wide to enumerate.
+package olap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Cell is one leaf value in the cube: its coordinates (dimension →
+// member) and a weighted value. The zero weight means 1. ID optionally
+// names the source fact row or partition behind the leaf, and is what
+// lineage extraction reports when present.
+type Cell struct {
+	ID     string            `json:"id,omitempty"`
+	Coords map[string]string `json:"coords"`
+	Value  float64           `json:"value"`
+	Weight float64           `json:"weight,omitempty"`
+}
+
+// Cube is a collection of leaf cells.
+type Cube struct {
+	cells []Cell
+}
+
+// NewCube returns an empty cube.
+func NewCube() *Cube {
+	return &Cube{}
+}
+
+// Add inserts a leaf cell with weight 1.
+func (c *Cube) Add(coords map[string]string, value float64) {
+	c.AddWeighted(coords, value, 1)
+}
+
+// AddWeighted inserts a leaf cell with an explicit weight.
+func (c *Cube) AddWeighted(coords map[string]string, value, weight float64) {
+	c.cells = append(c.cells, Cell{Coords: coords, Value: value, Weight: weight})
+}
+
+// AddFact inserts a leaf cell carrying the ID of the source fact row or
+// partition it came from.
+func (c *Cube) AddFact(id string, coords map[string]string, value, weight float64) {
+	c.cells = append(c.cells, Cell{ID: id, Coords: coords, Value: value, Weight: weight})
+}
+
+// detailLimit is how many contributing leaves get individual steps in a
+// cell's sub-trace before the enumeration compacts.
+const detailLimit = 64
+
+// Sum aggregates the leaves matching the filter into a cell value,
+// recording the derivation as a sub-trace on tr.
+func (c *Cube) Sum(tr *trace.Trace, filter map[string]string) float64 {
+	return c.aggregate(tr, "sum", filter)
+}
+
+// Avg aggregates the weighted average of the leaves matching the filter,
+// recording the derivation as a sub-trace on tr.
+func (c *Cube) Avg(tr *trace.Trace, filter map[string]string) float64 {
+	return c.aggregate(tr, "avg", filter)
+}
+
+// aggregate builds the cell's sub-trace: one step per contributing leaf
+// (or a compacted summary past the detail limit), the combining step, and
+// the result; then records it on the parent as an olap.<op> step.
+func (c *Cube) aggregate(tr *trace.Trace, op string, filter map[string]string) float64 {
+	var leaves []Cell
+	for _, cell := range c.cells {
+		if matches(cell, filter) {
+			leaves = append(leaves, cell)
+		}
+	}
+
+	inputs := map[string]any{"cell": coordKey(filter), "leaves": len(leaves)}
+	sub := trace.New("olap."+op, inputs)
+	weightedSum, weightTotal := 0.0, 0.0
+	if len(leaves) <= detailLimit {
+		for _, leaf := range leaves {
+			w := leaf.Weight
+			if w == 0 {
+				w = 1
+			}
+			weightedSum += leaf.Value * w
+			weightTotal += w
+			in := map[string]any{"coords": coordKey(leaf.Coords), "value": leaf.Value, "weight": w}
+			if leaf.ID != "" {
+				in["id"] = leaf.ID
+			}
+			sub.AddStep("olap.leaf", in,
+				map[string]any{"running_sum": weightedSum, "running_weight": weightTotal})
+		}
+	} else {
+		for _, leaf := range leaves {
+			w := leaf.Weight
+			if w == 0 {
+				w = 1
+			}
+			weightedSum += leaf.Value * w
+			weightTotal += w
+		}
+		sub.AddStep("olap.leaf",
+			map[string]any{"count": len(leaves), "leaves_hash": trace.HashValue(leaves)},
+			map[string]any{"sum": weightedSum, "weight": weightTotal},
+			trace.WithDescription(fmt.Sprintf("%d contributing leaves, compacted", len(leaves))))
+	}
+
+	var result float64
+	switch op {
+	case "avg":
+		if weightTotal == 0 {
+			sub.AddStep("olap.combine", nil, nil,
+				trace.WithStepError(fmt.Errorf("olap: average of cell %s with no weight", coordKey(filter))))
+			sub.Fail(fmt.Errorf("olap: empty cell"))
+			tr.AddSubTrace("olap."+op, sub)
+			return 0
+		}
+		result = weightedSum / weightTotal
+		sub.AddStep("olap.combine",
+			map[string]any{"weighted_sum": weightedSum, "weight_total": weightTotal},
+			map[string]any{"value": result})
+	default:
+		result = weightedSum
+		sub.AddStep("olap.combine",
+			map[string]any{"weighted_sum": weightedSum},
+			map[string]any{"value": result})
+	}
+	sub.Complete(result)
+	tr.AddSubTrace("olap."+op, sub)
+	return result
+}
+
+// matches reports whether the cell sits under the filter: every filtered
+// dimension must carry the filtered member.
+func matches(cell Cell, filter map[string]string) bool {
+	for dim, member := range filter {
+		if cell.Coords[dim] != member {
+			return false
+		}
+	}
+	return true
+}
+
+// coordKey renders coordinates canonically for step values and cell
+// naming.
+func coordKey(coords map[string]string) string {
+	dims := make([]string, 0, len(coords))
+	for dim := range coords {
+		dims = append(dims, dim)
+	}
+	sort.Strings(dims)
+	parts := make([]string, 0, len(dims))
+	for _, dim := range dims {
+		parts = append(parts, dim+"="+coords[dim])
+	}
+	if len(parts) == 0 {
+		return "(all)"
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,95 @@
+package olap
+
+import (
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+func salesCube() *Cube {
+	c := NewCube()
+	c.AddFact("fact-1", map[string]string{"region": "emea", "quarter": "q1"}, 100, 0)
+	c.AddFact("fact-2", map[string]string{"region": "emea", "quarter": "q2"}, 200, 0)
+	c.AddFact("fact-3", map[string]string{"region": "apac", "quarter": "q1"}, 50, 0)
+	return c
+}
+
+func TestSumRecordsDerivation(t *testing.T) {
+	tr := trace.New("report", nil)
+	c := salesCube()
+	if got := c.Sum(tr, map[string]string{"region": "emea"}); got != 300 {
+		t.Fatalf("sum %v", got)
+	}
+	if len(tr.Steps) != 1 || tr.Steps[0].Operation != "olap.sum" {
+		t.Fatalf("steps %+v", tr.Steps)
+	}
+	sub, ok := tr.SubTrace(tr.Steps[0].SubTraceRef)
+	if !ok {
+		t.Fatal("no sub-trace recorded")
+	}
+	if sub.Inputs["cell"] != "region=emea" || sub.Inputs["leaves"] != 2 {
+		t.Fatalf("sub inputs %v", sub.Inputs)
+	}
+	// Two leaf steps and the combine.
+	if len(sub.Steps) != 3 || sub.Steps[2].Operation != "olap.combine" {
+		t.Fatalf("sub steps %+v", sub.Steps)
+	}
+	if sub.Result != 300.0 {
+		t.Fatalf("sub result %v", sub.Result)
+	}
+	tr.Complete(nil)
+	if err := tr.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAvgWeighsLeaves(t *testing.T) {
+	tr := trace.New("report", nil)
+	c := NewCube()
+	c.AddWeighted(map[string]string{"region": "emea"}, 100, 1)
+	c.AddWeighted(map[string]string{"region": "emea"}, 200, 3)
+	if got := c.Avg(tr, nil); got != 175 {
+		t.Fatalf("avg %v", got)
+	}
+	// Averaging an empty cell fails its sub-trace rather than inventing
+	// a value.
+	if got := c.Avg(tr, map[string]string{"region": "apac"}); got != 0 {
+		t.Fatalf("empty avg %v", got)
+	}
+	sub, ok := tr.SubTrace(tr.Steps[1].SubTraceRef)
+	if !ok || sub.Status != trace.StatusFailed {
+		t.Fatalf("empty-cell sub-trace %+v", sub)
+	}
+}
+
+func TestAggregationCompactsPastDetailLimit(t *testing.T) {
+	tr := trace.New("report", nil)
+	c := NewCube()
+	for i := 0; i < detailLimit+10; i++ {
+		c.Add(map[string]string{"region": "emea"}, 1)
+	}
+	if got := c.Sum(tr, nil); got != float64(detailLimit+10) {
+		t.Fatalf("sum %v", got)
+	}
+	sub, _ := tr.SubTrace(tr.Steps[0].SubTraceRef)
+	if len(sub.Steps) != 2 { // compacted leaves + combine
+		t.Fatalf("sub steps %+v", sub.Steps)
+	}
+	if sub.Steps[0].Inputs["count"] != detailLimit+10 {
+		t.Fatalf("compacted leaf %+v", sub.Steps[0])
+	}
+}
+
+func TestExtractLineage(t *testing.T) {
+	tr := trace.New("report", nil)
+	c := salesCube()
+	c.Sum(tr, map[string]string{"region": "emea"})
+	lineage := ExtractLineage(tr)
+	contributors := lineage["region=emea"]
+	if len(contributors) != 2 || contributors[0].ID != "fact-1" || contributors[1].ID != "fact-2" {
+		t.Fatalf("lineage %+v", lineage)
+	}
+	if contributors[0].Weight != 1 || contributors[0].String() != "fact-1" {
+		t.Fatalf("contributor %+v", contributors[0])
+	}
+}
@@ -0,0 +1,83 @@
+package olap
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Query captures a query expression (MDX or similar) as a structured
+// trace input: the original text as written, plus a canonical form with
+// whitespace normalized outside bracketed names and string literals.
+// Query serializes and hashes as its canonical form alone, so trace
+// identities key off the canonical query — two spellings of the same
+// query that differ only in layout produce the same trace ID.
+type Query struct {
+	Text      string
+	Canonical string
+}
+
+// NewQuery captures a query and its canonical form.
+func NewQuery(text string) Query {
+	return Query{Text: text, Canonical: canonicalizeQuery(text)}
+}
+
+// String returns the canonical form.
+func (q Query) String() string {
+	return q.Canonical
+}
+
+// MarshalJSON writes just the canonical form: serialized traces and value
+// hashes carry the query's semantic content, not its original layout.
+func (q Query) MarshalJSON() ([]byte, error) {
+	return json.Marshal(q.Canonical)
+}
+
+// UnmarshalJSON reads a canonical form back; the original text is not
+// persisted, so both fields carry the canonical form after a round trip.
+func (q *Query) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	q.Text, q.Canonical = s, s
+	return nil
+}
+
+// canonicalizeQuery collapses runs of whitespace to single spaces and
+// trims the ends, leaving bracketed names ([Time].[2024 Q1]) and quoted
+// string literals byte-for-byte intact since their spacing is
+// significant.
+func canonicalizeQuery(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+	var quote byte // active delimiter: '[', '"', or '\'' (0 when outside)
+	space := false
+	for i := 0; i < len(text); i++ {
+		ch := text[i]
+		if quote != 0 {
+			b.WriteByte(ch)
+			if (quote == '[' && ch == ']') || (quote != '[' && ch == quote) {
+				quote = 0
+			}
+			continue
+		}
+		switch {
+		case ch == '[' || ch == '"' || ch == '\'':
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			quote = ch
+			b.WriteByte(ch)
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			space = true
+		default:
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteByte(ch)
+		}
+	}
+	return b.String()
+}
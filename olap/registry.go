@@ -0,0 +1,191 @@
+package olap
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/euclidtrace/trace"
+)
+
+// AggregatorFunc folds canonically ordered leaves into a value. The
+// ordering contract is what makes traced aggregations replayable: by the
+// time a fold runs, the leaves have been sorted into canonical order, so
+// the same leaves produce bit-for-bit the same float no matter what order
+// the source iterated them in.
+type AggregatorFunc func(ordered []Cell) float64
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]AggregatorFunc{
+		"sum": func(cells []Cell) float64 {
+			total := 0.0
+			for _, c := range cells {
+				total += c.Value
+			}
+			return total
+		},
+		"min": func(cells []Cell) float64 {
+			if len(cells) == 0 {
+				return 0
+			}
+			min := cells[0].Value
+			for _, c := range cells[1:] {
+				if c.Value < min {
+					min = c.Value
+				}
+			}
+			return min
+		},
+		"max": func(cells []Cell) float64 {
+			if len(cells) == 0 {
+				return 0
+			}
+			max := cells[0].Value
+			for _, c := range cells[1:] {
+				if c.Value > max {
+					max = c.Value
+				}
+			}
+			return max
+		},
+		"distinct-count": func(cells []Cell) float64 {
+			seen := map[float64]bool{}
+			for _, c := range cells {
+				seen[c.Value] = true
+			}
+			return float64(len(seen))
+		},
+		"weighted-avg": func(cells []Cell) float64 {
+			sum, weight := 0.0, 0.0
+			for _, c := range cells {
+				w := c.Weight
+				if w == 0 {
+					w = 1
+				}
+				sum += c.Value * w
+				weight += w
+			}
+			if weight == 0 {
+				return 0
+			}
+			return sum / weight
+		},
+	}
+)
+
+// RegisterAggregator adds an operator to the registry; re-registering a
+// name is rejected so replay semantics cannot silently change.
+func RegisterAggregator(name string, fn AggregatorFunc) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[name]; ok {
+		return fmt.Errorf("olap: aggregator %q already registered", name)
+	}
+	registry[name] = fn
+	return nil
+}
+
+func lookupAggregator(name string) (AggregatorFunc, bool) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	fn, ok := registry[name]
+	return fn, ok
+}
+
+// sortCanonical orders leaves by fact ID, then coordinates, then value
+// and weight — a total order independent of source iteration order.
+func sortCanonical(cells []Cell) {
+	sort.Slice(cells, func(i, j int) bool {
+		a, b := cells[i], cells[j]
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		if ak, bk := coordKey(a.Coords), coordKey(b.Coords); ak != bk {
+			return ak < bk
+		}
+		if a.Value != b.Value {
+			return a.Value < b.Value
+		}
+		return a.Weight < b.Weight
+	})
+}
+
+// AggregateWith aggregates the leaves matching the filter with a
+// registered operator, recording the canonically ordered leaves and the
+// result as a sub-trace whose derivation ReplayAggregations can re-verify
+// bit-for-bit.
+func (c *Cube) AggregateWith(tr *trace.Trace, operator string, filter map[string]string) (float64, error) {
+	fn, ok := lookupAggregator(operator)
+	if !ok {
+		return 0, fmt.Errorf("olap: unknown aggregator %q", operator)
+	}
+	var leaves []Cell
+	for _, cell := range c.cells {
+		if matches(cell, filter) {
+			leaves = append(leaves, cell)
+		}
+	}
+	sortCanonical(leaves)
+
+	sub := trace.New("olap.aggregate", map[string]any{
+		"cell": coordKey(filter), "operator": operator, "leaves": len(leaves),
+	})
+	for _, leaf := range leaves {
+		in := map[string]any{"coords": coordKey(leaf.Coords), "value": leaf.Value}
+		if leaf.ID != "" {
+			in["id"] = leaf.ID
+		}
+		if leaf.Weight != 0 {
+			in["weight"] = leaf.Weight
+		}
+		sub.AddStep("olap.leaf", in, nil)
+	}
+	result := fn(leaves)
+	sub.AddStep("olap.combine",
+		map[string]any{"operator": operator, "count": len(leaves)},
+		map[string]any{"value": result})
+	sub.Complete(result)
+	tr.AddSubTrace("olap."+operator, sub)
+	return result, nil
+}
+
+// ReplayAggregations re-runs every registry-based aggregation recorded on
+// the trace from its leaf steps and verifies the replayed value matches
+// the recorded result bit-for-bit. Aggregations whose operator is no
+// longer registered are reported; sub-traces recorded by other helpers
+// are skipped.
+func ReplayAggregations(tr *trace.Trace) error {
+	for _, st := range tr.Steps {
+		if st.SubTraceRef == "" {
+			continue
+		}
+		sub, ok := tr.SubTrace(st.SubTraceRef)
+		if !ok || sub.Name != "olap.aggregate" {
+			continue
+		}
+		operator, _ := sub.Inputs["operator"].(string)
+		fn, ok := lookupAggregator(operator)
+		if !ok {
+			return fmt.Errorf("olap: replay: aggregator %q not registered", operator)
+		}
+		var leaves []Cell
+		for _, leaf := range sub.Steps {
+			if leaf.Operation != "olap.leaf" {
+				continue
+			}
+			cell := Cell{}
+			cell.ID, _ = leaf.Inputs["id"].(string)
+			cell.Value, _ = asFloat(leaf.Inputs["value"])
+			cell.Weight, _ = asFloat(leaf.Inputs["weight"])
+			leaves = append(leaves, cell)
+		}
+		replayed := fn(leaves)
+		recorded, ok := asFloat(sub.Result)
+		if !ok || replayed != recorded {
+			return fmt.Errorf("olap: replay: cell %v operator %s: replayed %v, recorded %v",
+				sub.Inputs["cell"], operator, replayed, sub.Result)
+		}
+	}
+	return nil
+}
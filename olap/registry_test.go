@@ -0,0 +1,94 @@
+package olap
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+func TestAggregateWithIsOrderIndependent(t *testing.T) {
+	forward := NewCube()
+	forward.AddFact("a", map[string]string{"r": "x"}, 1, 0)
+	forward.AddFact("b", map[string]string{"r": "x"}, 2, 0)
+	backward := NewCube()
+	backward.AddFact("b", map[string]string{"r": "x"}, 2, 0)
+	backward.AddFact("a", map[string]string{"r": "x"}, 1, 0)
+
+	run := func(c *Cube) *trace.Trace {
+		tr := trace.New("report", map[string]any{"q": 1})
+		if _, err := c.AggregateWith(tr, "sum", nil); err != nil {
+			t.Fatal(err)
+		}
+		tr.Complete(nil)
+		return tr
+	}
+	if run(forward).ContentHash() != run(backward).ContentHash() {
+		t.Fatal("iteration order leaked into the trace")
+	}
+}
+
+func TestAggregateWithUnknownOperator(t *testing.T) {
+	tr := trace.New("report", nil)
+	if _, err := NewCube().AggregateWith(tr, "median-ish", nil); err == nil {
+		t.Fatal("aggregated with an unregistered operator")
+	}
+}
+
+func TestRegisterAggregatorRejectsDuplicates(t *testing.T) {
+	if err := RegisterAggregator("sum", func([]Cell) float64 { return 0 }); err == nil {
+		t.Fatal("re-registered a built-in")
+	}
+}
+
+func TestReplayAggregations(t *testing.T) {
+	c := salesCube()
+	tr := trace.New("report", nil)
+	for _, op := range []string{"sum", "min", "max", "weighted-avg"} {
+		if _, err := c.AggregateWith(tr, op, map[string]string{"region": "emea"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tr.Complete(nil)
+	if err := ReplayAggregations(tr); err != nil {
+		t.Fatal(err)
+	}
+	// A doctored result is caught bit-for-bit.
+	sub, ok := tr.SubTrace(tr.Steps[0].SubTraceRef)
+	if !ok {
+		t.Fatal("no sub-trace recorded")
+	}
+	sub.Result = 300.0000001
+	if err := ReplayAggregations(tr); err == nil {
+		t.Fatal("replay accepted a doctored result")
+	}
+}
+
+func TestQueryCanonicalization(t *testing.T) {
+	a := NewQuery("SELECT   [Measures].[Net  Sales]\n FROM [Cube]")
+	b := NewQuery("SELECT [Measures].[Net  Sales] FROM [Cube]")
+	if a.Canonical != b.Canonical {
+		t.Fatalf("%q != %q", a.Canonical, b.Canonical)
+	}
+	// Spacing inside bracketed names and string literals is preserved.
+	if !strings.Contains(a.Canonical, "[Net  Sales]") {
+		t.Fatalf("bracket contents normalized: %q", a.Canonical)
+	}
+	q := NewQuery(`WHERE name = 'a  b'   AND x = 1`)
+	if q.Canonical != `WHERE name = 'a  b' AND x = 1` {
+		t.Fatalf("canonical %q", q.Canonical)
+	}
+
+	data, err := json.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back Query
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if back.Canonical != a.Canonical || back.Text != a.Canonical {
+		t.Fatalf("round trip %+v", back)
+	}
+}
@@ -0,0 +1,138 @@
+package trace
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// FailureRecorder trades full traces for cheap buffering: steps are
+// held in a bounded ring with no hashing or chaining, and the complete
+// trace is only materialized when the computation fails or an assertion
+// trips. Successful runs emit a small summary record instead. This
+// keeps explainability where it matters — the failures — at minimal
+// steady-state cost.
+type FailureRecorder struct {
+	name   string
+	inputs map[string]any
+	opts   []Option
+	size   int
+
+	mu      sync.Mutex
+	buf     []bufferedStep
+	start   int
+	dropped int
+	tripped bool
+}
+
+// bufferedStep holds one step's raw material until (and unless) a full
+// trace is materialized; nothing is hashed while buffering.
+type bufferedStep struct {
+	operation string
+	inputs    map[string]any
+	outputs   map[string]any
+	opts      []StepOption
+	time      time.Time
+}
+
+// NewFailureRecorder begins buffering a computation under the given
+// name and inputs, keeping at most bufferSize recent steps; older steps
+// are dropped oldest-first and counted. The options are applied to
+// whichever trace is eventually materialized.
+func NewFailureRecorder(name string, inputs map[string]any, bufferSize int, opts ...Option) *FailureRecorder {
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+	return &FailureRecorder{name: name, inputs: inputs, opts: opts, size: bufferSize}
+}
+
+// AddStep buffers one step. Unlike Trace.AddStep it returns nothing:
+// the step has no hash or index until a trace is materialized.
+func (r *FailureRecorder) AddStep(operation string, inputs, outputs map[string]any, opts ...StepOption) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.push(bufferedStep{operation: operation, inputs: inputs, outputs: outputs, opts: opts, time: time.Now()})
+}
+
+// Assert buffers an assertion step in the standard shape; a failed
+// assertion trips the recorder, so Complete materializes the full trace
+// instead of a summary.
+func (r *FailureRecorder) Assert(name string, passed bool, detail string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	opts := []StepOption{}
+	if !passed {
+		r.tripped = true
+		opts = append(opts,
+			WithStepError(fmt.Errorf("assertion %s failed", name)),
+			WithDescription(detail))
+	}
+	r.push(bufferedStep{
+		operation: "assert",
+		inputs:    map[string]any{"name": name},
+		outputs:   map[string]any{"passed": passed},
+		opts:      opts,
+		time:      time.Now(),
+	})
+}
+
+// push appends to the ring, evicting the oldest step when full.
+func (r *FailureRecorder) push(bs bufferedStep) {
+	if len(r.buf) < r.size {
+		r.buf = append(r.buf, bs)
+		return
+	}
+	r.buf[r.start] = bs
+	r.start = (r.start + 1) % r.size
+	r.dropped++
+}
+
+// Fail materializes the full trace — every buffered step, chained and
+// hashed now, with original timestamps — and finishes it failed.
+func (r *FailureRecorder) Fail(err error) *Trace {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tr := r.materializeLocked()
+	tr.Fail(err)
+	return tr
+}
+
+// Complete finishes the computation. If an assertion tripped, the full
+// buffered trace is materialized and completed; otherwise a summary
+// record is emitted: the trace identity, a single step counting what
+// was buffered, and the result, with no step detail.
+func (r *FailureRecorder) Complete(result any) *Trace {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.tripped {
+		tr := r.materializeLocked()
+		tr.Complete(result)
+		return tr
+	}
+	tr := New(r.name, r.inputs, r.opts...)
+	tr.SetMetadata("recorder.mode", "summary")
+	tr.AddStep("trace.summary",
+		map[string]any{"steps_buffered": len(r.buf), "steps_dropped": r.dropped},
+		nil,
+		WithDescription("record-on-failure run succeeded; step detail not retained"))
+	tr.Complete(result)
+	return tr
+}
+
+// materializeLocked replays the ring into a real trace. Chain hashing
+// happens here, once, only for the traces that earn it; timestamps are
+// restored after recording since they sit outside every hash.
+func (r *FailureRecorder) materializeLocked() *Trace {
+	tr := New(r.name, r.inputs, r.opts...)
+	tr.SetMetadata("recorder.mode", "on-failure")
+	if r.dropped > 0 {
+		tr.SetMetadata("recorder.steps_dropped", fmt.Sprintf("%d", r.dropped))
+	}
+	for i := 0; i < len(r.buf); i++ {
+		bs := r.buf[(r.start+i)%len(r.buf)]
+		if st := tr.AddStep(bs.operation, bs.inputs, bs.outputs, bs.opts...); st != nil {
+			st.Time = bs.time
+		}
+	}
+	return tr
+}
@@ -0,0 +1,65 @@
+package trace
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFailureRecorderSummarizesSuccess(t *testing.T) {
+	r := NewFailureRecorder("job", map[string]any{"n": 3}, 8)
+	for i := 0; i < 5; i++ {
+		r.AddStep("iter", map[string]any{"i": i}, nil)
+	}
+	tr := r.Complete(99)
+	if tr.Status != StatusCompleted || tr.Result != 99 {
+		t.Fatalf("status %s result %v", tr.Status, tr.Result)
+	}
+	if tr.Metadata["recorder.mode"] != "summary" {
+		t.Fatalf("mode %q", tr.Metadata["recorder.mode"])
+	}
+	if len(tr.Steps) != 1 || tr.Steps[0].Operation != "trace.summary" {
+		t.Fatalf("summary trace has steps %+v", tr.Steps)
+	}
+	if got := tr.Steps[0].Inputs["steps_buffered"]; got != 5 {
+		t.Fatalf("steps_buffered %v", got)
+	}
+}
+
+func TestFailureRecorderMaterializesOnFailure(t *testing.T) {
+	r := NewFailureRecorder("job", nil, 3)
+	for i := 0; i < 5; i++ { // ring of 3: steps 0 and 1 fall out
+		r.AddStep("iter", map[string]any{"i": i}, nil)
+	}
+	tr := r.Fail(errors.New("boom"))
+	if tr.Status != StatusFailed || tr.Error != "boom" {
+		t.Fatalf("status %s error %q", tr.Status, tr.Error)
+	}
+	if tr.Metadata["recorder.mode"] != "on-failure" {
+		t.Fatalf("mode %q", tr.Metadata["recorder.mode"])
+	}
+	if tr.Metadata["recorder.steps_dropped"] != "2" {
+		t.Fatalf("steps_dropped %q", tr.Metadata["recorder.steps_dropped"])
+	}
+	if len(tr.Steps) != 3 || tr.Steps[0].Inputs["i"] != 2 {
+		t.Fatalf("buffer replayed wrong: %+v", tr.Steps)
+	}
+	if err := tr.Verify(); err != nil {
+		t.Fatalf("materialized trace does not verify: %v", err)
+	}
+}
+
+func TestFailureRecorderTripsOnFailedAssertion(t *testing.T) {
+	r := NewFailureRecorder("job", nil, 8)
+	r.AddStep("calc", nil, map[string]any{"y": -1})
+	r.Assert("non-negative", false, "y is -1")
+	tr := r.Complete(nil)
+	if tr.Metadata["recorder.mode"] != "on-failure" {
+		t.Fatalf("failed assertion did not trip full recording: mode %q", tr.Metadata["recorder.mode"])
+	}
+	if len(tr.FailedAssertions()) != 1 {
+		t.Fatalf("failed assertions: %d", len(tr.FailedAssertions()))
+	}
+	if tr.Status != StatusCompleted {
+		t.Fatalf("status %s", tr.Status)
+	}
+}
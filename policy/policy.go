@@ -0,0 +1,145 @@
+// Package policy evaluates compliance rules over completed traces and
+// produces structured violation reports. Compliance teams declare what every
+// trace of a given computation must (or must not) contain; the engine checks
+// archives after the fact, independent of the code that produced them.
+package policy
+
+import (
+	"fmt"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Violation is one rule breach located in a trace.
+type Violation struct {
+	Rule    string `json:"rule"`
+	TraceID string `json:"trace_id"`
+	// StepIndex locates the offending step, or -1 for trace-level breaches.
+	StepIndex int    `json:"step_index"`
+	Message   string `json:"message"`
+}
+
+// Rule is one declared requirement. TraceName restricts the rule to traces
+// of that computation; empty applies it to every trace.
+type Rule struct {
+	Name      string
+	TraceName string
+	Check     func(tr *trace.Trace) []Violation
+}
+
+// Engine holds a rule set and evaluates traces against it.
+type Engine struct {
+	rules []Rule
+}
+
+// NewEngine builds an engine from the given rules.
+func NewEngine(rules ...Rule) *Engine {
+	return &Engine{rules: rules}
+}
+
+// Add registers another rule.
+func (e *Engine) Add(rule Rule) {
+	e.rules = append(e.rules, rule)
+}
+
+// Evaluate checks one trace against every applicable rule.
+func (e *Engine) Evaluate(tr *trace.Trace) []Violation {
+	var violations []Violation
+	for _, rule := range e.rules {
+		if rule.TraceName != "" && rule.TraceName != tr.Name {
+			continue
+		}
+		violations = append(violations, rule.Check(tr)...)
+	}
+	return violations
+}
+
+// Source yields traces to evaluate; store.Store satisfies it.
+type Source interface {
+	Each(fn func(tr *trace.Trace) error) error
+}
+
+// EvaluateAll checks every trace from the source.
+func (e *Engine) EvaluateAll(src Source) ([]Violation, error) {
+	var violations []Violation
+	err := src.Each(func(tr *trace.Trace) error {
+		violations = append(violations, e.Evaluate(tr)...)
+		return nil
+	})
+	return violations, err
+}
+
+// RequireStep declares that matching traces must contain at least one step
+// with the given operation.
+func RequireStep(ruleName, traceName, operation string) Rule {
+	return Rule{
+		Name:      ruleName,
+		TraceName: traceName,
+		Check: func(tr *trace.Trace) []Violation {
+			for _, st := range tr.Steps {
+				if st.Operation == operation {
+					return nil
+				}
+			}
+			return []Violation{{
+				Rule:      ruleName,
+				TraceID:   tr.ID,
+				StepIndex: -1,
+				Message:   fmt.Sprintf("trace %s (%s) has no %q step", tr.ID, tr.Name, operation),
+			}}
+		},
+	}
+}
+
+// RequireAssertion declares that matching traces must contain the named
+// assertion, and that it must have passed.
+func RequireAssertion(ruleName, traceName, assertion string) Rule {
+	return Rule{
+		Name:      ruleName,
+		TraceName: traceName,
+		Check: func(tr *trace.Trace) []Violation {
+			for _, st := range tr.Steps {
+				if st.Operation != "assert" || st.Inputs["name"] != assertion {
+					continue
+				}
+				if st.Outputs["passed"] == true {
+					return nil
+				}
+				return []Violation{{
+					Rule:      ruleName,
+					TraceID:   tr.ID,
+					StepIndex: st.Index,
+					Message:   fmt.Sprintf("assertion %q failed in trace %s", assertion, tr.ID),
+				}}
+			}
+			return []Violation{{
+				Rule:      ruleName,
+				TraceID:   tr.ID,
+				StepIndex: -1,
+				Message:   fmt.Sprintf("trace %s (%s) is missing assertion %q", tr.ID, tr.Name, assertion),
+			}}
+		},
+	}
+}
+
+// MetadataRequires declares that traces carrying metadata key=value must
+// also carry requiredKey — e.g. override=true requires an approver entry.
+func MetadataRequires(ruleName, key, value, requiredKey string) Rule {
+	return Rule{
+		Name: ruleName,
+		Check: func(tr *trace.Trace) []Violation {
+			if tr.Metadata[key] != value {
+				return nil
+			}
+			if _, ok := tr.Metadata[requiredKey]; ok {
+				return nil
+			}
+			return []Violation{{
+				Rule:      ruleName,
+				TraceID:   tr.ID,
+				StepIndex: -1,
+				Message:   fmt.Sprintf("trace %s has metadata %s=%s but no %q entry", tr.ID, key, value, requiredKey),
+			}}
+		},
+	}
+}
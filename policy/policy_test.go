@@ -0,0 +1,91 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+type traces []*trace.Trace
+
+func (ts traces) Each(fn func(tr *trace.Trace) error) error {
+	for _, tr := range ts {
+		if err := fn(tr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestRequireStep(t *testing.T) {
+	engine := NewEngine(RequireStep("audit-step", "pricing", "audit"))
+	good := trace.New("pricing", nil)
+	good.AddStep("audit", nil, nil)
+	if v := engine.Evaluate(good); len(v) != 0 {
+		t.Fatalf("violations %v", v)
+	}
+	bad := trace.New("pricing", nil)
+	bad.AddStep("other", nil, nil)
+	v := engine.Evaluate(bad)
+	if len(v) != 1 || v[0].Rule != "audit-step" || v[0].TraceID != bad.ID || v[0].StepIndex != -1 {
+		t.Fatalf("violations %+v", v)
+	}
+	// The rule is scoped to pricing traces.
+	other := trace.New("billing", nil)
+	if v := engine.Evaluate(other); len(v) != 0 {
+		t.Fatalf("rule leaked across trace names: %v", v)
+	}
+}
+
+func TestRequireAssertion(t *testing.T) {
+	engine := NewEngine(RequireAssertion("bounds-checked", "", "bounded"))
+	passed := trace.New("t", nil)
+	passed.Assert("bounded", true, "")
+	if v := engine.Evaluate(passed); len(v) != 0 {
+		t.Fatalf("violations %v", v)
+	}
+	failed := trace.New("t", nil)
+	failed.Assert("bounded", false, "out of range")
+	if v := engine.Evaluate(failed); len(v) != 1 || v[0].StepIndex != 0 {
+		t.Fatalf("violations %+v", v)
+	}
+	missing := trace.New("t", nil)
+	if v := engine.Evaluate(missing); len(v) != 1 || v[0].StepIndex != -1 {
+		t.Fatalf("violations %+v", v)
+	}
+}
+
+func TestMetadataRequires(t *testing.T) {
+	engine := NewEngine(MetadataRequires("override-approved", "user.override", "true", "user.approver"))
+	override := trace.New("t", nil)
+	if err := override.SetUserMetadata("override", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if v := engine.Evaluate(override); len(v) != 1 {
+		t.Fatalf("violations %v", v)
+	}
+	if err := override.SetUserMetadata("approver", "lee"); err != nil {
+		t.Fatal(err)
+	}
+	if v := engine.Evaluate(override); len(v) != 0 {
+		t.Fatalf("violations %v", v)
+	}
+	plain := trace.New("t", nil)
+	if v := engine.Evaluate(plain); len(v) != 0 {
+		t.Fatalf("violations on a trace without the trigger: %v", v)
+	}
+}
+
+func TestEvaluateAll(t *testing.T) {
+	engine := NewEngine()
+	engine.Add(RequireStep("audit-step", "", "audit"))
+	a, b := trace.New("t", nil), trace.New("t", nil)
+	b.AddStep("audit", nil, nil)
+	violations, err := engine.EvaluateAll(traces{a, b})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) != 1 || violations[0].TraceID != a.ID {
+		t.Fatalf("violations %+v", violations)
+	}
+}
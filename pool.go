@@ -0,0 +1,60 @@
+package trace
+
+import "sync"
+
+// Pooling
+//
+// Services producing thousands of traces per second spend measurable GC
+// time on Trace and Step records. New and AddStep draw their structs
+// from sync.Pools, and Release returns a finished trace's storage for
+// reuse. The lifetime rules are strict because reuse makes stale
+// references silently observe someone else's computation:
+//
+//   - Release only a terminal trace, and only after every export —
+//     ToJSON, bundling, storage Put — has finished with it.
+//   - After Release, neither the trace nor any *Step obtained from it
+//     may be touched again.
+//   - Release does not descend into sub-traces: an embedded trace may
+//     be shared with other parents, so each owner releases what it
+//     created.
+//
+// Release is optional; unreleased traces are collected normally.
+
+var tracePool = sync.Pool{New: func() any { return new(Trace) }}
+
+var stepPool = sync.Pool{New: func() any { return new(Step) }}
+
+// newTrace returns a zeroed trace, reusing pooled storage — including
+// the step slice capacity of a released predecessor.
+func newTrace() *Trace {
+	return tracePool.Get().(*Trace)
+}
+
+// newStep returns a zeroed step from the pool.
+func newStep() *Step {
+	return stepPool.Get().(*Step)
+}
+
+// Release returns the trace's storage to the pool for reuse by later
+// traces. It is a no-op on a trace that is still recording. See the
+// lifetime rules above: after Release the trace and its steps are dead.
+func (tr *Trace) Release() {
+	tr.mu.Lock()
+	switch tr.Status {
+	case StatusCompleted, StatusFailed, StatusAborted:
+	default:
+		// Still recording, or already released and zeroed — either way
+		// the storage must not enter the pool (again).
+		tr.mu.Unlock()
+		return
+	}
+	steps := tr.Steps
+	for i, st := range steps {
+		*st = Step{}
+		stepPool.Put(st)
+		steps[i] = nil
+	}
+	tr.mu.Unlock()
+	*tr = Trace{Steps: steps[:0]}
+	tracePool.Put(tr)
+}
@@ -0,0 +1,40 @@
+package trace
+
+import "testing"
+
+func TestReleaseOnlyTerminalTraces(t *testing.T) {
+	tr := New("t", nil)
+	tr.AddStep("a", nil, nil)
+	tr.Release() // still recording: must be a no-op
+	if tr.Status != StatusRecording || len(tr.Steps) != 1 {
+		t.Fatalf("Release touched a recording trace: %s, %d steps", tr.Status, len(tr.Steps))
+	}
+	tr.Complete(nil)
+	tr.Release()
+	if tr.Status != "" || len(tr.Steps) != 0 {
+		t.Fatalf("released trace not zeroed: %q, %d steps", tr.Status, len(tr.Steps))
+	}
+	// A second Release must not pool the same storage twice.
+	tr.Release()
+}
+
+func TestPooledTracesStartZeroed(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		tr := New("t", map[string]any{"i": i})
+		tr.AddStep("a", map[string]any{"i": i}, nil)
+		tr.Complete(i)
+		id, hash := tr.ID, tr.ContentHash()
+		tr.Release()
+
+		next := New("t", map[string]any{"i": i})
+		next.AddStep("a", map[string]any{"i": i}, nil)
+		next.Complete(i)
+		if next.ID != id || next.ContentHash() != hash {
+			t.Fatalf("iteration %d: reused storage changed the recorded computation", i)
+		}
+		if err := next.Verify(); err != nil {
+			t.Fatalf("iteration %d: %v", i, err)
+		}
+		next.Release()
+	}
+}
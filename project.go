@@ -0,0 +1,103 @@
+package trace
+
+import "strings"
+
+// View declares, once, what one audience may see of a trace; Project and
+// the HTTP/report exporters apply the same rules. The zero value shows
+// nothing beyond the skeleton — identity, status, operations — and fields
+// are opted in per audience.
+type View struct {
+	Name string
+	// TraceInputs keeps the trace's input values and the final result.
+	TraceInputs bool
+	// StepValues keeps per-step input and output values.
+	StepValues bool
+	// Descriptions keeps step descriptions.
+	Descriptions bool
+	// Errors keeps trace and step error text.
+	Errors bool
+	// SubTraces keeps embedded sub-traces, projected under the same view.
+	SubTraces bool
+	// Metadata selects which metadata keys survive; nil drops all.
+	Metadata func(key string) bool
+}
+
+// ViewAuditor sees everything.
+var ViewAuditor = View{
+	Name:         "auditor",
+	TraceInputs:  true,
+	StepValues:   true,
+	Descriptions: true,
+	Errors:       true,
+	SubTraces:    true,
+	Metadata:     func(string) bool { return true },
+}
+
+// ViewCustomer sees the shape of the computation and its result —
+// operations, descriptions, trace inputs and outcome — but not internal
+// metadata, per-step values, or error internals.
+var ViewCustomer = View{
+	Name:         "customer",
+	TraceInputs:  true,
+	Descriptions: true,
+	Metadata: func(key string) bool {
+		return !strings.HasPrefix(key, "env.") && !strings.HasPrefix(key, "build.") &&
+			!strings.HasPrefix(key, "checkpoint.")
+	},
+}
+
+// Project returns a filtered copy of the trace for the view's audience.
+// Projections are presentation copies: removing fields breaks the step
+// hash chain, so a projection does not Verify and is marked with the view
+// name under the "projection.view" metadata key. Attachments never cross
+// a projection.
+func (tr *Trace) Project(v View) *Trace {
+	data, err := tr.ToJSON()
+	if err != nil {
+		return nil
+	}
+	p, err := FromJSON(data)
+	if err != nil {
+		return nil
+	}
+	if !v.TraceInputs {
+		p.Inputs = map[string]any{}
+		p.Result = nil
+		p.InputTags = nil
+	}
+	if !v.Errors {
+		p.Error = ""
+	}
+	for key := range p.Metadata {
+		if v.Metadata == nil || !v.Metadata(key) {
+			delete(p.Metadata, key)
+		}
+	}
+	for _, st := range p.Steps {
+		if !v.StepValues {
+			st.Inputs = nil
+			st.Outputs = nil
+		}
+		if !v.Descriptions {
+			st.Description = ""
+		}
+		if !v.Errors {
+			st.Err = ""
+		}
+	}
+	if v.SubTraces {
+		for hash, sub := range p.SubTraces {
+			p.SubTraces[hash] = sub.Project(v)
+		}
+	} else {
+		p.SubTraces = nil
+		for _, st := range p.Steps {
+			st.SubTraceRef = ""
+		}
+	}
+	p.Attachments = nil
+	if v.Name != "" {
+		p.Metadata["projection.view"] = v.Name
+	}
+	return p
+}
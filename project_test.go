@@ -0,0 +1,80 @@
+package trace
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func projectableTrace() *Trace {
+	sub := New("inner", map[string]any{"k": "secret"})
+	sub.Complete("hidden")
+	tr := New("outer", map[string]any{"ssn": "123-45-6789"})
+	tr.SetUserMetadata("ticket", "T-1")
+	tr.AddStep("lookup", map[string]any{"ssn": "123-45-6789"}, map[string]any{"score": 700},
+		WithDescription("credit lookup"))
+	tr.AddSubTrace("inner", sub)
+	tr.Attach("raw", []byte("payload"))
+	tr.Fail(errors.New("upstream timeout"))
+	return tr
+}
+
+func TestProjectCustomerStripsValues(t *testing.T) {
+	tr := projectableTrace()
+	p := tr.Project(ViewCustomer)
+	if len(p.Inputs) == 0 {
+		t.Fatal("customer view keeps trace inputs")
+	}
+	st := p.Steps[0]
+	if st.Inputs != nil || st.Outputs != nil {
+		t.Fatalf("step values leaked: %+v %+v", st.Inputs, st.Outputs)
+	}
+	if st.Description != "credit lookup" {
+		t.Fatal("customer view keeps descriptions")
+	}
+	if p.Error != "" || st.Hash != tr.Steps[0].Hash {
+		t.Fatalf("error %q leaked or hash rewritten", p.Error)
+	}
+	if p.Attachments != nil || p.SubTraces != nil {
+		t.Fatal("attachments or sub-traces crossed the projection")
+	}
+	if p.Metadata["projection.view"] != "customer" {
+		t.Fatalf("projection unmarked: %v", p.Metadata)
+	}
+	if p.Metadata["user.ticket"] != "T-1" {
+		t.Fatalf("user metadata dropped: %v", p.Metadata)
+	}
+	// The original is untouched.
+	if tr.Steps[0].Inputs == nil || tr.Attachments == nil {
+		t.Fatal("Project mutated the original")
+	}
+}
+
+func TestProjectAuditorSeesEverything(t *testing.T) {
+	tr := projectableTrace()
+	p := tr.Project(ViewAuditor)
+	if p.Error == "" || p.Steps[0].Inputs == nil || len(p.SubTraces) != 1 {
+		t.Fatalf("auditor view dropped content: %+v", p)
+	}
+	for _, sub := range p.SubTraces {
+		if sub.Metadata["projection.view"] != "auditor" {
+			t.Fatal("sub-trace not projected under the same view")
+		}
+	}
+}
+
+func TestZeroViewShowsSkeletonOnly(t *testing.T) {
+	tr := projectableTrace()
+	p := tr.Project(View{Name: "none"})
+	if len(p.Inputs) != 0 || p.Result != nil || p.Error != "" {
+		t.Fatalf("zero view leaked outcome: %+v", p)
+	}
+	if p.Steps[0].Operation != "lookup" {
+		t.Fatal("zero view lost the skeleton")
+	}
+	for k := range p.Metadata {
+		if !strings.HasPrefix(k, "projection.") {
+			t.Fatalf("metadata %q survived a nil filter", k)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package trace
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Propagation header names, shared by HTTP headers and gRPC metadata.
+// Values are plain text so any transport that carries string pairs can
+// forward them.
+const (
+	HeaderTraceID    = "Euclidtrace-Trace-Id"
+	HeaderParentStep = "Euclidtrace-Parent-Step"
+	HeaderChainHash  = "Euclidtrace-Chain-Hash"
+)
+
+// RemoteContext is the identity a caller propagates across a process
+// boundary: which trace the remote work belongs to, the index of the
+// last step recorded before the call left, and that step's chain hash.
+// The hash is what makes stitching trustworthy — a remote trace claims
+// a splice point that only the real parent chain contains.
+type RemoteContext struct {
+	TraceID    string
+	ParentStep int
+	ChainHash  string
+}
+
+// RemoteContext captures the trace's current chain state for
+// propagation. Before any step is recorded, ParentStep is -1 and the
+// chain hash is empty.
+func (tr *Trace) RemoteContext() RemoteContext {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	rc := RemoteContext{TraceID: tr.ID, ParentStep: -1}
+	if n := len(tr.Steps); n > 0 {
+		rc.ParentStep = n - 1
+		rc.ChainHash = tr.Steps[n-1].Hash
+	}
+	return rc
+}
+
+// Carrier is the header access a transport must offer for propagation.
+// net/http's Header satisfies it directly; gRPC metadata and message
+// queues wrap their pairs in a MapCarrier or an equivalent adapter.
+type Carrier interface {
+	Set(key, value string)
+	Get(key string) string
+}
+
+// MapCarrier adapts a plain string map — gRPC metadata, message
+// attributes — as a Carrier.
+type MapCarrier map[string]string
+
+func (c MapCarrier) Set(key, value string) { c[key] = value }
+func (c MapCarrier) Get(key string) string { return c[key] }
+
+// Inject writes the remote context into outgoing headers.
+func Inject(rc RemoteContext, c Carrier) {
+	c.Set(HeaderTraceID, rc.TraceID)
+	c.Set(HeaderParentStep, strconv.Itoa(rc.ParentStep))
+	c.Set(HeaderChainHash, rc.ChainHash)
+}
+
+// Extract reads a remote context from incoming headers; ok is false
+// when the request is not part of a traced computation.
+func Extract(c Carrier) (rc RemoteContext, ok bool) {
+	rc.TraceID = c.Get(HeaderTraceID)
+	if rc.TraceID == "" {
+		return RemoteContext{}, false
+	}
+	step, err := strconv.Atoi(c.Get(HeaderParentStep))
+	if err != nil {
+		return RemoteContext{}, false
+	}
+	rc.ParentStep = step
+	rc.ChainHash = c.Get(HeaderChainHash)
+	return rc, true
+}
+
+// Remote-binding metadata keys recorded by ResumeRemote.
+const (
+	metaRemoteParentID   = "remote.parent_trace_id"
+	metaRemoteParentStep = "remote.parent_step"
+	metaRemoteParentHash = "remote.parent_step_hash"
+)
+
+// ResumeRemote begins the server-side trace of work spawned by a remote
+// caller. The parent binding is recorded in metadata, so the finished
+// trace can later be stitched back into the parent by the collector.
+func ResumeRemote(rc RemoteContext, name string, inputs map[string]any, opts ...Option) *Trace {
+	tr := New(name, inputs, opts...)
+	tr.SetMetadata(metaRemoteParentID, rc.TraceID)
+	tr.SetMetadata(metaRemoteParentStep, strconv.Itoa(rc.ParentStep))
+	tr.SetMetadata(metaRemoteParentHash, rc.ChainHash)
+	return tr
+}
+
+// Stitch embeds a finished remote trace into its parent on the
+// collector side, producing one end-to-end computation. The remote's
+// recorded binding must name this parent, an existing step, and that
+// step's exact chain hash; since chain hashes cover everything up to
+// the splice point, a remote trace cannot be stitched onto a chain it
+// was not spawned from. The remote joins the parent's sub-trace table
+// keyed by content hash — both traces are already terminal, so no
+// hashed content changes.
+func Stitch(parent, remote *Trace) error {
+	if remote.Status == StatusRecording {
+		return fmt.Errorf("trace: stitch: remote trace %s is still recording", remote.ID)
+	}
+	if got := remote.Metadata[metaRemoteParentID]; got != parent.ID {
+		return fmt.Errorf("trace: stitch: remote trace %s binds to parent %q, not %s", remote.ID, got, parent.ID)
+	}
+	step, err := strconv.Atoi(remote.Metadata[metaRemoteParentStep])
+	if err != nil {
+		return fmt.Errorf("trace: stitch: remote trace %s carries no parent step", remote.ID)
+	}
+	hash := remote.Metadata[metaRemoteParentHash]
+	parent.mu.Lock()
+	defer parent.mu.Unlock()
+	switch {
+	case step == -1:
+		if hash != "" {
+			return fmt.Errorf("trace: stitch: remote trace %s claims a chain hash before any parent step", remote.ID)
+		}
+	case step < 0 || step >= len(parent.Steps):
+		return fmt.Errorf("trace: stitch: remote trace %s binds to step %d, parent has %d steps", remote.ID, step, len(parent.Steps))
+	case parent.Steps[step].Hash != hash:
+		return fmt.Errorf("trace: stitch: remote trace %s binds to step %d hash %s, parent chain has %s",
+			remote.ID, step, hash, parent.Steps[step].Hash)
+	}
+	if parent.SubTraces == nil {
+		parent.SubTraces = map[string]*Trace{}
+	}
+	parent.SubTraces[remote.ContentHash()] = remote
+	return nil
+}
@@ -0,0 +1,83 @@
+package trace
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestInjectExtractRoundTrip(t *testing.T) {
+	parent := New("api.request", nil)
+	parent.AddStep("validate", nil, nil)
+	rc := parent.RemoteContext()
+	if rc.ParentStep != 0 || rc.ChainHash != parent.Steps[0].Hash {
+		t.Fatalf("RemoteContext: %+v", rc)
+	}
+
+	hdr := http.Header{}
+	Inject(rc, hdr)
+	got, ok := Extract(hdr)
+	if !ok || got != rc {
+		t.Fatalf("Extract: %+v %v", got, ok)
+	}
+	if _, ok := Extract(http.Header{}); ok {
+		t.Fatal("Extract claimed a context from empty headers")
+	}
+}
+
+func TestRemoteContextBeforeAnySteps(t *testing.T) {
+	tr := New("t", nil)
+	rc := tr.RemoteContext()
+	if rc.ParentStep != -1 || rc.ChainHash != "" {
+		t.Fatalf("RemoteContext of empty trace: %+v", rc)
+	}
+}
+
+func TestStitchBindsByChainHash(t *testing.T) {
+	parent := New("api.request", nil)
+	parent.AddStep("validate", nil, nil)
+	rc := parent.RemoteContext()
+
+	// Server side: resume, work, finish.
+	carrier := MapCarrier{}
+	Inject(rc, carrier)
+	remoteRC, _ := Extract(carrier)
+	remote := ResumeRemote(remoteRC, "backend.work", map[string]any{"q": 1})
+	remote.AddStep("compute", nil, map[string]any{"v": 2})
+	remote.Complete(2)
+
+	parent.AddStep("respond", nil, nil)
+	parent.Complete(nil)
+	if err := Stitch(parent, remote); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := parent.SubTraces[remote.ContentHash()]; !ok {
+		t.Fatal("remote not embedded")
+	}
+}
+
+func TestStitchRejectsForgedBindings(t *testing.T) {
+	parent := New("api.request", nil)
+	parent.AddStep("validate", nil, nil)
+	parent.Complete(nil)
+
+	still := ResumeRemote(parent.RemoteContext(), "backend.work", nil)
+	if err := Stitch(parent, still); err == nil {
+		t.Error("stitched a still-recording remote")
+	}
+
+	wrongParent := ResumeRemote(RemoteContext{TraceID: "0000000000000000", ParentStep: -1}, "backend.work", nil)
+	wrongParent.Complete(nil)
+	if err := Stitch(parent, wrongParent); err == nil {
+		t.Error("stitched a remote bound to another parent")
+	}
+
+	forged := ResumeRemote(RemoteContext{
+		TraceID:    parent.ID,
+		ParentStep: 0,
+		ChainHash:  "not-the-chain-hash",
+	}, "backend.work", nil)
+	forged.Complete(nil)
+	if err := Stitch(parent, forged); err == nil {
+		t.Error("stitched a remote with a forged splice hash")
+	}
+}
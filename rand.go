@@ -0,0 +1,85 @@
+package trace
+
+import (
+	mathrand "math/rand"
+)
+
+// RandOption configures a traced random source.
+type RandOption func(*TracedRand)
+
+// RecordDraws records every draw from the source as a "rand.draw" step, so
+// the exact random values that influenced the computation appear in the
+// trace. Without it only the seed is recorded, which is enough to replay.
+func RecordDraws() RandOption {
+	return func(r *TracedRand) { r.record = true }
+}
+
+// RandName distinguishes multiple random sources within one trace; the seed
+// is recorded under the input key "rand_seed.<name>".
+func RandName(name string) RandOption {
+	return func(r *TracedRand) { r.name = name }
+}
+
+// TracedRand is a seeded pseudo-random source whose seed — and optionally
+// every draw — is recorded on the trace, keeping computations that involve
+// randomness replayable.
+type TracedRand struct {
+	tr     *Trace
+	rng    *mathrand.Rand
+	name   string
+	record bool
+	draws  int
+}
+
+// Rand returns a pseudo-random source seeded with seed, recording the seed as
+// a trace input so the computation remains replayable.
+func Rand(tr *Trace, seed int64, opts ...RandOption) *TracedRand {
+	r := &TracedRand{
+		tr:  tr,
+		rng: mathrand.New(mathrand.NewSource(seed)),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	key := "rand_seed"
+	if r.name != "" {
+		key += "." + r.name
+	}
+	tr.mu.Lock()
+	tr.Inputs[key] = seed
+	tr.mu.Unlock()
+	return r
+}
+
+// Int63 draws a non-negative 63-bit integer.
+func (r *TracedRand) Int63() int64 {
+	v := r.rng.Int63()
+	r.recordDraw("int63", v)
+	return v
+}
+
+// Intn draws an integer in [0, n).
+func (r *TracedRand) Intn(n int) int {
+	v := r.rng.Intn(n)
+	r.recordDraw("intn", v)
+	return v
+}
+
+// Float64 draws a float in [0, 1).
+func (r *TracedRand) Float64() float64 {
+	v := r.rng.Float64()
+	r.recordDraw("float64", v)
+	return v
+}
+
+func (r *TracedRand) recordDraw(kind string, v any) {
+	if !r.record {
+		return
+	}
+	r.draws++
+	inputs := map[string]any{"kind": kind, "draw": r.draws}
+	if r.name != "" {
+		inputs["source"] = r.name
+	}
+	r.tr.AddStep("rand.draw", inputs, map[string]any{"value": v})
+}
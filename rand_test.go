@@ -0,0 +1,42 @@
+package trace
+
+import "testing"
+
+func TestRandRecordsSeedAndReplays(t *testing.T) {
+	tr := New("sim", nil)
+	r := Rand(tr, 42)
+	first := []int{r.Intn(100), r.Intn(100), r.Intn(100)}
+	if tr.Inputs["rand_seed"] != int64(42) {
+		t.Fatalf("seed not recorded: %v", tr.Inputs)
+	}
+	if len(tr.Steps) != 0 {
+		t.Fatal("draws recorded without RecordDraws")
+	}
+	// Replaying from the recorded seed reproduces the draws.
+	replay := Rand(New("sim", nil), 42)
+	for i, want := range first {
+		if got := replay.Intn(100); got != want {
+			t.Fatalf("draw %d: %d, want %d", i, got, want)
+		}
+	}
+}
+
+func TestRandRecordDraws(t *testing.T) {
+	tr := New("sim", nil)
+	r := Rand(tr, 1, RecordDraws(), RandName("jitter"))
+	v := r.Float64()
+	_ = r.Int63()
+	if tr.Inputs["rand_seed.jitter"] != int64(1) {
+		t.Fatalf("named seed missing: %v", tr.Inputs)
+	}
+	if len(tr.Steps) != 2 {
+		t.Fatalf("%d draw steps", len(tr.Steps))
+	}
+	st := tr.Steps[0]
+	if st.Operation != "rand.draw" || st.Inputs["kind"] != "float64" || st.Outputs["value"] != v {
+		t.Fatalf("draw step %+v", st)
+	}
+	if st.Inputs["source"] != "jitter" || st.Inputs["draw"] != 1 {
+		t.Fatalf("draw step inputs %+v", st.Inputs)
+	}
+}
@@ -0,0 +1,117 @@
+// Package rational provides exact fraction arithmetic whose traces spell
+// the work out: every operation records the cross-multiplied raw fraction,
+// and simplification is its own step showing the gcd found and the reduced
+// numerator and denominator — for educational use and for verification
+// where "show your work" is literal.
+package rational
+
+import (
+	"fmt"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Rational is an exact fraction. The denominator is kept positive; the
+// fraction is not necessarily reduced — reduction is an explicit, traced
+// step.
+type Rational struct {
+	Num int64 `json:"num"`
+	Den int64 `json:"den"`
+}
+
+// New returns num/den, normalizing the sign into the numerator.
+func New(num, den int64) (Rational, error) {
+	if den == 0 {
+		return Rational{}, fmt.Errorf("rational: zero denominator")
+	}
+	if den < 0 {
+		num, den = -num, -den
+	}
+	return Rational{Num: num, Den: den}, nil
+}
+
+func (r Rational) String() string {
+	return fmt.Sprintf("%d/%d", r.Num, r.Den)
+}
+
+// gcd returns the greatest common divisor of |a| and |b|.
+func gcd(a, b int64) int64 {
+	if a < 0 {
+		a = -a
+	}
+	if b < 0 {
+		b = -b
+	}
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// Simplify reduces the fraction, recording the gcd found and the reduced
+// form as a step. A fraction already in lowest terms records gcd 1 and is
+// returned unchanged.
+func Simplify(tr *trace.Trace, r Rational) Rational {
+	g := gcd(r.Num, r.Den)
+	if g == 0 {
+		g = 1 // only 0/0, which New rejects
+	}
+	out := Rational{Num: r.Num / g, Den: r.Den / g}
+	tr.AddStep("rational.simplify",
+		map[string]any{"fraction": r},
+		map[string]any{"gcd": g, "result": out, "num": out.Num, "den": out.Den},
+		trace.WithDescription(fmt.Sprintf("gcd(%d, %d) = %d", r.Num, r.Den, g)))
+	return out
+}
+
+// Add records a + b by cross-multiplication, then simplifies as its own
+// step.
+func Add(tr *trace.Trace, a, b Rational) Rational {
+	raw := Rational{Num: a.Num*b.Den + b.Num*a.Den, Den: a.Den * b.Den}
+	record(tr, "rational.add", a, b, raw,
+		fmt.Sprintf("(%d*%d + %d*%d) / (%d*%d)", a.Num, b.Den, b.Num, a.Den, a.Den, b.Den))
+	return Simplify(tr, raw)
+}
+
+// Sub records a - b by cross-multiplication, then simplifies.
+func Sub(tr *trace.Trace, a, b Rational) Rational {
+	raw := Rational{Num: a.Num*b.Den - b.Num*a.Den, Den: a.Den * b.Den}
+	record(tr, "rational.sub", a, b, raw,
+		fmt.Sprintf("(%d*%d - %d*%d) / (%d*%d)", a.Num, b.Den, b.Num, a.Den, a.Den, b.Den))
+	return Simplify(tr, raw)
+}
+
+// Mul records a * b, then simplifies.
+func Mul(tr *trace.Trace, a, b Rational) Rational {
+	raw := Rational{Num: a.Num * b.Num, Den: a.Den * b.Den}
+	record(tr, "rational.mul", a, b, raw,
+		fmt.Sprintf("(%d*%d) / (%d*%d)", a.Num, b.Num, a.Den, b.Den))
+	return Simplify(tr, raw)
+}
+
+// Div records a / b as multiplication by the reciprocal, then simplifies.
+// Division by zero is recorded as an error step.
+func Div(tr *trace.Trace, a, b Rational) (Rational, error) {
+	if b.Num == 0 {
+		err := fmt.Errorf("rational: division of %s by zero", a)
+		tr.AddStep("rational.div",
+			map[string]any{"a": a, "b": b}, nil,
+			trace.WithStepError(err))
+		return Rational{}, err
+	}
+	num, den := a.Num*b.Den, a.Den*b.Num
+	if den < 0 {
+		num, den = -num, -den
+	}
+	raw := Rational{Num: num, Den: den}
+	record(tr, "rational.div", a, b, raw,
+		fmt.Sprintf("(%d*%d) / (%d*%d)", a.Num, b.Den, a.Den, b.Num))
+	return Simplify(tr, raw), nil
+}
+
+func record(tr *trace.Trace, op string, a, b, raw Rational, working string) {
+	tr.AddStep(op,
+		map[string]any{"a": a, "b": b},
+		map[string]any{"raw": raw},
+		trace.WithDescription(working))
+}
@@ -0,0 +1,79 @@
+package rational
+
+import (
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+func mustNew(t *testing.T, num, den int64) Rational {
+	t.Helper()
+	r, err := New(num, den)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestNewNormalizesSign(t *testing.T) {
+	if r := mustNew(t, 1, -2); r != (Rational{Num: -1, Den: 2}) {
+		t.Fatalf("1/-2 = %v", r)
+	}
+	if _, err := New(1, 0); err == nil {
+		t.Fatal("accepted a zero denominator")
+	}
+	if s := mustNew(t, 3, 4).String(); s != "3/4" {
+		t.Fatalf("string %q", s)
+	}
+}
+
+func TestAddShowsTheWork(t *testing.T) {
+	tr := trace.New("t", nil)
+	sum := Add(tr, mustNew(t, 1, 6), mustNew(t, 1, 3))
+	if sum != (Rational{Num: 1, Den: 2}) {
+		t.Fatalf("sum %v", sum)
+	}
+	// Cross-multiplication and simplification are separate steps.
+	if len(tr.Steps) != 2 {
+		t.Fatalf("%d steps", len(tr.Steps))
+	}
+	add, simp := tr.Steps[0], tr.Steps[1]
+	if add.Outputs["raw"] != (Rational{Num: 9, Den: 18}) || add.Description == "" {
+		t.Fatalf("add step %+v", add)
+	}
+	if simp.Operation != "rational.simplify" || simp.Outputs["gcd"] != int64(9) {
+		t.Fatalf("simplify step %+v", simp)
+	}
+}
+
+func TestSubMulDiv(t *testing.T) {
+	tr := trace.New("t", nil)
+	if d := Sub(tr, mustNew(t, 3, 4), mustNew(t, 1, 4)); d != (Rational{Num: 1, Den: 2}) {
+		t.Fatalf("diff %v", d)
+	}
+	if p := Mul(tr, mustNew(t, 2, 3), mustNew(t, 3, 4)); p != (Rational{Num: 1, Den: 2}) {
+		t.Fatalf("product %v", p)
+	}
+	q, err := Div(tr, mustNew(t, 1, 2), mustNew(t, -3, 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q != (Rational{Num: -2, Den: 3}) {
+		t.Fatalf("quotient %v", q)
+	}
+	if _, err := Div(tr, mustNew(t, 1, 2), Rational{Num: 0, Den: 1}); err == nil {
+		t.Fatal("divided by zero")
+	}
+	tr.Complete(nil)
+	if err := tr.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSimplifyLowestTerms(t *testing.T) {
+	tr := trace.New("t", nil)
+	r := Simplify(tr, mustNew(t, 5, 7))
+	if r != (Rational{Num: 5, Den: 7}) || tr.Steps[0].Outputs["gcd"] != int64(1) {
+		t.Fatalf("simplify %v, step %+v", r, tr.Steps[0])
+	}
+}
@@ -0,0 +1,75 @@
+package trace
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+)
+
+// RecoverOption configures RecoverInto.
+type RecoverOption func(*recoverConfig)
+
+type recoverConfig struct {
+	repanic bool
+}
+
+// Repanic makes RecoverInto re-raise the panic after recording it, for
+// callers that want the trace artifact but not to swallow the crash.
+func Repanic() RecoverOption {
+	return func(c *recoverConfig) { c.repanic = true }
+}
+
+// RecoverInto is meant to be deferred inside a traced computation. If the
+// computation panics, it records an error step carrying the panic value and a
+// sanitized stack trace, fails the trace, and — with Repanic — re-raises the
+// panic. Crashes then still leave an explainable artifact:
+//
+//	defer trace.RecoverInto(tr)
+func RecoverInto(tr *Trace, opts ...RecoverOption) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	var cfg recoverConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if tr != nil {
+		err := fmt.Errorf("panic: %v", v)
+		tr.AddStep("panic", map[string]any{
+			"value": fmt.Sprintf("%v", v),
+			"stack": sanitizeStack(debug.Stack()),
+		}, nil, WithStepError(err), WithDescription("computation panicked"))
+		tr.Fail(err)
+	}
+	if cfg.repanic {
+		panic(v)
+	}
+}
+
+// sanitizeStack strips the runtime panic machinery and this package's own
+// frames so the recorded stack begins at the code that panicked.
+func sanitizeStack(stack []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(stack)), "\n")
+	var out []string
+	skipNext := false
+	for i, line := range lines {
+		if i == 0 { // "goroutine N [running]:" header
+			out = append(out, line)
+			continue
+		}
+		if skipNext {
+			skipNext = false
+			continue
+		}
+		if strings.HasPrefix(line, "runtime/debug.Stack") ||
+			strings.HasPrefix(line, "panic(") ||
+			strings.HasPrefix(line, "runtime.gopanic") ||
+			strings.HasPrefix(line, "github.com/euclidtrace/trace.RecoverInto") {
+			skipNext = true
+			continue
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,53 @@
+package trace
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRecoverIntoRecordsPanic(t *testing.T) {
+	tr := New("t", nil)
+	func() {
+		defer RecoverInto(tr)
+		tr.AddStep("work", nil, nil)
+		panic("division table corrupt")
+	}()
+	if tr.Status != StatusFailed {
+		t.Fatalf("status %s", tr.Status)
+	}
+	last := tr.Steps[len(tr.Steps)-1]
+	if last.Operation != "panic" || last.Inputs["value"] != "division table corrupt" {
+		t.Fatalf("panic step: %s %v", last.Operation, last.Inputs)
+	}
+	stack, _ := last.Inputs["stack"].(string)
+	if !strings.Contains(stack, "recover_test.go") {
+		t.Fatalf("stack does not begin at the panicking code:\n%s", stack)
+	}
+	if err := tr.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecoverIntoRepanics(t *testing.T) {
+	tr := New("t", nil)
+	defer func() {
+		if recover() == nil {
+			t.Fatal("panic swallowed despite Repanic")
+		}
+		if tr.Status != StatusFailed {
+			t.Fatalf("status %s", tr.Status)
+		}
+	}()
+	defer RecoverInto(tr, Repanic())
+	panic("boom")
+}
+
+func TestRecoverIntoNoopWithoutPanic(t *testing.T) {
+	tr := New("t", nil)
+	func() {
+		defer RecoverInto(tr)
+	}()
+	if tr.Status != StatusRecording || len(tr.Steps) != 0 {
+		t.Fatalf("RecoverInto touched a healthy trace: %s", tr.Status)
+	}
+}
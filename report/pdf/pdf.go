@@ -0,0 +1,213 @@
+// Package pdf renders a trace as a paginated PDF report — header with trace
+// ID and content hash, inputs, the full derivation, and the result — for
+// regulators that require immutable document formats. The writer emits the
+// PDF object structure directly and has no external dependencies.
+package pdf
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/euclidtrace/trace"
+)
+
+const (
+	pageWidth    = 595 // A4 points
+	pageHeight   = 842
+	marginLeft   = 50
+	topY         = 792
+	lineHeight   = 12
+	linesPerPage = 60
+	maxLineRunes = 95
+)
+
+// Write renders the trace report to w.
+func Write(w io.Writer, tr *trace.Trace) error {
+	pages := paginate(reportLines(tr))
+	return writePDF(w, pages)
+}
+
+// WriteFile renders the trace report at path.
+func WriteFile(path string, tr *trace.Trace) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := Write(f, tr); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// reportLines lays the report out as plain text lines; pagination and PDF
+// framing are applied afterwards.
+func reportLines(tr *trace.Trace) []string {
+	var lines []string
+	add := func(format string, args ...any) {
+		text := fmt.Sprintf(format, args...)
+		for _, wrapped := range wrap(text) {
+			lines = append(lines, wrapped)
+		}
+	}
+	add("Computation Trace Report")
+	add("")
+	add("Trace ID:     %s", tr.ID)
+	add("Name:         %s", tr.Name)
+	add("Status:       %s", tr.Status)
+	add("Content hash: %s", tr.ContentHash())
+	if !tr.Started.IsZero() {
+		add("Started:      %s", tr.Started.Format("2006-01-02 15:04:05 MST"))
+	}
+	add("")
+	add("Inputs")
+	for _, k := range sortedKeys(tr.Inputs) {
+		add("  %s = %v", k, tr.Inputs[k])
+	}
+	add("")
+	add("Derivation")
+	for _, st := range tr.Steps {
+		line := fmt.Sprintf("  %3d. %s", st.Index, st.Operation)
+		if d := trace.Describe(st); d != "" {
+			line += " - " + d
+		}
+		add("%s", line)
+		for _, k := range sortedKeys(st.Inputs) {
+			add("        in  %s = %v", k, st.Inputs[k])
+		}
+		for _, k := range sortedKeys(st.Outputs) {
+			add("        out %s = %v", k, st.Outputs[k])
+		}
+		if st.Err != "" {
+			add("        error: %s", st.Err)
+		}
+		for _, n := range st.Notes {
+			add("        note [%s]: %s", n.Audience, n.Text)
+		}
+	}
+	add("")
+	if tr.Error != "" {
+		add("Error: %s", tr.Error)
+	} else {
+		add("Result: %v", tr.Result)
+	}
+	return lines
+}
+
+func paginate(lines []string) [][]string {
+	var pages [][]string
+	for len(lines) > 0 {
+		n := linesPerPage
+		if n > len(lines) {
+			n = len(lines)
+		}
+		pages = append(pages, lines[:n])
+		lines = lines[n:]
+	}
+	if pages == nil {
+		pages = [][]string{{}}
+	}
+	return pages
+}
+
+// writePDF emits a complete PDF: catalog, page tree, one content stream per
+// page, and a monospace base font, with a correct cross-reference table.
+func writePDF(w io.Writer, pages [][]string) error {
+	// Object numbering: 1 catalog, 2 pages, 3 font, then per page:
+	// page object and content stream.
+	numObjs := 3 + 2*len(pages)
+	objects := make([]string, numObjs+1) // 1-based
+
+	objects[1] = "<< /Type /Catalog /Pages 2 0 R >>"
+	var kids []string
+	for i := range pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 4+2*i))
+	}
+	objects[2] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))
+	objects[3] = "<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>"
+
+	for i, page := range pages {
+		pageObj := 4 + 2*i
+		contentObj := pageObj + 1
+		objects[pageObj] = fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, contentObj)
+		stream := contentStream(page)
+		objects[contentObj] = fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(stream), stream)
+	}
+
+	var b strings.Builder
+	offsets := make([]int, numObjs+1)
+	b.WriteString("%PDF-1.4\n")
+	for i := 1; i <= numObjs; i++ {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i, objects[i])
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n", numObjs+1)
+	b.WriteString("0000000000 65535 f \n")
+	for i := 1; i <= numObjs; i++ {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offsets[i])
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", numObjs+1, xref)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func contentStream(lines []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "BT\n/F1 9 Tf\n%d %d Td\n%d TL\n", marginLeft, topY, lineHeight)
+	for _, line := range lines {
+		fmt.Fprintf(&b, "(%s) Tj\nT*\n", escapePDF(line))
+	}
+	b.WriteString("ET\n")
+	return b.String()
+}
+
+// escapePDF escapes string-literal delimiters and replaces characters
+// outside the base font's range.
+func escapePDF(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		default:
+			if r < 32 || r > 126 {
+				b.WriteByte('?')
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	return b.String()
+}
+
+// wrap splits long lines so they fit the page width, indenting
+// continuations.
+func wrap(s string) []string {
+	runes := []rune(s)
+	if len(runes) <= maxLineRunes {
+		return []string{s}
+	}
+	var out []string
+	for len(runes) > maxLineRunes {
+		out = append(out, string(runes[:maxLineRunes]))
+		runes = append([]rune("      "), runes[maxLineRunes:]...)
+	}
+	return append(out, string(runes))
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,75 @@
+package pdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+func TestWriteProducesPDF(t *testing.T) {
+	tr := trace.New("pricing", map[string]any{"gross": 100.0})
+	tr.AddStep("tax", map[string]any{"gross": 100.0, "rate": 0.2}, map[string]any{"tax": 20.0},
+		trace.WithDescription("sales tax"))
+	tr.Complete(80.0)
+
+	var buf bytes.Buffer
+	if err := Write(&buf, tr); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "%PDF-") {
+		t.Fatalf("output starts %q", out[:16])
+	}
+	if !strings.HasSuffix(strings.TrimSpace(out), "%%EOF") {
+		t.Fatalf("output ends %q", out[len(out)-16:])
+	}
+	// Content streams are plain text in this renderer, so report lines
+	// are findable in the body.
+	for _, want := range []string{tr.ID, "sales tax", "Result: 80"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("report lacks %q", want)
+		}
+	}
+}
+
+func TestWritePaginatesLongTraces(t *testing.T) {
+	tr := trace.New("long", nil)
+	for i := 0; i < 200; i++ {
+		tr.AddStep("step", nil, map[string]any{"i": i})
+	}
+	tr.Complete(nil)
+
+	var buf bytes.Buffer
+	if err := Write(&buf, tr); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	pages := strings.Count(out, "/Type /Page ")
+	if pages < 2 {
+		t.Fatalf("expected multiple pages, found %d in %d bytes", pages, len(out))
+	}
+}
+
+func TestReportEscapesDelimiters(t *testing.T) {
+	tr := trace.New("escape (test) \\ case", nil)
+	tr.Complete(nil)
+	var buf bytes.Buffer
+	if err := Write(&buf, tr); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `escape \(test\) \\ case`) {
+		t.Fatal("parentheses and backslashes not escaped in the content stream")
+	}
+}
+
+func TestWrapBoundsLineLength(t *testing.T) {
+	tr := trace.New("wrap", map[string]any{"note": strings.Repeat("long ", 60)})
+	tr.Complete(nil)
+	for _, line := range reportLines(tr) {
+		if n := len([]rune(line)); n > maxLineRunes {
+			t.Fatalf("line of %d runes: %q", n, line)
+		}
+	}
+}
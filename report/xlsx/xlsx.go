@@ -0,0 +1,263 @@
+// Package xlsx renders a trace as an Excel workbook with separate sheets for
+// inputs, steps, metadata, and the result — numbers stored as numbers,
+// header rows bold and frozen — targeted at finance reviewers. The workbook
+// is written directly in the OOXML spreadsheet format, with no external
+// dependencies.
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Write renders the trace as a workbook.
+func Write(w io.Writer, tr *trace.Trace) error {
+	sheets := []sheet{
+		inputsSheet(tr),
+		stepsSheet(tr),
+		metadataSheet(tr),
+		resultSheet(tr),
+	}
+	zw := zip.NewWriter(w)
+	files := map[string]string{
+		"[Content_Types].xml":        contentTypes(len(sheets)),
+		"_rels/.rels":                rootRels,
+		"xl/workbook.xml":            workbookXML(sheets),
+		"xl/_rels/workbook.xml.rels": workbookRels(len(sheets)),
+		"xl/styles.xml":              stylesXML,
+	}
+	for i, s := range sheets {
+		files[fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1)] = s.render()
+	}
+	for _, name := range []string{
+		"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml",
+		"xl/_rels/workbook.xml.rels", "xl/styles.xml",
+	} {
+		if err := writeZipEntry(zw, name, files[name]); err != nil {
+			return err
+		}
+	}
+	for i := range sheets {
+		name := fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1)
+		if err := writeZipEntry(zw, name, files[name]); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// WriteFile renders the trace as a workbook at path.
+func WriteFile(path string, tr *trace.Trace) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := Write(f, tr); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// sheet is one worksheet: a name, a header row, and data rows of typed
+// cells.
+type sheet struct {
+	name   string
+	header []string
+	rows   [][]any
+}
+
+func inputsSheet(tr *trace.Trace) sheet {
+	s := sheet{name: "Inputs", header: []string{"Input", "Value"}}
+	for _, k := range sortedKeys(tr.Inputs) {
+		s.rows = append(s.rows, []any{k, tr.Inputs[k]})
+	}
+	return s
+}
+
+func stepsSheet(tr *trace.Trace) sheet {
+	s := sheet{name: "Steps", header: []string{
+		"Index", "Operation", "Description", "Inputs", "Outputs", "Error", "Duration",
+	}}
+	for _, st := range tr.Steps {
+		s.rows = append(s.rows, []any{
+			st.Index, st.Operation, st.Description,
+			flatten(st.Inputs), flatten(st.Outputs), st.Err, st.Duration.String(),
+		})
+	}
+	return s
+}
+
+func metadataSheet(tr *trace.Trace) sheet {
+	s := sheet{name: "Metadata", header: []string{"Key", "Value"}}
+	keys := make([]string, 0, len(tr.Metadata))
+	for k := range tr.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		s.rows = append(s.rows, []any{k, tr.Metadata[k]})
+	}
+	return s
+}
+
+func resultSheet(tr *trace.Trace) sheet {
+	return sheet{
+		name:   "Result",
+		header: []string{"Field", "Value"},
+		rows: [][]any{
+			{"Trace ID", tr.ID},
+			{"Name", tr.Name},
+			{"Status", string(tr.Status)},
+			{"Result", tr.Result},
+			{"Error", tr.Error},
+			{"Content hash", tr.ContentHash()},
+		},
+	}
+}
+
+// render emits the worksheet XML with a bold, frozen header row and numeric
+// cells stored as numbers.
+func (s sheet) render() string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	b.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	b.WriteString(`<sheetData>`)
+	b.WriteString(`<row r="1">`)
+	for c, h := range s.header {
+		fmt.Fprintf(&b, `<c r="%s1" t="inlineStr" s="1"><is><t>%s</t></is></c>`, colName(c), escape(h))
+	}
+	b.WriteString(`</row>`)
+	for r, row := range s.rows {
+		fmt.Fprintf(&b, `<row r="%d">`, r+2)
+		for c, v := range row {
+			ref := fmt.Sprintf("%s%d", colName(c), r+2)
+			if num, ok := numeric(v); ok {
+				fmt.Fprintf(&b, `<c r="%s"><v>%s</v></c>`, ref, num)
+			} else {
+				fmt.Fprintf(&b, `<c r="%s" t="inlineStr"><is><t>%s</t></is></c>`, ref, escape(fmt.Sprintf("%v", v)))
+			}
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
+
+// numeric formats ints and floats for storage as spreadsheet numbers.
+func numeric(v any) (string, bool) {
+	switch n := v.(type) {
+	case int:
+		return fmt.Sprintf("%d", n), true
+	case int32:
+		return fmt.Sprintf("%d", n), true
+	case int64:
+		return fmt.Sprintf("%d", n), true
+	case float32:
+		return fmt.Sprintf("%g", n), true
+	case float64:
+		return fmt.Sprintf("%g", n), true
+	}
+	return "", false
+}
+
+func colName(i int) string {
+	name := ""
+	for i >= 0 {
+		name = string(rune('A'+i%26)) + name
+		i = i/26 - 1
+	}
+	return name
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func flatten(m map[string]any) string {
+	parts := make([]string, 0, len(m))
+	for _, k := range sortedKeys(m) {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, m[k]))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func writeZipEntry(zw *zip.Writer, name, content string) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, content)
+	return err
+}
+
+func contentTypes(sheets int) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+const rootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+func workbookXML(sheets []sheet) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, s := range sheets {
+		fmt.Fprintf(&b, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escape(s.name), i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	return b.String()
+}
+
+func workbookRels(sheets int) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i, i)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, sheets+1)
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+// stylesXML declares two cell formats: the default, and a bold variant used
+// by header rows (style index 1).
+const stylesXML = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="1"><fill><patternFill patternType="none"/></fill></fills>` +
+	`<borders count="1"><border/></borders>` +
+	`<cellStyleXfs count="1"><xf/></cellStyleXfs>` +
+	`<cellXfs count="2"><xf xfId="0"/><xf xfId="0" fontId="1" applyFont="1"/></cellXfs>` +
+	`</styleSheet>`
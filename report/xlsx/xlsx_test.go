@@ -0,0 +1,100 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+func reported() *trace.Trace {
+	tr := trace.New("pricing", map[string]any{"gross": 100.0})
+	tr.SetUserMetadata("owner", "pricing-team")
+	tr.AddStep("tax", map[string]any{"gross": 100.0, "rate": 0.2}, map[string]any{"tax": 20.0},
+		trace.WithDescription("sales tax"))
+	tr.Complete(80.0)
+	return tr
+}
+
+func TestWriteProducesWorkbook(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, reported()); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries[f.Name] = string(body)
+	}
+	for _, name := range []string{
+		"[Content_Types].xml",
+		"_rels/.rels",
+		"xl/workbook.xml",
+		"xl/worksheets/sheet1.xml",
+	} {
+		if _, ok := entries[name]; !ok {
+			t.Fatalf("missing workbook entry %q; have %v", name, keys(entries))
+		}
+	}
+	workbook := entries["xl/workbook.xml"]
+	for _, sheet := range []string{"Inputs", "Steps", "Metadata", "Result"} {
+		if !strings.Contains(workbook, sheet) {
+			t.Fatalf("workbook %q lacks sheet %s", workbook, sheet)
+		}
+	}
+	all := strings.Join([]string{
+		entries["xl/worksheets/sheet1.xml"],
+		entries["xl/worksheets/sheet2.xml"],
+		entries["xl/worksheets/sheet3.xml"],
+		entries["xl/worksheets/sheet4.xml"],
+	}, "\n")
+	for _, want := range []string{"gross", "tax", "sales tax", "user.owner"} {
+		if !strings.Contains(all, want) {
+			t.Fatalf("sheets lack %q", want)
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+	var buf bytes.Buffer
+	tr := trace.New("a<b>&c", map[string]any{"note": `"x" & 'y'`})
+	tr.Complete(nil)
+	if err := Write(&buf, tr); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range zr.File {
+		rc, _ := f.Open()
+		body, _ := io.ReadAll(rc)
+		rc.Close()
+		if bytes.Contains(body, []byte("a<b>")) {
+			t.Fatalf("unescaped markup in %s", f.Name)
+		}
+	}
+}
+
+func keys(m map[string]string) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
@@ -0,0 +1,77 @@
+package trace
+
+import (
+	"time"
+)
+
+// Attempt is one try of a retried step: what went wrong and how long the
+// computation backed off before trying again.
+type Attempt struct {
+	Number   int           `json:"number"`
+	Err      string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration,omitempty"`
+	Backoff  time.Duration `json:"backoff,omitempty"`
+}
+
+// RetryOption configures Retry.
+type RetryOption func(*retryConfig)
+
+type retryConfig struct {
+	maxAttempts int
+	backoff     time.Duration
+}
+
+// MaxAttempts caps the number of tries; the default is 3.
+func MaxAttempts(n int) RetryOption {
+	return func(c *retryConfig) { c.maxAttempts = n }
+}
+
+// Backoff sets the initial delay between tries, which doubles after each
+// failure; the default is 100ms.
+func Backoff(d time.Duration) RetryOption {
+	return func(c *retryConfig) { c.backoff = d }
+}
+
+// Retry runs fn until it succeeds or attempts run out, recording every try —
+// error, duration, backoff delay — as sub-entries of a single logical step
+// rather than polluting the main step sequence. The step carries the
+// successful outputs, or the last error when all attempts fail.
+func Retry(tr *Trace, operation string, inputs map[string]any, fn func() (map[string]any, error), opts ...RetryOption) (*Step, error) {
+	cfg := retryConfig{maxAttempts: 3, backoff: 100 * time.Millisecond}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var attempts []Attempt
+	var lastErr error
+	start := time.Now()
+	backoff := cfg.backoff
+	for i := 1; i <= cfg.maxAttempts; i++ {
+		attemptStart := time.Now()
+		outputs, err := fn()
+		attempt := Attempt{Number: i, Duration: time.Since(attemptStart)}
+		if err == nil {
+			attempts = append(attempts, attempt)
+			st := tr.AddStep(operation, inputs, outputs,
+				WithDuration(time.Since(start)), withAttempts(attempts))
+			return st, nil
+		}
+		lastErr = err
+		attempt.Err = err.Error()
+		if i < cfg.maxAttempts {
+			attempt.Backoff = backoff
+		}
+		attempts = append(attempts, attempt)
+		if i < cfg.maxAttempts {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+	st := tr.AddStep(operation, inputs, nil,
+		WithDuration(time.Since(start)), WithStepError(lastErr), withAttempts(attempts))
+	return st, lastErr
+}
+
+func withAttempts(attempts []Attempt) StepOption {
+	return func(st *Step) { st.Attempts = attempts }
+}
@@ -0,0 +1,54 @@
+package trace
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryRecordsAttemptsOnOneStep(t *testing.T) {
+	tr := New("t", nil)
+	calls := 0
+	st, err := Retry(tr, "fetch", map[string]any{"url": "u"},
+		func() (map[string]any, error) {
+			calls++
+			if calls < 3 {
+				return nil, errors.New("transient")
+			}
+			return map[string]any{"body": "ok"}, nil
+		},
+		MaxAttempts(5), Backoff(time.Microsecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 || len(tr.Steps) != 1 {
+		t.Fatalf("calls %d, steps %d", calls, len(tr.Steps))
+	}
+	if len(st.Attempts) != 3 || st.Attempts[0].Err != "transient" || st.Attempts[2].Err != "" {
+		t.Fatalf("attempts %+v", st.Attempts)
+	}
+	if st.Outputs["body"] != "ok" || st.Err != "" {
+		t.Fatalf("step outcome %v %q", st.Outputs, st.Err)
+	}
+}
+
+func TestRetryExhaustionRecordsLastError(t *testing.T) {
+	tr := New("t", nil)
+	st, err := Retry(tr, "fetch", nil,
+		func() (map[string]any, error) { return nil, errors.New("down") },
+		MaxAttempts(2), Backoff(time.Microsecond))
+	if err == nil || st.Err != "down" {
+		t.Fatalf("err %v, step error %q", err, st.Err)
+	}
+	if len(st.Attempts) != 2 {
+		t.Fatalf("attempts %+v", st.Attempts)
+	}
+	// Attempts are bookkeeping: editing them must not break the chain.
+	tr.Complete(nil)
+	data, _ := tr.ToJSON()
+	cp, _ := FromJSON(data)
+	cp.Steps[0].Attempts = nil
+	if err := cp.Verify(); err != nil {
+		t.Fatalf("attempts are hashed: %v", err)
+	}
+}
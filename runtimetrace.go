@@ -0,0 +1,51 @@
+package trace
+
+import (
+	"context"
+	rtrace "runtime/trace"
+)
+
+// WithRuntimeTrace mirrors the trace into the Go execution tracer: the
+// trace becomes a runtime/trace task named after it, every recorded step
+// emits a log event carrying the operation, and Region marks out code
+// spans. When an execution trace is captured with the runtime tracer, its
+// timeline can then be correlated with the deterministic computation trace
+// by trace ID.
+func WithRuntimeTrace() Option {
+	return func(tr *Trace) {
+		tr.rtCtx, tr.rtTask = rtrace.NewTask(context.Background(), "trace:"+tr.Name)
+		rtrace.Log(tr.rtCtx, "trace_id", tr.ID)
+	}
+}
+
+// Region marks a span of code within the trace's runtime/trace task; call
+// the returned func when the span ends. Without WithRuntimeTrace the
+// returned func is a no-op.
+func (tr *Trace) Region(operation string) func() {
+	tr.mu.Lock()
+	ctx := tr.rtCtx
+	tr.mu.Unlock()
+	if ctx == nil {
+		return func() {}
+	}
+	r := rtrace.StartRegion(ctx, operation)
+	return r.End
+}
+
+// runtimeLogLocked emits the step to the execution tracer when the bridge
+// is active.
+func (tr *Trace) runtimeLogLocked(st *Step) {
+	if tr.rtCtx != nil {
+		rtrace.Log(tr.rtCtx, "step", st.Operation)
+	}
+}
+
+// endRuntimeTaskLocked closes the runtime/trace task when the trace reaches
+// a terminal state.
+func (tr *Trace) endRuntimeTaskLocked() {
+	if tr.rtTask != nil {
+		rtrace.Log(tr.rtCtx, "status", string(tr.Status))
+		tr.rtTask.End()
+		tr.rtTask = nil
+	}
+}
@@ -0,0 +1,156 @@
+package trace
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// FieldSpec constrains one named input: its expected type, whether it must
+// be present, and an optional numeric range. An empty Type accepts any
+// value.
+type FieldSpec struct {
+	// Type names the expected kind: "number", "string", or "bool".
+	Type string
+	// Required rejects inputs that omit the field entirely.
+	Required bool
+	// Min and Max bound numeric fields inclusively when set.
+	Min, Max *float64
+}
+
+// InputSchema declares what a computation's inputs must look like. Schemas
+// are registered per trace name; New validates against the registered
+// schema and records its version, so malformed computations are caught at
+// the source rather than discovered in their results.
+type InputSchema struct {
+	Version string
+	Fields  map[string]FieldSpec
+}
+
+// Validate checks the inputs against the schema and returns the first
+// violation found, in field-name order.
+func (s *InputSchema) Validate(inputs map[string]any) error {
+	for _, name := range sortedSpecKeys(s.Fields) {
+		spec := s.Fields[name]
+		v, ok := inputs[name]
+		if !ok {
+			if spec.Required {
+				return fmt.Errorf("trace: schema: required input %q missing", name)
+			}
+			continue
+		}
+		if err := spec.check(name, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (spec FieldSpec) check(name string, v any) error {
+	switch spec.Type {
+	case "":
+	case "number":
+		f, ok := numericValue(v)
+		if !ok {
+			return fmt.Errorf("trace: schema: input %q is %T, want number", name, v)
+		}
+		if spec.Min != nil && f < *spec.Min {
+			return fmt.Errorf("trace: schema: input %q is %v, below minimum %v", name, f, *spec.Min)
+		}
+		if spec.Max != nil && f > *spec.Max {
+			return fmt.Errorf("trace: schema: input %q is %v, above maximum %v", name, f, *spec.Max)
+		}
+	case "string":
+		if _, ok := v.(string); !ok {
+			return fmt.Errorf("trace: schema: input %q is %T, want string", name, v)
+		}
+	case "bool":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("trace: schema: input %q is %T, want bool", name, v)
+		}
+	default:
+		return fmt.Errorf("trace: schema: input %q has unknown type constraint %q", name, spec.Type)
+	}
+	return nil
+}
+
+var (
+	schemaMu sync.Mutex
+	schemas  = map[string]*InputSchema{}
+)
+
+// RegisterSchema declares the input schema for a trace name. Registering a
+// name twice is rejected — changed requirements belong under a new
+// version, registered once per process.
+func RegisterSchema(name string, s *InputSchema) error {
+	if s.Version == "" {
+		return fmt.Errorf("trace: schema for %q needs a version", name)
+	}
+	schemaMu.Lock()
+	defer schemaMu.Unlock()
+	if prev, ok := schemas[name]; ok {
+		return fmt.Errorf("trace: schema for %q already registered at version %s", name, prev.Version)
+	}
+	schemas[name] = s
+	return nil
+}
+
+// SchemaFor returns the registered schema for a trace name.
+func SchemaFor(name string) (*InputSchema, bool) {
+	schemaMu.Lock()
+	defer schemaMu.Unlock()
+	s, ok := schemas[name]
+	return s, ok
+}
+
+// applySchemaLocked validates a new trace's inputs against its registered
+// schema, stamps the schema version, and embeds any violation as a failed
+// assertion step so the malformed start is part of the hashed record.
+func (tr *Trace) applySchemaLocked() {
+	s, ok := SchemaFor(tr.Name)
+	if !ok {
+		return
+	}
+	tr.Metadata["schema.version"] = s.Version
+	if err := s.Validate(tr.Inputs); err != nil {
+		name := "schema:" + tr.Name
+		tr.addStepLocked("assert",
+			map[string]any{"name": name},
+			map[string]any{"passed": false},
+			WithStepError(fmt.Errorf("assertion %s failed", name)),
+			WithDescription(err.Error()))
+	}
+}
+
+// numericValue reports v as a float64 for the numeric kinds inputs
+// commonly carry, including numbers decoded from JSON.
+func numericValue(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func sortedSpecKeys(m map[string]FieldSpec) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,69 @@
+package trace
+
+import (
+	"strings"
+	"testing"
+)
+
+func floatPtr(f float64) *float64 { return &f }
+
+func TestInputSchemaValidate(t *testing.T) {
+	s := &InputSchema{
+		Version: "1",
+		Fields: map[string]FieldSpec{
+			"income": {Type: "number", Required: true, Min: floatPtr(0)},
+			"region": {Type: "string"},
+			"joint":  {Type: "bool"},
+		},
+	}
+	if err := s.Validate(map[string]any{"income": 100, "region": "de", "joint": true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Validate(map[string]any{"region": "de"}); err == nil {
+		t.Fatal("missing required input accepted")
+	}
+	if err := s.Validate(map[string]any{"income": "lots"}); err == nil {
+		t.Fatal("wrong type accepted")
+	}
+	if err := s.Validate(map[string]any{"income": -5.0}); err == nil {
+		t.Fatal("value below minimum accepted")
+	}
+}
+
+func TestRegisteredSchemaStampsAndEmbedsViolations(t *testing.T) {
+	// Registration is per trace name and process-global, so use a name no
+	// other test claims.
+	err := RegisterSchema("schema_test.calc", &InputSchema{
+		Version: "1",
+		Fields: map[string]FieldSpec{
+			"x": {Type: "number", Required: true, Max: floatPtr(10)},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterSchema("schema_test.calc", &InputSchema{Version: "2"}); err == nil {
+		t.Fatal("re-registration accepted")
+	}
+
+	ok := New("schema_test.calc", map[string]any{"x": 3})
+	if ok.Metadata["schema.version"] != "1" {
+		t.Fatalf("schema version metadata %q", ok.Metadata["schema.version"])
+	}
+	if len(ok.FailedAssertions()) != 0 {
+		t.Fatal("valid inputs produced a violation")
+	}
+
+	bad := New("schema_test.calc", map[string]any{"x": 11})
+	failed := bad.FailedAssertions()
+	if len(failed) != 1 {
+		t.Fatalf("%d failed assertions, want 1", len(failed))
+	}
+	if !strings.Contains(failed[0].Description, "above maximum") {
+		t.Fatalf("violation detail %q", failed[0].Description)
+	}
+	bad.Complete(nil)
+	if err := bad.Verify(); err != nil {
+		t.Fatalf("embedded violation broke the chain: %v", err)
+	}
+}
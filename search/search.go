@@ -0,0 +1,172 @@
+// Package search maintains an embedded full-text index over trace names,
+// descriptions, metadata, and string values, so investigations can start
+// from what is remembered about a computation ("discount rate 0.07") rather
+// than from trace IDs.
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Hit is one matching location: a trace-level field or a step field.
+type Hit struct {
+	TraceID string `json:"trace_id"`
+	Name    string `json:"name"`
+	// StepIndex locates the matching step, or -1 for trace-level fields.
+	StepIndex int    `json:"step_index"`
+	Field     string `json:"field"`
+	Text      string `json:"text"`
+}
+
+// posting ties an indexed document (one searchable field occurrence) back to
+// its location.
+type posting struct {
+	hit Hit
+}
+
+// Index is an in-memory inverted index over traces.
+type Index struct {
+	docs     []posting
+	inverted map[string][]int // token -> doc ids, ascending
+}
+
+// NewIndex returns an empty index.
+func NewIndex() *Index {
+	return &Index{inverted: map[string][]int{}}
+}
+
+// Source yields traces to index; store.Store satisfies it.
+type Source interface {
+	Each(fn func(tr *trace.Trace) error) error
+}
+
+// AddStore indexes every trace from the source.
+func (ix *Index) AddStore(src Source) error {
+	return src.Each(func(tr *trace.Trace) error {
+		ix.Add(tr)
+		return nil
+	})
+}
+
+// Add indexes one trace: its name, metadata, inputs, and every step's
+// description, operation, and string values.
+func (ix *Index) Add(tr *trace.Trace) {
+	ix.addDoc(tr, -1, "name", tr.Name)
+	for k, v := range tr.Metadata {
+		ix.addDoc(tr, -1, "metadata."+k, v)
+	}
+	for k, v := range tr.Inputs {
+		ix.addDoc(tr, -1, "input."+k, fmt.Sprintf("%v", v))
+	}
+	ix.addDoc(tr, -1, "result", fmt.Sprintf("%v", tr.Result))
+	for _, st := range tr.Steps {
+		ix.addDoc(tr, st.Index, "operation", st.Operation)
+		if st.Description != "" {
+			ix.addDoc(tr, st.Index, "description", st.Description)
+		}
+		for k, v := range st.Inputs {
+			ix.addDoc(tr, st.Index, "input."+k, fmt.Sprintf("%v", v))
+		}
+		for k, v := range st.Outputs {
+			ix.addDoc(tr, st.Index, "output."+k, fmt.Sprintf("%v", v))
+		}
+	}
+}
+
+func (ix *Index) addDoc(tr *trace.Trace, stepIndex int, field, text string) {
+	tokens := tokenize(text)
+	if len(tokens) == 0 {
+		return
+	}
+	id := len(ix.docs)
+	ix.docs = append(ix.docs, posting{hit: Hit{
+		TraceID:   tr.ID,
+		Name:      tr.Name,
+		StepIndex: stepIndex,
+		Field:     field,
+		Text:      text,
+	}})
+	seen := map[string]bool{}
+	for _, tok := range tokens {
+		if seen[tok] {
+			continue
+		}
+		seen[tok] = true
+		ix.inverted[tok] = append(ix.inverted[tok], id)
+	}
+}
+
+// Search returns the locations whose text contains every token of the
+// query, ordered by trace ID and step index.
+func (ix *Index) Search(query string) []Hit {
+	tokens := tokenize(query)
+	if len(tokens) == 0 {
+		return nil
+	}
+	ids := ix.inverted[tokens[0]]
+	for _, tok := range tokens[1:] {
+		ids = intersect(ids, ix.inverted[tok])
+		if len(ids) == 0 {
+			return nil
+		}
+	}
+	hits := make([]Hit, 0, len(ids))
+	for _, id := range ids {
+		hits = append(hits, ix.docs[id].hit)
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].TraceID != hits[j].TraceID {
+			return hits[i].TraceID < hits[j].TraceID
+		}
+		return hits[i].StepIndex < hits[j].StepIndex
+	})
+	return hits
+}
+
+// Search builds an ephemeral index over the source and queries it — a
+// convenience for one-off investigations over a store.
+func Search(src Source, query string) ([]Hit, error) {
+	ix := NewIndex()
+	if err := ix.AddStore(src); err != nil {
+		return nil, err
+	}
+	return ix.Search(query), nil
+}
+
+// tokenize lowercases and splits on everything except letters, digits, and
+// interior dots, so "0.07" survives as one token.
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9') && r != '.'
+	})
+	var tokens []string
+	for _, f := range fields {
+		f = strings.Trim(f, ".")
+		if f != "" {
+			tokens = append(tokens, f)
+		}
+	}
+	return tokens
+}
+
+func intersect(a, b []int) []int {
+	var out []int
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, a[i])
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	return out
+}
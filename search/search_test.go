@@ -0,0 +1,78 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+type traces []*trace.Trace
+
+func (ts traces) Each(fn func(tr *trace.Trace) error) error {
+	for _, tr := range ts {
+		if err := fn(tr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func indexed() (*Index, *trace.Trace) {
+	tr := trace.New("quarterly-pricing", map[string]any{"rate": 0.07})
+	tr.AddStep("discount", map[string]any{"rate": 0.07}, map[string]any{"price": 93.0},
+		trace.WithDescription("applied the discount rate"))
+	tr.Complete(93.0)
+	ix := NewIndex()
+	ix.Add(tr)
+	return ix, tr
+}
+
+func TestSearchFindsValuesAndDescriptions(t *testing.T) {
+	ix, tr := indexed()
+	hits := ix.Search("0.07")
+	if len(hits) == 0 {
+		t.Fatal("numeric value not found")
+	}
+	for _, h := range hits {
+		if h.TraceID != tr.ID {
+			t.Fatalf("hit %+v", h)
+		}
+	}
+	hits = ix.Search("discount rate")
+	found := false
+	for _, h := range hits {
+		if h.Field == "description" && h.StepIndex == 0 {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("description not matched by a multi-token query: %+v", hits)
+	}
+	if hits := ix.Search("quarterly"); len(hits) != 1 || hits[0].Field != "name" || hits[0].StepIndex != -1 {
+		t.Fatalf("name hit %+v", hits)
+	}
+}
+
+func TestSearchRequiresEveryToken(t *testing.T) {
+	ix, _ := indexed()
+	if hits := ix.Search("discount zebra"); hits != nil {
+		t.Fatalf("partial match returned %+v", hits)
+	}
+	if hits := ix.Search(""); hits != nil {
+		t.Fatalf("empty query returned %+v", hits)
+	}
+}
+
+func TestSearchOverSource(t *testing.T) {
+	a := trace.New("alpha", nil)
+	a.Complete("needle")
+	b := trace.New("beta", nil)
+	b.Complete("hay")
+	hits, err := Search(traces{a, b}, "needle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 1 || hits[0].TraceID != a.ID || hits[0].Field != "result" {
+		t.Fatalf("hits %+v", hits)
+	}
+}
@@ -0,0 +1,29 @@
+package trace
+
+import "encoding/json"
+
+// ToJSON serializes the trace in the canonical JSON form (FormatVersion).
+// Attachments are not part of the canonical form; they travel in bundles.
+func (tr *Trace) ToJSON() ([]byte, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	type plain Trace // shed methods so Marshal does not recurse
+	return json.MarshalIndent((*plain)(tr), "", "  ")
+}
+
+// FromJSON reconstructs a trace from its canonical JSON form.
+func FromJSON(data []byte) (*Trace, error) {
+	type plain Trace
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+	tr := (*Trace)(&p)
+	if tr.Metadata == nil {
+		tr.Metadata = map[string]string{}
+	}
+	if tr.Inputs == nil {
+		tr.Inputs = map[string]any{}
+	}
+	return tr, nil
+}
@@ -0,0 +1,38 @@
+package trace
+
+// Severity grades a step. Ordinary steps are info (the zero value, left
+// implicit); warning marks questionable-but-tolerated conditions such as
+// clamped values or fallbacks; error marks steps that went wrong.
+type Severity string
+
+const (
+	SeverityInfo    Severity = "info"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// WithSeverity grades the step. Severity is part of the step's hashed
+// content: downgrading a recorded warning breaks the chain.
+func WithSeverity(s Severity) StepOption {
+	return func(st *Step) { st.Severity = s }
+}
+
+// Warn is WithSeverity(SeverityWarning).
+func Warn() StepOption {
+	return WithSeverity(SeverityWarning)
+}
+
+// Warnings returns the steps graded warning or worse, so computations
+// that succeeded but hit questionable conditions surface them
+// prominently in reports.
+func (tr *Trace) Warnings() []*Step {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	var out []*Step
+	for _, st := range tr.Steps {
+		if st.Severity == SeverityWarning || st.Severity == SeverityError {
+			out = append(out, st)
+		}
+	}
+	return out
+}
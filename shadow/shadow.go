@@ -0,0 +1,123 @@
+// Package shadow runs an old and a new implementation of a computation
+// side by side on the same inputs and records a meta-trace of the
+// comparison — the standard pattern for rolling out a formula change:
+// ship the new code dark, shadow it against the old, and promote it once
+// the comparisons agree.
+package shadow
+
+import (
+	"fmt"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Impl is one implementation of the computation under comparison. It
+// records its work on the supplied trace and returns the result; the
+// harness completes or fails the trace from the return values.
+type Impl func(tr *trace.Trace, inputs map[string]any) (any, error)
+
+// Comparison is the outcome of one shadow run: both traces, the
+// meta-trace that diffed them, and the verdicts.
+type Comparison struct {
+	Old *trace.Trace
+	New *trace.Trace
+	// Meta traces the comparison itself. Both runs are embedded as
+	// sub-traces and each diff verdict is a step, so the decision to
+	// trust (or distrust) the new implementation is itself a verifiable
+	// computation.
+	Meta *trace.Trace
+	// ResultsMatch reports whether both implementations produced the
+	// same final value.
+	ResultsMatch bool
+	// StatusesMatch reports whether both runs ended in the same state.
+	StatusesMatch bool
+	// OldOnly and NewOnly list step operations recorded by just one
+	// side, showing where the implementations structurally diverge.
+	OldOnly []string
+	NewOnly []string
+}
+
+// Agrees reports whether the new implementation can stand in for the
+// old: same result and same terminal state. Structural divergence alone
+// does not block agreement — a rewrite is expected to take different
+// steps to the same answer.
+func (c *Comparison) Agrees() bool {
+	return c.ResultsMatch && c.StatusesMatch
+}
+
+// Run executes both implementations on the same inputs, diffs the two
+// traces, and returns the completed comparison. The meta-trace finishes
+// with the agreement verdict as its result.
+func Run(name string, inputs map[string]any, oldImpl, newImpl Impl) *Comparison {
+	c := &Comparison{
+		Old:  execute(name, inputs, oldImpl),
+		New:  execute(name, inputs, newImpl),
+		Meta: trace.New("shadow."+name, inputs),
+	}
+	c.Meta.AddSubTrace("shadow.old", c.Old)
+	c.Meta.AddSubTrace("shadow.new", c.New)
+
+	c.ResultsMatch = trace.HashValue(c.Old.Result) == trace.HashValue(c.New.Result)
+	opts := []trace.StepOption{}
+	if !c.ResultsMatch {
+		opts = append(opts, trace.Warn(),
+			trace.WithDescription(fmt.Sprintf("old result %v, new result %v", c.Old.Result, c.New.Result)))
+	}
+	c.Meta.AddStep("shadow.compare_results",
+		map[string]any{"old": trace.HashValue(c.Old.Result), "new": trace.HashValue(c.New.Result)},
+		map[string]any{"match": c.ResultsMatch},
+		opts...)
+
+	c.StatusesMatch = c.Old.Status == c.New.Status
+	opts = opts[:0]
+	if !c.StatusesMatch {
+		opts = append(opts, trace.Warn())
+	}
+	c.Meta.AddStep("shadow.compare_status",
+		map[string]any{"old": string(c.Old.Status), "new": string(c.New.Status)},
+		map[string]any{"match": c.StatusesMatch},
+		opts...)
+
+	c.OldOnly, c.NewOnly = operationDiff(c.Old, c.New)
+	c.Meta.AddStep("shadow.compare_operations",
+		map[string]any{"old_steps": len(c.Old.Steps), "new_steps": len(c.New.Steps)},
+		map[string]any{"old_only": c.OldOnly, "new_only": c.NewOnly})
+
+	c.Meta.Complete(c.Agrees())
+	return c
+}
+
+// execute runs one implementation under its own trace, finishing it from
+// the implementation's return values.
+func execute(name string, inputs map[string]any, impl Impl) *trace.Trace {
+	tr := trace.New(name, inputs)
+	result, err := impl(tr, inputs)
+	if err != nil {
+		tr.Fail(err)
+	} else {
+		tr.Complete(result)
+	}
+	return tr
+}
+
+// operationDiff returns the step operations that appear in only one
+// trace, in first-appearance order.
+func operationDiff(a, b *trace.Trace) (aOnly, bOnly []string) {
+	return onlyIn(a, b), onlyIn(b, a)
+}
+
+func onlyIn(a, b *trace.Trace) []string {
+	other := map[string]bool{}
+	for _, st := range b.Steps {
+		other[st.Operation] = true
+	}
+	seen := map[string]bool{}
+	var out []string
+	for _, st := range a.Steps {
+		if !other[st.Operation] && !seen[st.Operation] {
+			seen[st.Operation] = true
+			out = append(out, st.Operation)
+		}
+	}
+	return out
+}
@@ -0,0 +1,77 @@
+package shadow
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+func oldPricing(tr *trace.Trace, inputs map[string]any) (any, error) {
+	gross := inputs["gross"].(float64)
+	tax := gross * 0.2
+	tr.AddStep("tax", map[string]any{"gross": gross}, map[string]any{"tax": tax})
+	tr.AddStep("subtract", map[string]any{"tax": tax}, map[string]any{"net": gross - tax})
+	return gross - tax, nil
+}
+
+func newPricing(tr *trace.Trace, inputs map[string]any) (any, error) {
+	gross := inputs["gross"].(float64)
+	net := gross * 0.8
+	tr.AddStep("net_rate", map[string]any{"gross": gross}, map[string]any{"net": net})
+	return net, nil
+}
+
+func TestRunAgreesOnMatchingResults(t *testing.T) {
+	c := Run("pricing", map[string]any{"gross": 100.0}, oldPricing, newPricing)
+	if !c.Agrees() || !c.ResultsMatch || !c.StatusesMatch {
+		t.Fatalf("comparison %+v", c)
+	}
+	// Structural divergence is reported but does not block agreement.
+	if len(c.OldOnly) != 2 || len(c.NewOnly) != 1 || c.NewOnly[0] != "net_rate" {
+		t.Fatalf("op diff %v %v", c.OldOnly, c.NewOnly)
+	}
+	if c.Meta.Result != true {
+		t.Fatalf("meta result %v", c.Meta.Result)
+	}
+	if len(c.Meta.SubTraces) != 2 {
+		t.Fatalf("sub-traces %d", len(c.Meta.SubTraces))
+	}
+	if err := c.Meta.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunFlagsDivergentResults(t *testing.T) {
+	drifted := func(tr *trace.Trace, inputs map[string]any) (any, error) {
+		return 79.99, nil
+	}
+	c := Run("pricing", map[string]any{"gross": 100.0}, oldPricing, drifted)
+	if c.Agrees() || c.ResultsMatch {
+		t.Fatalf("comparison %+v", c)
+	}
+	// Steps 0 and 1 embed the two runs; the verdicts follow.
+	st := c.Meta.Steps[2]
+	if st.Operation != "shadow.compare_results" || st.Outputs["match"] != false {
+		t.Fatalf("compare step %+v", st)
+	}
+	if st.Severity != trace.SeverityWarning || st.Description == "" {
+		t.Fatalf("divergence not flagged: %+v", st)
+	}
+}
+
+func TestRunFlagsDivergentStatus(t *testing.T) {
+	failing := func(tr *trace.Trace, inputs map[string]any) (any, error) {
+		return nil, errors.New("unsupported currency")
+	}
+	c := Run("pricing", map[string]any{"gross": 100.0}, oldPricing, failing)
+	if c.Agrees() || c.StatusesMatch {
+		t.Fatalf("comparison %+v", c)
+	}
+	if c.New.Status != trace.StatusFailed || c.Old.Status != trace.StatusCompleted {
+		t.Fatalf("statuses %s %s", c.Old.Status, c.New.Status)
+	}
+	if c.Meta.Result != false {
+		t.Fatalf("meta result %v", c.Meta.Result)
+	}
+}
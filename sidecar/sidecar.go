@@ -0,0 +1,74 @@
+// Package sidecar writes and verifies SHA-256 sidecar files for serialized
+// traces and bundles, providing integrity checks in object stores that do
+// not validate content themselves. Sidecars use the conventional sha256sum
+// line format, so standard tooling can check them too.
+package sidecar
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Ext is the extension appended to the data file's path for its sidecar.
+const Ext = ".sha256"
+
+// Write computes the SHA-256 of the file at path and writes it to
+// path+Ext in sha256sum format, returning the sidecar path.
+func Write(path string) (string, error) {
+	sum, err := fileSum(path)
+	if err != nil {
+		return "", err
+	}
+	sidecarPath := path + Ext
+	line := fmt.Sprintf("%s  %s\n", sum, filepath.Base(path))
+	if err := os.WriteFile(sidecarPath, []byte(line), 0o644); err != nil {
+		return "", err
+	}
+	return sidecarPath, nil
+}
+
+// Verify recomputes the SHA-256 of the file at path and checks it against
+// its sidecar.
+func Verify(path string) error {
+	f, err := os.Open(path + Ext)
+	if err != nil {
+		return fmt.Errorf("sidecar: %w", err)
+	}
+	defer f.Close()
+	line, err := bufio.NewReader(f).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("sidecar: read %s: %w", path+Ext, err)
+	}
+	fields := strings.Fields(line)
+	if len(fields) < 1 {
+		return fmt.Errorf("sidecar: %s is empty", path+Ext)
+	}
+	want := fields[0]
+	got, err := fileSum(path)
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return fmt.Errorf("sidecar: %s checksum mismatch: file is %s, sidecar says %s", path, got, want)
+	}
+	return nil
+}
+
+func fileSum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,61 @@
+package sidecar
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteAndVerify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calc.tracebundle")
+	if err := os.WriteFile(path, []byte("bundle bytes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sidecarPath, err := Write(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sidecarPath != path+Ext {
+		t.Fatalf("sidecar at %q", sidecarPath)
+	}
+	// sha256sum line format: <hex>  <basename>
+	line, err := os.ReadFile(sidecarPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := strings.Fields(string(line))
+	if len(fields) != 2 || len(fields[0]) != 64 || fields[1] != "calc.tracebundle" {
+		t.Fatalf("sidecar line %q", line)
+	}
+	if err := Verify(path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyDetectsModification(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calc.tracebundle")
+	if err := os.WriteFile(path, []byte("bundle bytes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Write(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("bundle bytes, modified"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err := Verify(path)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("verify after modification: %v", err)
+	}
+}
+
+func TestVerifyWithoutSidecar(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calc.tracebundle")
+	if err := os.WriteFile(path, []byte("bundle bytes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(path); err == nil {
+		t.Fatal("verified without a sidecar")
+	}
+}
@@ -0,0 +1,120 @@
+// Package sigfig tracks significant figures through traced arithmetic.
+// Values carry how many figures they are good for, operations compute the
+// figures their output justifies — the least-figures rule for
+// multiplication and division, the least-decimal-places rule for addition
+// and subtraction — and steps whose raw result claims more precision than
+// the inputs justify are flagged with a warning and reported rounded.
+package sigfig
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Value is a number with the count of significant figures it is good for.
+type Value struct {
+	X    float64 `json:"x"`
+	Figs int     `json:"figs"`
+}
+
+// New returns a value good to the given number of significant figures.
+func New(x float64, figs int) Value {
+	if figs < 1 {
+		figs = 1
+	}
+	return Value{X: x, Figs: figs}
+}
+
+func (v Value) String() string {
+	return fmt.Sprintf("%.*g", v.Figs, v.X)
+}
+
+// Round returns x rounded to figs significant figures.
+func Round(x float64, figs int) float64 {
+	if x == 0 || math.IsInf(x, 0) || math.IsNaN(x) {
+		return x
+	}
+	scale := math.Pow(10, float64(figs-1-order(x)))
+	return math.Round(x*scale) / scale
+}
+
+// order returns the power of ten of x's leading digit.
+func order(x float64) int {
+	return int(math.Floor(math.Log10(math.Abs(x))))
+}
+
+// decimalPlaces returns how many digits past the decimal point the value
+// is good for; negative when the last significant digit sits left of the
+// point.
+func (v Value) decimalPlaces() int {
+	if v.X == 0 {
+		return v.Figs - 1
+	}
+	return v.Figs - 1 - order(v.X)
+}
+
+// Add records a + b. Addition keeps the decimal places of the least
+// precise operand.
+func Add(tr *trace.Trace, a, b Value) Value {
+	return additive(tr, "sigfig.add", a, b, a.X+b.X)
+}
+
+// Sub records a - b under the same decimal-places rule.
+func Sub(tr *trace.Trace, a, b Value) Value {
+	return additive(tr, "sigfig.sub", a, b, a.X-b.X)
+}
+
+func additive(tr *trace.Trace, op string, a, b Value, raw float64) Value {
+	places := a.decimalPlaces()
+	if bp := b.decimalPlaces(); bp < places {
+		places = bp
+	}
+	figs := 1
+	if raw != 0 {
+		figs = order(raw) + 1 + places
+		if figs < 1 {
+			figs = 1
+		}
+	}
+	out := Value{X: Round(raw, figs), Figs: figs}
+	record(tr, op, a, b, raw, out)
+	return out
+}
+
+// Mul records a * b. Multiplication keeps the significant figures of the
+// least precise operand.
+func Mul(tr *trace.Trace, a, b Value) Value {
+	return multiplicative(tr, "sigfig.mul", a, b, a.X*b.X)
+}
+
+// Div records a / b under the same least-figures rule.
+func Div(tr *trace.Trace, a, b Value) Value {
+	return multiplicative(tr, "sigfig.div", a, b, a.X/b.X)
+}
+
+func multiplicative(tr *trace.Trace, op string, a, b Value, raw float64) Value {
+	figs := a.Figs
+	if b.Figs < figs {
+		figs = b.Figs
+	}
+	out := Value{X: Round(raw, figs), Figs: figs}
+	record(tr, op, a, b, raw, out)
+	return out
+}
+
+// record flags the step with a warning when the raw result carried more
+// precision than the inputs justify, so over-precise reporting is visible
+// in the trace rather than silently truncated.
+func record(tr *trace.Trace, op string, a, b Value, raw float64, out Value) {
+	opts := []trace.StepOption{}
+	if raw != out.X {
+		opts = append(opts, trace.Warn(),
+			trace.WithDescription(fmt.Sprintf("raw result %g exceeds justified precision; reported to %d significant figures", raw, out.Figs)))
+	}
+	tr.AddStep(op,
+		map[string]any{"a": a, "b": b},
+		map[string]any{"raw": raw, "result": out, "figs": out.Figs},
+		opts...)
+}
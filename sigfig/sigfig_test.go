@@ -0,0 +1,69 @@
+package sigfig
+
+import (
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+func TestRound(t *testing.T) {
+	cases := []struct {
+		x    float64
+		figs int
+		want float64
+	}{
+		{123.456, 3, 123},
+		{123.456, 5, 123.46},
+		{0.0012345, 2, 0.0012},
+		{-9876, 2, -9900},
+		{0, 3, 0},
+	}
+	for _, c := range cases {
+		if got := Round(c.x, c.figs); got != c.want {
+			t.Fatalf("Round(%g, %d) = %g, want %g", c.x, c.figs, got, c.want)
+		}
+	}
+}
+
+func TestMulKeepsLeastFigures(t *testing.T) {
+	tr := trace.New("t", nil)
+	// 3.1 (2 figs) * 4.567 (4 figs) = 14.1577 raw, justified to 2 figs.
+	out := Mul(tr, New(3.1, 2), New(4.567, 4))
+	if out.Figs != 2 || out.X != 14 {
+		t.Fatalf("product %+v", out)
+	}
+	st := tr.Steps[0]
+	if st.Outputs["raw"] != 3.1*4.567 || st.Severity != trace.SeverityWarning {
+		t.Fatalf("over-precision not flagged: %+v", st)
+	}
+	out = Div(tr, New(10.0, 3), New(4.0, 3))
+	if out.X != 2.5 || out.Figs != 3 {
+		t.Fatalf("quotient %+v", out)
+	}
+	// An exact result carries no warning.
+	if tr.Steps[1].Severity == trace.SeverityWarning {
+		t.Fatalf("exact division flagged: %+v", tr.Steps[1])
+	}
+}
+
+func TestAddKeepsLeastDecimalPlaces(t *testing.T) {
+	tr := trace.New("t", nil)
+	// 12.1 is good to one decimal place; 0.234 to three. The sum keeps one.
+	out := Add(tr, New(12.1, 3), New(0.234, 3))
+	if out.X != 12.3 {
+		t.Fatalf("sum %+v", out)
+	}
+	out = Sub(tr, New(12.1, 3), New(0.04, 1))
+	if out.X != 12.1 {
+		t.Fatalf("difference %+v", out)
+	}
+}
+
+func TestNewClampsAndFormats(t *testing.T) {
+	if v := New(1.5, 0); v.Figs != 1 {
+		t.Fatalf("figs %d", v.Figs)
+	}
+	if s := New(123.456, 4).String(); s != "123.5" {
+		t.Fatalf("string %q", s)
+	}
+}
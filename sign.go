@@ -0,0 +1,45 @@
+package trace
+
+import (
+	"crypto/ed25519"
+	"fmt"
+)
+
+// Signature is a detached signature over a trace's content hash. It does not
+// alter the trace itself, so a trace can accumulate signatures from several
+// parties after completion.
+type Signature struct {
+	KeyID       string `json:"key_id"`
+	Algorithm   string `json:"algorithm"`
+	ContentHash string `json:"content_hash"`
+	Signature   []byte `json:"signature"`
+}
+
+// Sign signs the trace's content hash with an Ed25519 key. KeyID identifies
+// which key was used so verifiers can look up the matching public key.
+func Sign(tr *Trace, keyID string, key ed25519.PrivateKey) Signature {
+	hash := tr.ContentHash()
+	return Signature{
+		KeyID:       keyID,
+		Algorithm:   "ed25519",
+		ContentHash: hash,
+		Signature:   ed25519.Sign(key, []byte(hash)),
+	}
+}
+
+// Verify checks the signature against the trace's current content hash and
+// the given public key. It fails if the trace content changed since signing
+// or the signature was not produced by the key.
+func (s Signature) Verify(tr *Trace, pub ed25519.PublicKey) error {
+	if s.Algorithm != "ed25519" {
+		return fmt.Errorf("trace: unsupported signature algorithm %q", s.Algorithm)
+	}
+	hash := tr.ContentHash()
+	if s.ContentHash != hash {
+		return fmt.Errorf("trace: content hash mismatch: signed %s, trace is %s", s.ContentHash, hash)
+	}
+	if !ed25519.Verify(pub, []byte(hash), s.Signature) {
+		return fmt.Errorf("trace: signature by key %q does not verify", s.KeyID)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package trace
+
+import (
+	"context"
+	"crypto/ed25519"
+	"testing"
+)
+
+func testKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pub, priv
+}
+
+func TestSignatureBindsContent(t *testing.T) {
+	pub, priv := testKey(t)
+	tr := New("calc", map[string]any{"x": 1})
+	tr.AddStep("op", nil, map[string]any{"y": 2})
+	tr.Complete(2)
+
+	sig := Sign(tr, "auditor-key", priv)
+	if err := sig.Verify(tr, pub); err != nil {
+		t.Fatal(err)
+	}
+	// Content drift after signing is detected.
+	data, _ := tr.ToJSON()
+	cp, _ := FromJSON(data)
+	cp.Result = 3
+	if err := sig.Verify(cp, pub); err == nil {
+		t.Fatal("signature verified against changed content")
+	}
+	// A different key does not verify.
+	otherPub, _ := testKey(t)
+	if err := sig.Verify(tr, otherPub); err == nil {
+		t.Fatal("signature verified with the wrong key")
+	}
+}
+
+func TestStepAuthorSignature(t *testing.T) {
+	pub, priv := testKey(t)
+	id := Identity{Service: "pricing", Principal: "batch", KeyID: "k1"}
+	tr := New("t", nil)
+	st := tr.AddStep("op", nil, nil, WithAuthor(id))
+	if err := SignStep(st, priv); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyStepAuthor(st, pub); err != nil {
+		t.Fatal(err)
+	}
+	// The signature binds the author fields, not just the hash.
+	st.Author.Principal = "someone-else"
+	if err := VerifyStepAuthor(st, pub); err == nil {
+		t.Fatal("rewritten principal still verified")
+	}
+}
+
+func TestStepAuthorIsNotHashed(t *testing.T) {
+	tr := New("t", nil)
+	tr.AddStep("op", nil, nil, WithAuthor(Identity{Service: "s"}))
+	tr.Complete(nil)
+	if err := tamper(t, tr, func(tr *Trace) { tr.Steps[0].Author = nil }); err != nil {
+		t.Fatalf("author should sit outside the chain hash: %v", err)
+	}
+}
+
+func TestAuthorFromContext(t *testing.T) {
+	id := Identity{Service: "svc", KeyID: "k"}
+	ctx := NewIdentityContext(context.Background(), id)
+	tr := New("t", nil)
+	st := tr.AddStep("op", nil, nil, WithAuthorFromContext(ctx))
+	if st.Author == nil || st.Author.Service != "svc" {
+		t.Fatalf("author %+v", st.Author)
+	}
+	plain := tr.AddStep("op", nil, nil, WithAuthorFromContext(context.Background()))
+	if plain.Author != nil {
+		t.Fatalf("identity invented from an empty context: %+v", plain.Author)
+	}
+	if _, ok := IdentityFromContext(context.Background()); ok {
+		t.Fatal("IdentityFromContext reported an identity on an empty context")
+	}
+	if err := SignStep(plain, nil); err == nil {
+		t.Fatal("signed a step with no author")
+	}
+}
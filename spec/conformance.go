@@ -0,0 +1,107 @@
+// Package spec carries the trace format specification (SPEC.md) and its
+// conformance vectors (vectors.json), and checks this implementation
+// against them. Implementations in other languages consume vectors.json
+// directly; Check exists so a change to the Go hashing or encoding that
+// breaks byte compatibility fails loudly here.
+package spec
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/euclidtrace/trace"
+)
+
+//go:embed vectors.json
+var vectorData []byte
+
+// Vectors is the conformance suite: expected canonical encodings, value
+// hashes, trace IDs, and the hashes of a full serialized trace.
+type Vectors struct {
+	Version int           `json:"version"`
+	Values  []ValueVector `json:"values"`
+	IDs     []IDVector    `json:"ids"`
+	Trace   TraceVector   `json:"trace"`
+}
+
+// ValueVector pairs a JSON value with its expected canonical encoding
+// and value hash.
+type ValueVector struct {
+	Value     json.RawMessage `json:"value"`
+	Canonical string          `json:"canonical"`
+	Hash      string          `json:"hash"`
+}
+
+// IDVector pairs a trace name and inputs with the expected trace ID.
+type IDVector struct {
+	Name   string          `json:"name"`
+	Inputs json.RawMessage `json:"inputs"`
+	ID     string          `json:"id"`
+}
+
+// TraceVector is a full serialized trace with its expected per-step
+// chain hashes and content hash.
+type TraceVector struct {
+	Trace       json.RawMessage `json:"trace"`
+	StepHashes  []string        `json:"step_hashes"`
+	ContentHash string          `json:"content_hash"`
+}
+
+// Load returns the embedded conformance vectors.
+func Load() (*Vectors, error) {
+	var v Vectors
+	if err := json.Unmarshal(vectorData, &v); err != nil {
+		return nil, fmt.Errorf("spec: parsing vectors: %w", err)
+	}
+	return &v, nil
+}
+
+// Check runs this implementation against every embedded vector and
+// returns the first divergence found.
+func Check() error {
+	v, err := Load()
+	if err != nil {
+		return err
+	}
+	for i, vec := range v.Values {
+		var value any
+		if err := json.Unmarshal(vec.Value, &value); err != nil {
+			return fmt.Errorf("spec: value vector %d: %w", i, err)
+		}
+		if got := string(trace.CanonicalBytes(value)); got != vec.Canonical {
+			return fmt.Errorf("spec: value vector %d (%s): canonical %q, want %q", i, vec.Value, got, vec.Canonical)
+		}
+		if got := trace.HashValue(value); got != vec.Hash {
+			return fmt.Errorf("spec: value vector %d (%s): hash %s, want %s", i, vec.Value, got, vec.Hash)
+		}
+	}
+	for i, vec := range v.IDs {
+		var inputs map[string]any
+		if err := json.Unmarshal(vec.Inputs, &inputs); err != nil {
+			return fmt.Errorf("spec: id vector %d: %w", i, err)
+		}
+		if got := trace.New(vec.Name, inputs).ID; got != vec.ID {
+			return fmt.Errorf("spec: id vector %d (%s): id %s, want %s", i, vec.Name, got, vec.ID)
+		}
+	}
+	tr, err := trace.FromJSON(v.Trace.Trace)
+	if err != nil {
+		return fmt.Errorf("spec: trace vector: %w", err)
+	}
+	if err := tr.Verify(); err != nil {
+		return fmt.Errorf("spec: trace vector: %w", err)
+	}
+	if len(tr.Steps) != len(v.Trace.StepHashes) {
+		return fmt.Errorf("spec: trace vector: %d steps, want %d", len(tr.Steps), len(v.Trace.StepHashes))
+	}
+	for i, want := range v.Trace.StepHashes {
+		if tr.Steps[i].Hash != want {
+			return fmt.Errorf("spec: trace vector: step %d hash %s, want %s", i, tr.Steps[i].Hash, want)
+		}
+	}
+	if got := tr.ContentHash(); got != v.Trace.ContentHash {
+		return fmt.Errorf("spec: trace vector: content hash %s, want %s", got, v.Trace.ContentHash)
+	}
+	return nil
+}
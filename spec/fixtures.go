@@ -0,0 +1,210 @@
+package spec
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Fixture is one golden serialized trace for downstream readers to
+// validate against: the file contents plus the identities and hashes
+// the reader is expected to reproduce.
+type Fixture struct {
+	// File is the fixture's file name under the fixtures directory.
+	File string `json:"file"`
+	// SHA256 is the full hex digest of the file bytes.
+	SHA256 string `json:"sha256"`
+	// TraceID and ContentHash are what the reader must recompute from
+	// the trace per SPEC.md.
+	TraceID     string `json:"trace_id"`
+	ContentHash string `json:"content_hash"`
+	Status      string `json:"status"`
+	// Features names what the fixture exercises, so a reader can report
+	// which parts of the format it covers.
+	Features []string `json:"features"`
+
+	data []byte
+}
+
+// Manifest indexes the golden fixtures. SignerPublicKey is the hex
+// ed25519 public key that verifies every step and trace signature in
+// the signed fixture.
+type Manifest struct {
+	Version         int       `json:"version"`
+	SignerPublicKey string    `json:"signer_public_key"`
+	Fixtures        []Fixture `json:"fixtures"`
+}
+
+// fixtureSeed keys the deterministic ed25519 signer, so regenerating
+// the fixtures reproduces the same signatures byte for byte.
+var fixtureSeed = []byte("euclidtrace-fixture-signer-00032")
+
+// Fixtures generates the golden traces in memory. Every volatile field
+// is pinned, so repeated generation is byte-identical.
+func Fixtures() (*Manifest, error) {
+	key := ed25519.NewKeyFromSeed(fixtureSeed)
+	m := &Manifest{
+		Version:         1,
+		SignerPublicKey: hex.EncodeToString(key.Public().(ed25519.PublicKey)),
+	}
+	builders := []struct {
+		file     string
+		features []string
+		build    func(ed25519.PrivateKey) (*trace.Trace, error)
+	}{
+		{"values.json", []string{"value-kinds", "nesting", "metadata"}, fixtureValues},
+		{"errors.json", []string{"failed-status", "step-errors", "severity", "assertions"}, fixtureErrors},
+		{"subtraces.json", []string{"subtraces", "refs", "origins"}, fixtureSubtraces},
+		{"signed.json", []string{"step-signatures", "trace-signature", "authors"}, fixtureSigned},
+	}
+	for _, b := range builders {
+		tr, err := b.build(key)
+		if err != nil {
+			return nil, fmt.Errorf("spec: fixture %s: %w", b.file, err)
+		}
+		pinFixtureTimes(tr)
+		if err := tr.Verify(); err != nil {
+			return nil, fmt.Errorf("spec: fixture %s: %w", b.file, err)
+		}
+		data, err := tr.ToJSON()
+		if err != nil {
+			return nil, fmt.Errorf("spec: fixture %s: %w", b.file, err)
+		}
+		sum := sha256.Sum256(data)
+		m.Fixtures = append(m.Fixtures, Fixture{
+			File:        b.file,
+			SHA256:      hex.EncodeToString(sum[:]),
+			TraceID:     tr.ID,
+			ContentHash: tr.ContentHash(),
+			Status:      string(tr.Status),
+			Features:    b.features,
+			data:        data,
+		})
+	}
+	return m, nil
+}
+
+// WriteFixtures regenerates the golden fixtures and their manifest
+// under dir — the API behind spec/fixtures in this repository.
+func WriteFixtures(dir string) error {
+	m, err := Fixtures()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for _, f := range m.Fixtures {
+		if err := os.WriteFile(filepath.Join(dir, f.File), f.data, 0o644); err != nil {
+			return err
+		}
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "manifest.json"), append(data, '\n'), 0o644)
+}
+
+// fixtureValues exercises every canonical value kind, deep nesting, and
+// unicode, in inputs, step values, and the result.
+func fixtureValues(ed25519.PrivateKey) (*trace.Trace, error) {
+	tr := trace.New("fixture.values", map[string]any{
+		"null":    nil,
+		"bool":    true,
+		"int":     42,
+		"float":   1.5,
+		"tiny":    1e-7,
+		"huge":    1e21,
+		"string":  "héllo|=:",
+		"list":    []any{1, "two", nil, true, []any{2.25}},
+		"map":     map[string]any{"b": "x", "a": 1, "nested": map[string]any{"k": []any{nil}}},
+		"empties": map[string]any{"list": []any{}, "map": map[string]any{}, "string": ""},
+	}, trace.WithMetadata("fixture", "values"))
+	tr.AddStep("identity", map[string]any{"in": math.Pi}, map[string]any{"out": math.Pi})
+	tr.AddStep("concat", map[string]any{"a": "héllo", "b": "wörld"}, map[string]any{"out": "héllowörld"},
+		trace.WithDescription("unicode strings hash by UTF-8 byte length"))
+	tr.Complete(map[string]any{"ok": true, "count": 2})
+	return tr, nil
+}
+
+// fixtureErrors exercises the failed terminal state, step errors,
+// severities, and embedded assertion steps.
+func fixtureErrors(ed25519.PrivateKey) (*trace.Trace, error) {
+	tr := trace.New("fixture.errors", map[string]any{"x": -1.0})
+	tr.AddStep("validate", map[string]any{"x": -1.0}, map[string]any{"ok": false},
+		trace.Warn(), trace.WithDescription("negative input tolerated with a warning"))
+	tr.Assert("x_nonnegative", false, "x is -1")
+	tr.AddStep("sqrt", map[string]any{"x": -1.0}, nil,
+		trace.WithRefs(0), trace.WithStepError(fmt.Errorf("sqrt of negative number")))
+	tr.Fail(fmt.Errorf("fixture.errors: sqrt of negative number"))
+	return tr, nil
+}
+
+// fixtureSubtraces exercises embedded sub-traces, data-flow refs, and
+// merged child origins.
+func fixtureSubtraces(ed25519.PrivateKey) (*trace.Trace, error) {
+	sub := trace.New("fixture.inner", map[string]any{"x": 3.0})
+	sub.AddStep("square", map[string]any{"x": 3.0}, map[string]any{"y": 9.0})
+	sub.Complete(9.0)
+	pinFixtureTimes(sub)
+
+	tr := trace.New("fixture.outer", map[string]any{"x": 3.0, "offset": 1.0})
+	tr.AddStep("load", map[string]any{"x": 3.0}, map[string]any{"loaded": 3.0})
+	tr.AddSubTrace("fixture.inner", sub, trace.WithRefs(0))
+	tr.AddStep("add", map[string]any{"y": 9.0, "offset": 1.0}, map[string]any{"sum": 10.0},
+		trace.WithRefs(1))
+	tr.Complete(10.0)
+	return tr, nil
+}
+
+// fixtureSigned exercises step authorship with ed25519 signatures; the
+// manifest carries a detached trace signature and the public key.
+func fixtureSigned(key ed25519.PrivateKey) (*trace.Trace, error) {
+	author := trace.Identity{Service: "pricing", Principal: "fixture-bot", KeyID: "fixture-1"}
+	tr := trace.New("fixture.signed", map[string]any{"amount": 100.0, "rate": 0.07})
+	st := tr.AddStep("tax", map[string]any{"amount": 100.0, "rate": 0.07}, map[string]any{"tax": 7.0},
+		trace.WithAuthor(author))
+	if err := trace.SignStep(st, key); err != nil {
+		return nil, err
+	}
+	tr.Complete(107.0)
+	return tr, nil
+}
+
+// pinFixtureTimes pins every timestamp to a fixed schedule and drops
+// machine-dependent metadata. Both sit outside all hashes, so pinning
+// after recording changes no identity; it only makes the serialized
+// bytes reproducible across machines and Go versions.
+func pinFixtureTimes(tr *trace.Trace) {
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tr.Started = base
+	tr.Finished = base.Add(time.Second)
+	for k := range tr.Metadata {
+		if strings.HasPrefix(k, "build.") || strings.HasPrefix(k, "env.") {
+			delete(tr.Metadata, k)
+		}
+	}
+	for i, st := range tr.Steps {
+		st.Time = base.Add(time.Duration(i+1) * time.Millisecond)
+		st.Duration = 0
+	}
+	subs := make([]string, 0, len(tr.SubTraces))
+	for hash := range tr.SubTraces {
+		subs = append(subs, hash)
+	}
+	sort.Strings(subs)
+	for _, hash := range subs {
+		pinFixtureTimes(tr.SubTraces[hash])
+	}
+}
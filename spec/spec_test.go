@@ -0,0 +1,88 @@
+package spec
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+func TestCheck(t *testing.T) {
+	if err := Check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVectorsAreWellFormed(t *testing.T) {
+	v, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Version < 1 || len(v.Values) == 0 || len(v.IDs) == 0 {
+		t.Fatalf("vectors %d/%d/%d", v.Version, len(v.Values), len(v.IDs))
+	}
+	if len(v.Trace.StepHashes) == 0 || v.Trace.ContentHash == "" {
+		t.Fatalf("trace vector %+v", v.Trace)
+	}
+}
+
+func TestFixturesAreDeterministic(t *testing.T) {
+	a, err := Fixtures()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Fixtures()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Fixtures) != len(b.Fixtures) || a.SignerPublicKey != b.SignerPublicKey {
+		t.Fatalf("manifests differ: %+v vs %+v", a, b)
+	}
+	for i := range a.Fixtures {
+		if a.Fixtures[i].SHA256 != b.Fixtures[i].SHA256 {
+			t.Fatalf("fixture %s not byte-identical across generations", a.Fixtures[i].File)
+		}
+	}
+}
+
+func TestCommittedFixturesMatchGenerator(t *testing.T) {
+	m, err := Fixtures()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range m.Fixtures {
+		data, err := os.ReadFile(filepath.Join("fixtures", f.File))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != f.SHA256 {
+			t.Fatalf("fixtures/%s is stale; regenerate with WriteFixtures", f.File)
+		}
+		tr, err := trace.FromJSON(data)
+		if err != nil {
+			t.Fatalf("fixtures/%s: %v", f.File, err)
+		}
+		if err := tr.Verify(); err != nil {
+			t.Fatalf("fixtures/%s: %v", f.File, err)
+		}
+		if tr.ContentHash() != f.ContentHash {
+			t.Fatalf("fixtures/%s content hash drifted", f.File)
+		}
+	}
+	committed, err := os.ReadFile(filepath.Join("fixtures", "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var onDisk Manifest
+	if err := json.Unmarshal(committed, &onDisk); err != nil {
+		t.Fatal(err)
+	}
+	if onDisk.SignerPublicKey != m.SignerPublicKey || len(onDisk.Fixtures) != len(m.Fixtures) {
+		t.Fatal("fixtures/manifest.json is stale; regenerate with WriteFixtures")
+	}
+}
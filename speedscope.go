@@ -0,0 +1,110 @@
+package trace
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// speedscope file-format structures (https://www.speedscope.app).
+type speedscopeFile struct {
+	Schema   string           `json:"$schema"`
+	Shared   speedscopeShared `json:"shared"`
+	Profiles []speedscopeProf `json:"profiles"`
+	Name     string           `json:"name"`
+	Exporter string           `json:"exporter"`
+}
+
+type speedscopeShared struct {
+	Frames []speedscopeFrame `json:"frames"`
+}
+
+type speedscopeFrame struct {
+	Name string `json:"name"`
+}
+
+type speedscopeProf struct {
+	Type       string            `json:"type"`
+	Name       string            `json:"name"`
+	Unit       string            `json:"unit"`
+	StartValue int64             `json:"startValue"`
+	EndValue   int64             `json:"endValue"`
+	Events     []speedscopeEvent `json:"events"`
+}
+
+type speedscopeEvent struct {
+	Type  string `json:"type"` // "O" open, "C" close
+	Frame int    `json:"frame"`
+	At    int64  `json:"at"`
+}
+
+// ToSpeedscope writes the trace as a speedscope profile: each step is a
+// frame whose width is its duration, and steps that reference a sub-trace
+// nest that sub-trace's steps beneath them, so deep traced computations
+// read as a flame graph. Frames are laid out sequentially by duration
+// rather than wall clock, which keeps nesting well-formed even when child
+// timings overlap.
+func (tr *Trace) ToSpeedscope(w io.Writer) error {
+	b := &speedscopeBuilder{frames: make(map[string]int)}
+	end := b.layoutSteps(tr, tr.Steps, 0)
+	file := speedscopeFile{
+		Schema: "https://www.speedscope.app/file-format-schema.json",
+		Shared: speedscopeShared{Frames: b.frameList},
+		Profiles: []speedscopeProf{{
+			Type:       "evented",
+			Name:       tr.Name,
+			Unit:       "nanoseconds",
+			StartValue: 0,
+			EndValue:   end,
+			Events:     b.events,
+		}},
+		Name:     tr.Name,
+		Exporter: "euclidtrace",
+	}
+	enc := json.NewEncoder(w)
+	return enc.Encode(file)
+}
+
+type speedscopeBuilder struct {
+	frames    map[string]int
+	frameList []speedscopeFrame
+	events    []speedscopeEvent
+}
+
+func (b *speedscopeBuilder) frame(name string) int {
+	if i, ok := b.frames[name]; ok {
+		return i
+	}
+	i := len(b.frameList)
+	b.frames[name] = i
+	b.frameList = append(b.frameList, speedscopeFrame{Name: name})
+	return i
+}
+
+// layoutSteps places each step after the previous one and returns the
+// cursor after the last; a step's width is its own duration or, if its
+// sub-trace runs longer, the nested total.
+func (b *speedscopeBuilder) layoutSteps(tr *Trace, steps []*Step, at int64) int64 {
+	for _, st := range steps {
+		frame := b.frame(st.Operation)
+		b.events = append(b.events, speedscopeEvent{Type: "O", Frame: frame, At: at})
+		end := at + stepWidth(st)
+		if st.SubTraceRef != "" {
+			if sub, ok := tr.SubTrace(st.SubTraceRef); ok {
+				if nested := b.layoutSteps(sub, sub.Steps, at); nested > end {
+					end = nested
+				}
+			}
+		}
+		b.events = append(b.events, speedscopeEvent{Type: "C", Frame: frame, At: end})
+		at = end
+	}
+	return at
+}
+
+func stepWidth(st *Step) int64 {
+	if st.Duration > 0 {
+		return int64(st.Duration)
+	}
+	return int64(time.Microsecond)
+}
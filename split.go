@@ -0,0 +1,95 @@
+package trace
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Chunk is one ordered piece of a split trace. Each chunk carries its own
+// SHA-256 so it can be verified independently while in transit.
+type Chunk struct {
+	TraceID string `json:"trace_id"`
+	Seq     int    `json:"seq"`
+	Total   int    `json:"total"`
+	Payload []byte `json:"payload"`
+	SHA256  string `json:"sha256"`
+}
+
+// Verify checks the chunk payload against its embedded checksum.
+func (c Chunk) Verify() error {
+	sum := sha256.Sum256(c.Payload)
+	if got := hex.EncodeToString(sum[:]); got != c.SHA256 {
+		return fmt.Errorf("trace: chunk %d/%d of %s corrupt: payload hash %s, want %s",
+			c.Seq+1, c.Total, c.TraceID, got, c.SHA256)
+	}
+	return nil
+}
+
+// Split serializes the trace and cuts it into ordered chunks of at most
+// maxBytes of payload each, so large traces can move through message buses
+// with payload size limits. Join reassembles them.
+func Split(tr *Trace, maxBytes int) ([]Chunk, error) {
+	if maxBytes <= 0 {
+		return nil, fmt.Errorf("trace: Split maxBytes must be positive, got %d", maxBytes)
+	}
+	data, err := tr.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+	total := (len(data) + maxBytes - 1) / maxBytes
+	chunks := make([]Chunk, 0, total)
+	for seq := 0; seq < total; seq++ {
+		lo := seq * maxBytes
+		hi := lo + maxBytes
+		if hi > len(data) {
+			hi = len(data)
+		}
+		payload := data[lo:hi]
+		sum := sha256.Sum256(payload)
+		chunks = append(chunks, Chunk{
+			TraceID: tr.ID,
+			Seq:     seq,
+			Total:   total,
+			Payload: payload,
+			SHA256:  hex.EncodeToString(sum[:]),
+		})
+	}
+	return chunks, nil
+}
+
+// Join verifies and reassembles chunks produced by Split. Chunks must all
+// belong to the same trace and form a complete sequence, but may arrive in
+// any order.
+func Join(chunks []Chunk) (*Trace, error) {
+	if len(chunks) == 0 {
+		return nil, fmt.Errorf("trace: Join requires at least one chunk")
+	}
+	total := chunks[0].Total
+	id := chunks[0].TraceID
+	if len(chunks) != total {
+		return nil, fmt.Errorf("trace: Join got %d chunks of %s, want %d", len(chunks), id, total)
+	}
+	ordered := make([]*Chunk, total)
+	for i := range chunks {
+		c := &chunks[i]
+		if c.TraceID != id {
+			return nil, fmt.Errorf("trace: Join mixed traces %s and %s", id, c.TraceID)
+		}
+		if c.Total != total {
+			return nil, fmt.Errorf("trace: chunk %d of %s disagrees on total (%d vs %d)", c.Seq, id, c.Total, total)
+		}
+		if c.Seq < 0 || c.Seq >= total || ordered[c.Seq] != nil {
+			return nil, fmt.Errorf("trace: duplicate or out-of-range chunk %d of %s", c.Seq, id)
+		}
+		if err := c.Verify(); err != nil {
+			return nil, err
+		}
+		ordered[c.Seq] = c
+	}
+	var data []byte
+	for _, c := range ordered {
+		data = append(data, c.Payload...)
+	}
+	return FromJSON(data)
+}
@@ -0,0 +1,56 @@
+package trace
+
+import "testing"
+
+func TestSplitJoinRoundTrip(t *testing.T) {
+	tr := New("big", map[string]any{"n": 10})
+	for i := 0; i < 10; i++ {
+		tr.AddStep("iter", map[string]any{"i": i}, nil)
+	}
+	tr.Complete(nil)
+
+	chunks, err := Split(tr, 256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) < 2 {
+		t.Fatalf("expected multiple chunks, got %d", len(chunks))
+	}
+	// Chunks may arrive in any order.
+	for i, j := 0, len(chunks)-1; i < j; i, j = i+1, j-1 {
+		chunks[i], chunks[j] = chunks[j], chunks[i]
+	}
+	back, err := Join(chunks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.ContentHash() != tr.ContentHash() {
+		t.Fatal("reassembled trace differs from the original")
+	}
+	if err := back.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestJoinChunksRejectsCorruptAndIncomplete(t *testing.T) {
+	tr := New("t", nil)
+	tr.Complete(nil)
+	chunks, err := Split(tr, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Join(chunks[:len(chunks)-1]); err == nil {
+		t.Fatal("joined an incomplete sequence")
+	}
+	chunks[0].Payload[0] ^= 0xff
+	if _, err := Join(chunks); err == nil {
+		t.Fatal("joined a corrupt chunk")
+	}
+}
+
+func TestSplitRejectsNonPositiveSize(t *testing.T) {
+	tr := New("t", nil)
+	if _, err := Split(tr, 0); err == nil {
+		t.Fatal("split with zero maxBytes")
+	}
+}
@@ -0,0 +1,184 @@
+// Package stats provides traced statistical summaries — the most commonly
+// audited outputs — where the intermediate sums and sorts are recorded as
+// structured steps. For small inputs every accumulation is its own step;
+// past a configurable detail limit the element-level steps compact into a
+// single summarizing step carrying the count and a hash of the data, so
+// wide aggregations stay auditable without drowning the trace.
+package stats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/euclidtrace/trace"
+)
+
+// defaultDetail is how many elements get individual accumulation steps
+// before compaction kicks in.
+const defaultDetail = 32
+
+// Option configures one stats call.
+type Option func(*config)
+
+type config struct {
+	detail int
+}
+
+// WithDetail sets how many elements are traced individually before the
+// element-level steps compact into a summary. Zero compacts always.
+func WithDetail(n int) Option {
+	return func(c *config) { c.detail = n }
+}
+
+func newConfig(opts []Option) config {
+	c := config{detail: defaultDetail}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// Sum records the accumulation of xs and returns the total.
+func Sum(tr *trace.Trace, xs []float64, opts ...Option) float64 {
+	return sum(tr, xs, newConfig(opts))
+}
+
+func sum(tr *trace.Trace, xs []float64, c config) float64 {
+	total := 0.0
+	if len(xs) <= c.detail {
+		for i, x := range xs {
+			next := total + x
+			tr.AddStep("stats.accumulate",
+				map[string]any{"index": i, "x": x, "running": total},
+				map[string]any{"running": next})
+			total = next
+		}
+	} else {
+		for _, x := range xs {
+			total += x
+		}
+		tr.AddStep("stats.accumulate",
+			map[string]any{"count": len(xs), "data_hash": trace.HashValue(xs)},
+			map[string]any{"sum": total},
+			trace.WithDescription(fmt.Sprintf("%d elements accumulated, compacted", len(xs))))
+	}
+	tr.AddStep("stats.sum",
+		map[string]any{"count": len(xs)},
+		map[string]any{"sum": total})
+	return total
+}
+
+// Mean records the sum and the division and returns the mean; an empty
+// input records an error step and returns NaN.
+func Mean(tr *trace.Trace, xs []float64, opts ...Option) float64 {
+	if len(xs) == 0 {
+		tr.AddStep("stats.mean", nil, nil,
+			trace.WithStepError(fmt.Errorf("stats: mean of no values")))
+		return math.NaN()
+	}
+	total := sum(tr, xs, newConfig(opts))
+	mean := total / float64(len(xs))
+	tr.AddStep("stats.mean",
+		map[string]any{"sum": total, "count": len(xs)},
+		map[string]any{"mean": mean})
+	return mean
+}
+
+// Median records the sort and the middle selection and returns the
+// median.
+func Median(tr *trace.Trace, xs []float64, opts ...Option) float64 {
+	if len(xs) == 0 {
+		tr.AddStep("stats.median", nil, nil,
+			trace.WithStepError(fmt.Errorf("stats: median of no values")))
+		return math.NaN()
+	}
+	sorted := recordSort(tr, xs, newConfig(opts))
+	n := len(sorted)
+	var median float64
+	if n%2 == 1 {
+		median = sorted[n/2]
+	} else {
+		median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+	tr.AddStep("stats.median",
+		map[string]any{"count": n},
+		map[string]any{"median": median})
+	return median
+}
+
+// Variance records the mean and the squared-deviation accumulation and
+// returns the sample variance (n-1 denominator).
+func Variance(tr *trace.Trace, xs []float64, opts ...Option) float64 {
+	if len(xs) < 2 {
+		tr.AddStep("stats.variance", nil, nil,
+			trace.WithStepError(fmt.Errorf("stats: sample variance needs at least 2 values, have %d", len(xs))))
+		return math.NaN()
+	}
+	c := newConfig(opts)
+	mean := Mean(tr, xs, opts...)
+	deviations := make([]float64, len(xs))
+	for i, x := range xs {
+		d := x - mean
+		deviations[i] = d * d
+	}
+	ss := sum(tr, deviations, c)
+	variance := ss / float64(len(xs)-1)
+	tr.AddStep("stats.variance",
+		map[string]any{"sum_squared_dev": ss, "count": len(xs)},
+		map[string]any{"variance": variance},
+		trace.WithDescription("sample variance, n-1 denominator"))
+	return variance
+}
+
+// StdDev records the variance and the square root and returns the sample
+// standard deviation.
+func StdDev(tr *trace.Trace, xs []float64, opts ...Option) float64 {
+	variance := Variance(tr, xs, opts...)
+	sd := math.Sqrt(variance)
+	tr.AddStep("stats.stddev",
+		map[string]any{"variance": variance},
+		map[string]any{"stddev": sd})
+	return sd
+}
+
+// Percentile records the sort and the rank selection and returns the
+// p-th percentile (0–100) by the nearest-rank method.
+func Percentile(tr *trace.Trace, xs []float64, p float64, opts ...Option) float64 {
+	if len(xs) == 0 || p < 0 || p > 100 {
+		tr.AddStep("stats.percentile",
+			map[string]any{"p": p, "count": len(xs)}, nil,
+			trace.WithStepError(fmt.Errorf("stats: percentile %g of %d values", p, len(xs))))
+		return math.NaN()
+	}
+	sorted := recordSort(tr, xs, newConfig(opts))
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	value := sorted[rank-1]
+	tr.AddStep("stats.percentile",
+		map[string]any{"p": p, "rank": rank, "count": len(sorted)},
+		map[string]any{"value": value},
+		trace.WithDescription("nearest-rank method"))
+	return value
+}
+
+// recordSort sorts a copy of xs, recording the full ordering for small
+// inputs and the endpoints plus a data hash for large ones.
+func recordSort(tr *trace.Trace, xs []float64, c config) []float64 {
+	sorted := make([]float64, len(xs))
+	copy(sorted, xs)
+	sort.Float64s(sorted)
+	if len(sorted) <= c.detail {
+		tr.AddStep("stats.sort",
+			map[string]any{"values": append([]float64(nil), xs...)},
+			map[string]any{"sorted": sorted})
+	} else {
+		tr.AddStep("stats.sort",
+			map[string]any{"count": len(xs), "data_hash": trace.HashValue(xs)},
+			map[string]any{"min": sorted[0], "max": sorted[len(sorted)-1]},
+			trace.WithDescription(fmt.Sprintf("%d elements sorted, compacted", len(xs))))
+	}
+	return sorted
+}
@@ -0,0 +1,91 @@
+package stats
+
+import (
+	"math"
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+func TestSumRecordsEveryAccumulation(t *testing.T) {
+	tr := trace.New("t", nil)
+	if total := Sum(tr, []float64{1, 2, 3}); total != 6 {
+		t.Fatalf("sum %g", total)
+	}
+	// Three accumulation steps plus the total.
+	if len(tr.Steps) != 4 || tr.Steps[3].Outputs["sum"] != 6.0 {
+		t.Fatalf("steps %+v", tr.Steps)
+	}
+	if tr.Steps[1].Inputs["running"] != 1.0 || tr.Steps[1].Outputs["running"] != 3.0 {
+		t.Fatalf("accumulation step %+v", tr.Steps[1])
+	}
+}
+
+func TestSumCompactsPastDetailLimit(t *testing.T) {
+	tr := trace.New("t", nil)
+	xs := []float64{1, 2, 3, 4, 5}
+	if total := Sum(tr, xs, WithDetail(2)); total != 15 {
+		t.Fatalf("sum %g", total)
+	}
+	if len(tr.Steps) != 2 {
+		t.Fatalf("steps %+v", tr.Steps)
+	}
+	compacted := tr.Steps[0]
+	if compacted.Inputs["count"] != 5 || compacted.Inputs["data_hash"] != trace.HashValue(xs) {
+		t.Fatalf("compacted step %+v", compacted)
+	}
+}
+
+func TestMeanMedianPercentile(t *testing.T) {
+	tr := trace.New("t", nil)
+	if m := Mean(tr, []float64{2, 4, 6}); m != 4 {
+		t.Fatalf("mean %g", m)
+	}
+	if m := Median(tr, []float64{5, 1, 3}); m != 3 {
+		t.Fatalf("odd median %g", m)
+	}
+	if m := Median(tr, []float64{4, 1, 3, 2}); m != 2.5 {
+		t.Fatalf("even median %g", m)
+	}
+	if p := Percentile(tr, []float64{1, 2, 3, 4}, 50); p != 2 {
+		t.Fatalf("p50 %g", p)
+	}
+	if p := Percentile(tr, []float64{1, 2, 3, 4}, 100); p != 4 {
+		t.Fatalf("p100 %g", p)
+	}
+	tr.Complete(nil)
+	if err := tr.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVarianceAndStdDev(t *testing.T) {
+	tr := trace.New("t", nil)
+	xs := []float64{2, 4, 4, 4, 5, 5, 7, 9}
+	v := Variance(tr, xs)
+	if math.Abs(v-4.571428571428571) > 1e-12 {
+		t.Fatalf("variance %g", v)
+	}
+	sd := StdDev(trace.New("t", nil), xs)
+	if math.Abs(sd-math.Sqrt(v)) > 1e-12 {
+		t.Fatalf("stddev %g", sd)
+	}
+}
+
+func TestDegenerateInputsRecordErrors(t *testing.T) {
+	tr := trace.New("t", nil)
+	if !math.IsNaN(Mean(tr, nil)) || !math.IsNaN(Median(tr, nil)) {
+		t.Fatal("empty input did not yield NaN")
+	}
+	if !math.IsNaN(Variance(tr, []float64{1})) {
+		t.Fatal("single-element variance did not yield NaN")
+	}
+	if !math.IsNaN(Percentile(tr, []float64{1}, 150)) {
+		t.Fatal("out-of-range percentile did not yield NaN")
+	}
+	for _, st := range tr.Steps {
+		if st.Err == "" {
+			t.Fatalf("degenerate input recorded no error: %+v", st)
+		}
+	}
+}
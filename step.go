@@ -0,0 +1,105 @@
+package trace
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Step is one recorded operation inside a Trace: the values it consumed, the
+// values it produced, and when it happened. Steps are hash-chained, so the
+// sequence cannot be reordered or edited without detection.
+type Step struct {
+	Index       int            `json:"index"`
+	Operation   string         `json:"operation"`
+	Description string         `json:"description,omitempty"`
+	Inputs      map[string]any `json:"inputs,omitempty"`
+	Outputs     map[string]any `json:"outputs,omitempty"`
+	// Refs lists the indexes of earlier steps whose outputs fed this step,
+	// forming the data-flow lineage of the trace.
+	Refs []int `json:"refs,omitempty"`
+	// Origin names the child recorder that produced the step, for steps
+	// merged from concurrent goroutines; empty for steps recorded directly
+	// on the trace.
+	Origin string `json:"origin,omitempty"`
+	Err    string `json:"error,omitempty"`
+	// Severity grades the step; empty means info. See WithSeverity.
+	Severity Severity `json:"severity,omitempty"`
+	// OpVersion is the catalog version of the operation in force when the
+	// step was recorded; see OpCatalog.
+	OpVersion string `json:"op_version,omitempty"`
+	// Repeat counts how many structurally identical consecutive steps were
+	// coalesced into this one; zero for ordinary steps. See Compact.
+	Repeat int `json:"repeat,omitempty"`
+	// SubTraceRef is the content hash of a sub-computation recorded by this
+	// step, resolvable through the trace's sub-trace table. See AddSubTrace.
+	SubTraceRef string `json:"subtrace,omitempty"`
+	// Notes are reviewer-addressed commentary, excluded from every hash
+	// and from machine consumers; see WithNote.
+	Notes []Note `json:"notes,omitempty"`
+	// Attempts records the individual tries behind a retried step; see
+	// Retry. Attempts are timing detail and do not enter the chain hash.
+	Attempts []Attempt `json:"attempts,omitempty"`
+	// Author identifies the component that recorded the step, with an
+	// optional signature binding it to the chain hash; see WithAuthor and
+	// SignStep.
+	Author    *Identity     `json:"author,omitempty"`
+	AuthorSig []byte        `json:"author_sig,omitempty"`
+	Time      time.Time     `json:"time"`
+	Duration  time.Duration `json:"duration,omitempty"`
+	Hash      string        `json:"hash"`
+}
+
+// StepOption configures a step as it is recorded.
+type StepOption func(*Step)
+
+// WithDescription attaches a human-readable description to the step.
+func WithDescription(d string) StepOption {
+	return func(st *Step) { st.Description = d }
+}
+
+// WithRefs records which earlier step indexes produced this step's inputs.
+func WithRefs(indexes ...int) StepOption {
+	return func(st *Step) { st.Refs = append(st.Refs, indexes...) }
+}
+
+// WithStepError marks the step as having produced an error.
+func WithStepError(err error) StepOption {
+	return func(st *Step) {
+		if err != nil {
+			st.Err = err.Error()
+		}
+	}
+}
+
+// WithDuration records how long the operation behind the step took.
+func WithDuration(d time.Duration) StepOption {
+	return func(st *Step) { st.Duration = d }
+}
+
+// chainHash computes the step's hash chained onto the previous step's hash.
+func (st *Step) chainHash(prev string) string {
+	h := sha256.New()
+	io.WriteString(h, prev)
+	fmt.Fprintf(h, "|%d|%s|%s|%s|%s|%d|%s", st.Index, st.Operation, st.Description, st.Err, st.Origin, st.Repeat, st.SubTraceRef)
+	for _, k := range sortedKeys(st.Inputs) {
+		fmt.Fprintf(h, "|in:%s=%s", k, HashValue(st.Inputs[k]))
+	}
+	for _, k := range sortedKeys(st.Outputs) {
+		fmt.Fprintf(h, "|out:%s=%s", k, HashValue(st.Outputs[k]))
+	}
+	for _, ref := range st.Refs {
+		fmt.Fprintf(h, "|ref:%d", ref)
+	}
+	// Included only when set, so severity-free steps keep their hashes
+	// from before severity existed.
+	if st.Severity != "" {
+		fmt.Fprintf(h, "|sev:%s", st.Severity)
+	}
+	if st.OpVersion != "" {
+		fmt.Fprintf(h, "|opv:%s", st.OpVersion)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,68 @@
+package store
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Amendments indexes amendment records by the trace they correct, so
+// supersession chains can be followed from any version to the current
+// one. At most one amendment may supersede a given trace; corrections to
+// a correction amend the superseding trace instead.
+type Amendments struct {
+	mu         sync.Mutex
+	byOriginal map[string]*trace.Amendment
+}
+
+// NewAmendments returns an empty amendment index.
+func NewAmendments() *Amendments {
+	return &Amendments{byOriginal: map[string]*trace.Amendment{}}
+}
+
+// Record adds an amendment; a second amendment for the same original is
+// rejected.
+func (a *Amendments) Record(am *trace.Amendment) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if prev, ok := a.byOriginal[am.OriginalID]; ok {
+		return fmt.Errorf("store: trace %s already superseded by %s", am.OriginalID, prev.SupersedingID)
+	}
+	a.byOriginal[am.OriginalID] = am
+	return nil
+}
+
+// Superseding returns the amendment that supersedes the given trace, if
+// any.
+func (a *Amendments) Superseding(traceID string) (*trace.Amendment, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	am, ok := a.byOriginal[traceID]
+	return am, ok
+}
+
+// Chain follows the supersession chain starting at traceID, returning
+// each version's trace from the store in order, oldest first. A cycle in
+// the recorded amendments is reported as an error.
+func (a *Amendments) Chain(s Store, traceID string) ([]*trace.Trace, error) {
+	var chain []*trace.Trace
+	seen := map[string]bool{}
+	id := traceID
+	for {
+		if seen[id] {
+			return nil, fmt.Errorf("store: supersession cycle at trace %s", id)
+		}
+		seen[id] = true
+		tr, err := s.Get(id)
+		if err != nil {
+			return nil, fmt.Errorf("store: supersession chain at trace %s: %w", id, err)
+		}
+		chain = append(chain, tr)
+		am, ok := a.Superseding(id)
+		if !ok {
+			return chain, nil
+		}
+		id = am.SupersedingID
+	}
+}
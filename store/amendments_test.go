@@ -0,0 +1,128 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+// version builds one completed version of the same calculation; the
+// input varies so each version gets its own deterministic ID.
+func version(n int) *trace.Trace {
+	tr := trace.New("calc", map[string]any{"x": n})
+	tr.AddStep("double", map[string]any{"x": n}, map[string]any{"y": 2 * n})
+	tr.Complete(2 * n)
+	return tr
+}
+
+// amend stores both traces and applies an amendment linking them.
+func amend(t *testing.T, s Store, a *Amendments, original, superseding *trace.Trace) *trace.Amendment {
+	t.Helper()
+	for _, tr := range []*trace.Trace{original, superseding} {
+		if err := s.Put(tr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	am, err := trace.NewAmendment(original, superseding, "corrected rate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Apply(s, am); err != nil {
+		t.Fatal(err)
+	}
+	return am
+}
+
+func TestApplyLinksBothVersions(t *testing.T) {
+	s := NewMemory()
+	a := NewAmendments()
+	v1, v2 := version(1), version(2)
+	am := amend(t, s, a, v1, v2)
+
+	if v1.SupersededBy != v2.ID || v2.Supersedes != v1.ID {
+		t.Fatalf("links %q %q", v1.SupersededBy, v2.Supersedes)
+	}
+	if got, ok := a.Superseding(v1.ID); !ok || got != am {
+		t.Fatalf("superseding %v %v", got, ok)
+	}
+	// A second correction must amend the superseding trace, not the
+	// original again.
+	if _, err := trace.NewAmendment(v1, version(3), "again"); err != nil {
+		t.Fatal(err)
+	} else if err := a.Record(am); err == nil {
+		t.Fatal("recorded a second amendment for the same original")
+	}
+}
+
+func TestChainAndLatestVersion(t *testing.T) {
+	s := NewMemory()
+	a := NewAmendments()
+	v1, v2, v3 := version(1), version(2), version(3)
+	amend(t, s, a, v1, v2)
+	amend(t, s, a, v2, v3)
+
+	chain, err := a.Chain(s, v1.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 3 || chain[0].ID != v1.ID || chain[2].ID != v3.ID {
+		t.Fatalf("chain %v", chain)
+	}
+	latest, err := LatestVersion(s, v1.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest.ID != v3.ID {
+		t.Fatalf("latest %s", latest.ID)
+	}
+	// The intermediate versions stay in the store.
+	if _, err := s.Get(v2.ID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApplyRejectsMismatchedAmendment(t *testing.T) {
+	s := NewMemory()
+	a := NewAmendments()
+	v1, v2 := version(1), version(2)
+	for _, tr := range []*trace.Trace{v1, v2} {
+		if err := s.Put(tr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	am, err := trace.NewAmendment(v1, v2, "corrected rate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	am.SupersedingHash = "tampered"
+	if err := a.Apply(s, am); err == nil {
+		t.Fatal("applied an amendment that does not match its traces")
+	}
+	if v1.SupersededBy != "" {
+		t.Fatal("links written despite the failed verification")
+	}
+}
+
+func TestAnnotationsCurrent(t *testing.T) {
+	anns := NewAnnotations()
+	tr := completed("annotated")
+	current, err := trace.Annotate(tr, "lee", trace.AnnotationComment, "looks right")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale, err := trace.Annotate(tr, "kim", trace.AnnotationComment, "old note")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.ContentHash = "superseded-version"
+	anns.Add(current)
+	anns.Add(stale)
+
+	if got := anns.For(tr.ID); len(got) != 2 {
+		t.Fatalf("for %v", got)
+	}
+	got := anns.Current(tr)
+	if len(got) != 1 || got[0] != current {
+		t.Fatalf("current %v", got)
+	}
+}
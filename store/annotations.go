@@ -0,0 +1,51 @@
+package store
+
+import (
+	"sync"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Annotations collects reviewer annotations keyed by trace ID, stored and
+// served alongside the traces they discuss without ever entering the
+// signed content.
+type Annotations struct {
+	mu      sync.Mutex
+	byTrace map[string][]*trace.Annotation
+}
+
+// NewAnnotations returns an empty annotation collection.
+func NewAnnotations() *Annotations {
+	return &Annotations{byTrace: map[string][]*trace.Annotation{}}
+}
+
+// Add appends an annotation to its trace's collection.
+func (a *Annotations) Add(an *trace.Annotation) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.byTrace[an.TraceID] = append(a.byTrace[an.TraceID], an)
+}
+
+// For returns the annotations recorded against a trace, in the order they
+// were added.
+func (a *Annotations) For(traceID string) []*trace.Annotation {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make([]*trace.Annotation, len(a.byTrace[traceID]))
+	copy(out, a.byTrace[traceID])
+	return out
+}
+
+// Current returns the annotations whose pinned content hash matches the
+// trace's current content — commentary written against this exact
+// version, as opposed to one later superseded.
+func (a *Annotations) Current(tr *trace.Trace) []*trace.Annotation {
+	hash := tr.ContentHash()
+	var out []*trace.Annotation
+	for _, an := range a.For(tr.ID) {
+		if an.ContentHash == hash {
+			out = append(out, an)
+		}
+	}
+	return out
+}
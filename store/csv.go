@@ -0,0 +1,25 @@
+package store
+
+import (
+	"encoding/csv"
+	"io"
+
+	"github.com/euclidtrace/trace"
+)
+
+// ExportCSV writes every trace in the store as flattened step rows under a
+// single header, so a whole archive opens as one spreadsheet.
+func ExportCSV(w io.Writer, s Store) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(trace.CSVHeader()); err != nil {
+		return err
+	}
+	err := s.Each(func(tr *trace.Trace) error {
+		return tr.WriteCSVRows(cw)
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,93 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Dir is a filesystem Store: each trace is one canonical JSON file named
+// <id>.json inside the directory. It suits archives synchronized to object
+// stores and inspection with ordinary tools.
+type Dir struct {
+	path string
+}
+
+// NewDir opens (creating if needed) a directory-backed store.
+func NewDir(path string) (*Dir, error) {
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return nil, err
+	}
+	return &Dir{path: path}, nil
+}
+
+// Put writes the trace as <id>.json, replacing any previous version
+// atomically via a rename.
+func (d *Dir) Put(tr *trace.Trace) error {
+	data, err := tr.ToJSON()
+	if err != nil {
+		return err
+	}
+	final := d.file(tr.ID)
+	tmp := final + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, final)
+}
+
+// Get reads the trace with the given ID.
+func (d *Dir) Get(id string) (*trace.Trace, error) {
+	data, err := os.ReadFile(d.file(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return trace.FromJSON(data)
+}
+
+// Delete removes the trace file with the given ID; deleting an absent
+// ID is a no-op.
+func (d *Dir) Delete(id string) error {
+	err := os.Remove(d.file(id))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Each visits every stored trace in ID order.
+func (d *Dir) Each(fn func(tr *trace.Trace) error) error {
+	entries, err := os.ReadDir(d.path)
+	if err != nil {
+		return err
+	}
+	var ids []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		ids = append(ids, strings.TrimSuffix(name, ".json"))
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		tr, err := d.Get(id)
+		if err != nil {
+			return err
+		}
+		if err := fn(tr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Dir) file(id string) string {
+	return filepath.Join(d.path, id+".json")
+}
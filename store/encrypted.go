@@ -0,0 +1,156 @@
+package store
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/euclidtrace/trace"
+)
+
+// EncryptedDir is a filesystem Store that seals each trace with its
+// tenant's AES-256-GCM key before it touches disk, so one deployment
+// can hold several customers' traces without any file being readable
+// with another tenant's key. Files are named <id>.trace.enc and carry a
+// plaintext tenant header — tenant IDs are routing, not secrets —
+// followed by the nonce and ciphertext.
+type EncryptedDir struct {
+	path     string
+	policies Policies
+}
+
+// encExt names sealed trace files.
+const encExt = ".trace.enc"
+
+// NewEncryptedDir opens (creating if needed) an encrypted
+// directory-backed store. Every tenant whose traces it will hold needs
+// a 32-byte key in its policy.
+func NewEncryptedDir(path string, p Policies) (*EncryptedDir, error) {
+	if err := os.MkdirAll(path, 0o700); err != nil {
+		return nil, err
+	}
+	return &EncryptedDir{path: path, policies: p}, nil
+}
+
+// Put seals the trace with its tenant's key and writes it atomically.
+// Untenanted traces are refused: without a tenant there is no key, and
+// silently storing plaintext next to sealed files would defeat the
+// point of this store.
+func (d *EncryptedDir) Put(tr *trace.Trace) error {
+	tenant := tr.Tenant()
+	if tenant == "" {
+		return fmt.Errorf("store: trace %s has no tenant; encrypted stores require one", tr.ID)
+	}
+	gcm, err := d.sealer(tenant)
+	if err != nil {
+		return err
+	}
+	data, err := tr.ToJSON()
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s\n", tenant)
+	buf.Write(nonce)
+	buf.Write(gcm.Seal(nil, nonce, data, []byte(tr.ID)))
+
+	final := d.file(tr.ID)
+	tmp := final + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, final)
+}
+
+// Get unseals and returns the trace with the given ID.
+func (d *EncryptedDir) Get(id string) (*trace.Trace, error) {
+	raw, err := os.ReadFile(d.file(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	nl := bytes.IndexByte(raw, '\n')
+	if nl < 0 {
+		return nil, fmt.Errorf("store: sealed trace %s: missing tenant header", id)
+	}
+	tenant := string(raw[:nl])
+	gcm, err := d.sealer(tenant)
+	if err != nil {
+		return nil, err
+	}
+	body := raw[nl+1:]
+	if len(body) < gcm.NonceSize() {
+		return nil, fmt.Errorf("store: sealed trace %s: truncated", id)
+	}
+	data, err := gcm.Open(nil, body[:gcm.NonceSize()], body[gcm.NonceSize():], []byte(id))
+	if err != nil {
+		return nil, fmt.Errorf("store: sealed trace %s: %w", id, err)
+	}
+	return trace.FromJSON(data)
+}
+
+// Delete removes the sealed trace file; deleting an absent ID is a
+// no-op.
+func (d *EncryptedDir) Delete(id string) error {
+	err := os.Remove(d.file(id))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Each visits every stored trace in ID order.
+func (d *EncryptedDir) Each(fn func(tr *trace.Trace) error) error {
+	entries, err := os.ReadDir(d.path)
+	if err != nil {
+		return err
+	}
+	var ids []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, encExt) {
+			continue
+		}
+		ids = append(ids, strings.TrimSuffix(name, encExt))
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		tr, err := d.Get(id)
+		if err != nil {
+			return err
+		}
+		if err := fn(tr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sealer returns the AEAD for a tenant's key.
+func (d *EncryptedDir) sealer(tenant string) (cipher.AEAD, error) {
+	policy, ok := d.policies[tenant]
+	if !ok || len(policy.Key) == 0 {
+		return nil, fmt.Errorf("store: no encryption key for tenant %q", tenant)
+	}
+	block, err := aes.NewCipher(policy.Key)
+	if err != nil {
+		return nil, fmt.Errorf("store: tenant %q key: %w", tenant, err)
+	}
+	return cipher.NewGCM(block)
+}
+
+func (d *EncryptedDir) file(id string) string {
+	return filepath.Join(d.path, id+encExt)
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+func encPolicies() Policies {
+	return Policies{
+		"acme":   {Key: bytes.Repeat([]byte{0x11}, 32)},
+		"globex": {Key: bytes.Repeat([]byte{0x22}, 32)},
+	}
+}
+
+func TestEncryptedDirRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewEncryptedDir(dir, encPolicies())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := completed("sealed")
+	tr.SetTenant("acme")
+	if err := s.Put(tr); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.Get(tr.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ContentHash() != tr.ContentHash() {
+		t.Fatal("round trip changed the trace content")
+	}
+
+	// Nothing but the tenant routing header is readable on disk.
+	raw, err := os.ReadFile(s.file(tr.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(raw, []byte("acme\n")) {
+		t.Fatal("missing tenant header")
+	}
+	if bytes.Contains(raw, []byte(tr.ID)) || bytes.Contains(raw, []byte("double")) {
+		t.Fatal("plaintext leaked into the sealed file")
+	}
+}
+
+func TestEncryptedDirWrongKey(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewEncryptedDir(dir, encPolicies())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := completed("sealed")
+	tr.SetTenant("acme")
+	if err := s.Put(tr); err != nil {
+		t.Fatal(err)
+	}
+	// Reopen with a different key for acme: the ciphertext must not open.
+	other, err := NewEncryptedDir(dir, Policies{"acme": {Key: bytes.Repeat([]byte{0x33}, 32)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Get(tr.ID); err == nil {
+		t.Fatal("opened with the wrong key")
+	}
+}
+
+func TestEncryptedDirRefusals(t *testing.T) {
+	s, err := NewEncryptedDir(t.TempDir(), encPolicies())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(completed("untenanted")); err == nil {
+		t.Fatal("stored an untenanted trace")
+	}
+	unknown := completed("unknown")
+	unknown.SetTenant("initech")
+	if err := s.Put(unknown); err == nil || !strings.Contains(err.Error(), "no encryption key") {
+		t.Fatalf("keyless tenant: %v", err)
+	}
+	if _, err := s.Get("missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("missing trace: %v", err)
+	}
+}
+
+func TestEncryptedDirEachAndDelete(t *testing.T) {
+	s, err := NewEncryptedDir(t.TempDir(), encPolicies())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := completed("a")
+	a.SetTenant("acme")
+	b := completed("b")
+	b.SetTenant("globex")
+	for _, tr := range []*trace.Trace{a, b} {
+		if err := s.Put(tr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	n := 0
+	err = s.Each(func(tr *trace.Trace) error {
+		n++
+		return nil
+	})
+	if err != nil || n != 2 {
+		t.Fatalf("visited %d traces, err %v", n, err)
+	}
+	if err := s.Delete(a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(a.ID); err != nil {
+		t.Fatalf("second delete: %v", err)
+	}
+	if _, err := s.Get(a.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("trace survived deletion: %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package store
+
+import (
+	"errors"
+
+	"github.com/euclidtrace/trace"
+)
+
+// errStop aborts an Each walk when the consumer stops ranging early.
+var errStop = errors.New("store: iteration stopped")
+
+// AllTraces returns an iterator over every trace in the store, yielding
+// (id, trace). Iteration stops early if the store errors mid-walk.
+func AllTraces(s Store) trace.Seq2[string, *trace.Trace] {
+	return func(yield func(string, *trace.Trace) bool) {
+		stop := false
+		_ = s.Each(func(tr *trace.Trace) error {
+			if stop {
+				return nil
+			}
+			if !yield(tr.ID, tr) {
+				stop = true
+				return errStop
+			}
+			return nil
+		})
+	}
+}
+
+// AllSteps returns an iterator over every step of every trace in the store,
+// yielding (trace, step), so analysis code can stream through an archive
+// without materializing it.
+func AllSteps(s Store) trace.Seq2[*trace.Trace, *trace.Step] {
+	return func(yield func(*trace.Trace, *trace.Step) bool) {
+		AllTraces(s)(func(_ string, tr *trace.Trace) bool {
+			cont := true
+			tr.AllSteps()(func(_ int, st *trace.Step) bool {
+				cont = yield(tr, st)
+				return cont
+			})
+			return cont
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/euclidtrace/trace"
+)
+
+// SyncPolicy selects when the journal fsyncs its file. The trade-off is
+// throughput against how many trailing records a power failure can lose:
+//
+//   - SyncEveryStep: fsync after every record. Slowest; loses nothing.
+//     The default, because some traces are legally significant.
+//   - SyncEveryN(n): fsync after every n records. Bounded loss of up to
+//     n-1 step records.
+//   - SyncOnSections: fsync when a record's origin differs from the
+//     previous one and on finalization. Loses at most the tail of the
+//     current section.
+//
+// Finalization records always fsync regardless of policy.
+type SyncPolicy func(*Journal)
+
+// SyncEveryStep fsyncs after every appended record.
+func SyncEveryStep() SyncPolicy {
+	return func(j *Journal) { j.syncEvery = 1 }
+}
+
+// SyncEveryN fsyncs after every n appended records.
+func SyncEveryN(n int) SyncPolicy {
+	return func(j *Journal) { j.syncEvery = n }
+}
+
+// SyncOnSections fsyncs when the step origin changes between records.
+func SyncOnSections() SyncPolicy {
+	return func(j *Journal) { j.syncSections = true }
+}
+
+// Journal is a write-ahead log of trace activity: one JSON record per
+// line, appended as steps are recorded, so a crash loses at most the
+// records permitted by the sync policy. Callers append explicitly:
+//
+//	j, _ := store.OpenJournal(path, store.SyncEveryN(16))
+//	st := tr.AddStep("multiply", in, out)
+//	j.Record(tr, st)
+//	...
+//	tr.Complete(result)
+//	j.Finalize(tr)
+//
+// ReplayJournal rebuilds the traces from the log after a crash.
+type Journal struct {
+	mu           sync.Mutex
+	f            *os.File
+	w            *bufio.Writer
+	syncEvery    int
+	syncSections bool
+	sinceSync    int
+	lastOrigin   map[string]string // trace ID → origin of last record
+	begun        map[string]bool
+}
+
+// journalRecord is one line of the log.
+type journalRecord struct {
+	Type    string          `json:"type"` // "begin", "step", "final"
+	TraceID string          `json:"trace_id"`
+	Step    *trace.Step     `json:"step,omitempty"`
+	Trace   json.RawMessage `json:"trace,omitempty"`
+}
+
+// OpenJournal opens (appending) or creates the journal at path. Without a
+// policy the journal fsyncs every record.
+func OpenJournal(path string, opts ...SyncPolicy) (*Journal, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("store: open journal: %w", err)
+	}
+	j := &Journal{
+		f:          f,
+		w:          bufio.NewWriter(f),
+		syncEvery:  1,
+		lastOrigin: map[string]string{},
+		begun:      map[string]bool{},
+	}
+	for _, opt := range opts {
+		opt(j)
+	}
+	return j, nil
+}
+
+// Record appends one step record, preceded by a begin record the first
+// time a trace appears, and fsyncs according to the policy.
+func (j *Journal) Record(tr *trace.Trace, st *trace.Step) error {
+	if st == nil { // AddStep on a terminal trace
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if !j.begun[tr.ID] {
+		header, err := beginHeader(tr)
+		if err != nil {
+			return fmt.Errorf("store: journal begin: %w", err)
+		}
+		if err := j.appendLocked(journalRecord{Type: "begin", TraceID: tr.ID, Trace: header}); err != nil {
+			return err
+		}
+		j.begun[tr.ID] = true
+	}
+	if err := j.appendLocked(journalRecord{Type: "step", TraceID: tr.ID, Step: st}); err != nil {
+		return err
+	}
+	return j.maybeSyncLocked(tr.ID, st.Origin)
+}
+
+// beginHeader renders the trace's identity — name, inputs, metadata —
+// without its steps. Steps arrive as their own records; including them
+// in the header would replay the first step twice.
+func beginHeader(tr *trace.Trace) (json.RawMessage, error) {
+	data, err := tr.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+	header, err := trace.FromJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	header.Steps = nil
+	return header.ToJSON()
+}
+
+// Finalize appends the finished trace's full canonical form and fsyncs
+// unconditionally.
+func (j *Journal) Finalize(tr *trace.Trace) error {
+	data, err := tr.ToJSON()
+	if err != nil {
+		return fmt.Errorf("store: journal finalize: %w", err)
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err := j.appendLocked(journalRecord{Type: "final", TraceID: tr.ID, Trace: data}); err != nil {
+		return err
+	}
+	return j.syncLocked()
+}
+
+// Close flushes, fsyncs, and closes the journal file.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err := j.syncLocked(); err != nil {
+		j.f.Close()
+		return err
+	}
+	return j.f.Close()
+}
+
+func (j *Journal) appendLocked(rec journalRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("store: journal append: %w", err)
+	}
+	if _, err := j.w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("store: journal append: %w", err)
+	}
+	j.sinceSync++
+	return nil
+}
+
+func (j *Journal) maybeSyncLocked(traceID, origin string) error {
+	sectionBreak := j.syncSections && j.lastOrigin[traceID] != origin
+	j.lastOrigin[traceID] = origin
+	if sectionBreak || (j.syncEvery > 0 && j.sinceSync >= j.syncEvery && !j.syncSections) {
+		return j.syncLocked()
+	}
+	return nil
+}
+
+func (j *Journal) syncLocked() error {
+	if err := j.w.Flush(); err != nil {
+		return fmt.Errorf("store: journal sync: %w", err)
+	}
+	if err := j.f.Sync(); err != nil {
+		return fmt.Errorf("store: journal sync: %w", err)
+	}
+	j.sinceSync = 0
+	return nil
+}
+
+// ReplayJournal rebuilds traces from the log: finalized traces come back
+// in their canonical form; traces without a final record are
+// reconstructed from their begin header and step records and remain in
+// the recording state, ready for Recover-style handling. Truncated
+// trailing lines — the normal result of a crash mid-write — are skipped.
+func ReplayJournal(path string) ([]*trace.Trace, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("store: replay journal: %w", err)
+	}
+	defer f.Close()
+
+	traces := map[string]*trace.Trace{}
+	var order []string
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		var rec journalRecord
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			continue // truncated trailing write
+		}
+		switch rec.Type {
+		case "begin":
+			tr, err := trace.FromJSON(rec.Trace)
+			if err != nil {
+				return nil, fmt.Errorf("store: replay journal: trace %s: %w", rec.TraceID, err)
+			}
+			if _, seen := traces[rec.TraceID]; !seen {
+				order = append(order, rec.TraceID)
+			}
+			traces[rec.TraceID] = tr
+		case "step":
+			if tr, ok := traces[rec.TraceID]; ok && rec.Step != nil {
+				tr.Steps = append(tr.Steps, rec.Step)
+			}
+		case "final":
+			tr, err := trace.FromJSON(rec.Trace)
+			if err != nil {
+				return nil, fmt.Errorf("store: replay journal: trace %s: %w", rec.TraceID, err)
+			}
+			if _, seen := traces[rec.TraceID]; !seen {
+				order = append(order, rec.TraceID)
+			}
+			traces[rec.TraceID] = tr
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("store: replay journal: %w", err)
+	}
+	out := make([]*trace.Trace, 0, len(traces))
+	for _, id := range order {
+		out = append(out, traces[id])
+	}
+	return out, nil
+}
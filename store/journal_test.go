@@ -0,0 +1,125 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+func TestJournalReplayFinalizedTrace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.log")
+	j, err := OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := trace.New("journaled", map[string]any{"x": 1})
+	if err := j.Record(tr, tr.AddStep("double", map[string]any{"x": 1}, map[string]any{"y": 2})); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Record(tr, tr.AddStep("round", map[string]any{"y": 2}, map[string]any{"z": 2})); err != nil {
+		t.Fatal(err)
+	}
+	tr.Complete(2)
+	if err := j.Finalize(tr); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	replayed, err := ReplayJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replayed) != 1 {
+		t.Fatalf("replayed %d traces", len(replayed))
+	}
+	got := replayed[0]
+	if got.ContentHash() != tr.ContentHash() || got.Status != trace.StatusCompleted {
+		t.Fatalf("replayed trace %s %s", got.Status, got.ID)
+	}
+	if err := got.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestJournalReplayAfterCrash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.log")
+	j, err := OpenJournal(path, SyncEveryN(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := trace.New("crashed", map[string]any{"x": 1})
+	j.Record(tr, tr.AddStep("double", map[string]any{"x": 1}, map[string]any{"y": 2}))
+	j.Record(tr, tr.AddStep("round", map[string]any{"y": 2}, map[string]any{"z": 2}))
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// Simulate a torn trailing write.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"type":"step","trace_id":"` + tr.ID + `","st`)
+	f.Close()
+
+	replayed, err := ReplayJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replayed) != 1 {
+		t.Fatalf("replayed %d traces", len(replayed))
+	}
+	got := replayed[0]
+	if got.Status != trace.StatusRecording || len(got.Steps) != 2 {
+		t.Fatalf("replayed trace %s with %d steps", got.Status, len(got.Steps))
+	}
+
+	// The recovered partial can be closed out through the store.
+	s := NewMemory()
+	if err := s.Put(got); err != nil {
+		t.Fatal(err)
+	}
+	partial, err := Recover(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(partial) != 1 || partial[0].ID != got.ID {
+		t.Fatalf("recovered %v", partial)
+	}
+	if err := FinalizeAborted(s, got, os.ErrDeadlineExceeded); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != trace.StatusAborted {
+		t.Fatalf("status %s", got.Status)
+	}
+	if partial, _ := Recover(s); len(partial) != 0 {
+		t.Fatalf("still recovering %v", partial)
+	}
+}
+
+func TestJournalSkipsNilStep(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.log")
+	j, err := OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := trace.New("done", nil)
+	tr.Complete(nil)
+	// AddStep on a terminal trace returns nil; the journal ignores it.
+	if err := j.Record(tr, tr.AddStep("late", nil, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := ReplayJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replayed) != 0 {
+		t.Fatalf("replayed %v", replayed)
+	}
+}
@@ -0,0 +1,58 @@
+package store
+
+import (
+	"fmt"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Apply records the amendment and writes the supersession links onto both
+// traces in the store, so consumers holding either trace can navigate the
+// correction chain without the amendment index at hand.
+func (a *Amendments) Apply(s Store, am *trace.Amendment) error {
+	original, err := s.Get(am.OriginalID)
+	if err != nil {
+		return fmt.Errorf("store: apply amendment: %w", err)
+	}
+	superseding, err := s.Get(am.SupersedingID)
+	if err != nil {
+		return fmt.Errorf("store: apply amendment: %w", err)
+	}
+	if err := am.Verify(original, superseding); err != nil {
+		return fmt.Errorf("store: apply amendment: %w", err)
+	}
+	if err := a.Record(am); err != nil {
+		return err
+	}
+	original.SupersededBy = am.SupersedingID
+	superseding.Supersedes = am.OriginalID
+	if err := s.Put(original); err != nil {
+		return fmt.Errorf("store: apply amendment: %w", err)
+	}
+	if err := s.Put(superseding); err != nil {
+		return fmt.Errorf("store: apply amendment: %w", err)
+	}
+	return nil
+}
+
+// LatestVersion follows SupersededBy links from the given trace to the
+// authoritative current version. The intermediate versions remain in the
+// store, so the full correction history stays auditable.
+func LatestVersion(s Store, traceID string) (*trace.Trace, error) {
+	seen := map[string]bool{}
+	id := traceID
+	for {
+		if seen[id] {
+			return nil, fmt.Errorf("store: supersession cycle at trace %s", id)
+		}
+		seen[id] = true
+		tr, err := s.Get(id)
+		if err != nil {
+			return nil, fmt.Errorf("store: latest version of trace %s: %w", traceID, err)
+		}
+		if tr.SupersededBy == "" {
+			return tr, nil
+		}
+		id = tr.SupersededBy
+	}
+}
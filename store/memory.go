@@ -0,0 +1,69 @@
+package store
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Memory is an in-process Store, useful for tests and for services that
+// export traces elsewhere and only need a short-lived working set.
+type Memory struct {
+	mu     sync.RWMutex
+	traces map[string]*trace.Trace
+}
+
+// NewMemory returns an empty in-memory store.
+func NewMemory() *Memory {
+	return &Memory{traces: map[string]*trace.Trace{}}
+}
+
+// Put stores the trace.
+func (m *Memory) Put(tr *trace.Trace) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.traces[tr.ID] = tr
+	return nil
+}
+
+// Get returns the stored trace with the given ID.
+func (m *Memory) Get(id string) (*trace.Trace, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	tr, ok := m.traces[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return tr, nil
+}
+
+// Delete removes the trace with the given ID; deleting an absent ID is
+// a no-op.
+func (m *Memory) Delete(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.traces, id)
+	return nil
+}
+
+// Each visits every stored trace in ID order.
+func (m *Memory) Each(fn func(tr *trace.Trace) error) error {
+	m.mu.RLock()
+	ids := make([]string, 0, len(m.traces))
+	for id := range m.traces {
+		ids = append(ids, id)
+	}
+	m.mu.RUnlock()
+	sort.Strings(ids)
+	for _, id := range ids {
+		tr, err := m.Get(id)
+		if err != nil {
+			continue // removed concurrently
+		}
+		if err := fn(tr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
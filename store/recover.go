@@ -0,0 +1,30 @@
+package store
+
+import (
+	"github.com/euclidtrace/trace"
+)
+
+// Recover returns the partial traces in the store — checkpoints that
+// never reached a terminal state, typically left behind by a crash. Each
+// can be resumed via trace.Resume-style continuation of the work, or
+// finalized with FinalizeAborted when the computation will not continue.
+func Recover(s Store) ([]*trace.Trace, error) {
+	var partial []*trace.Trace
+	err := s.Each(func(tr *trace.Trace) error {
+		if tr.Status == trace.StatusRecording {
+			partial = append(partial, tr)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return partial, nil
+}
+
+// FinalizeAborted marks a recovered partial trace aborted and stores the
+// result, closing out work that will not be resumed.
+func FinalizeAborted(s Store, tr *trace.Trace, reason error) error {
+	tr.Abort(reason)
+	return s.Put(tr)
+}
@@ -0,0 +1,24 @@
+// Package store persists and retrieves traces. Store is the minimal
+// interface the rest of the module builds on; Memory keeps traces
+// in-process and Dir lays them out as canonical JSON files in a directory.
+package store
+
+import (
+	"errors"
+
+	"github.com/euclidtrace/trace"
+)
+
+// ErrNotFound reports a trace ID the store does not hold.
+var ErrNotFound = errors.New("store: trace not found")
+
+// Store is a collection of traces keyed by trace ID.
+type Store interface {
+	// Put stores a trace, replacing any previous trace with the same ID.
+	Put(tr *trace.Trace) error
+	// Get returns the trace with the given ID, or ErrNotFound.
+	Get(id string) (*trace.Trace, error)
+	// Each visits every stored trace; iteration stops at the first error,
+	// which Each returns.
+	Each(fn func(tr *trace.Trace) error) error
+}
@@ -0,0 +1,133 @@
+package store
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+func completed(name string) *trace.Trace {
+	tr := trace.New(name, map[string]any{"x": 1})
+	tr.AddStep("double", map[string]any{"x": 1}, map[string]any{"y": 2})
+	tr.Complete(2)
+	return tr
+}
+
+func testCRUD(t *testing.T, s Store) {
+	t.Helper()
+	tr := completed("crud")
+	if err := s.Put(tr); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.Get(tr.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ContentHash() != tr.ContentHash() {
+		t.Fatal("round trip changed the trace content")
+	}
+	if _, err := s.Get("no-such-id"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("missing trace: %v", err)
+	}
+	d, ok := s.(Deleter)
+	if !ok {
+		t.Fatalf("%T does not delete", s)
+	}
+	if err := d.Delete(tr.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get(tr.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("trace survived deletion: %v", err)
+	}
+}
+
+func TestMemoryCRUD(t *testing.T) {
+	testCRUD(t, NewMemory())
+}
+
+func TestDirCRUD(t *testing.T) {
+	d, err := NewDir(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCRUD(t, d)
+}
+
+func TestDirEachVisitsInIDOrder(t *testing.T) {
+	d, err := NewDir(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// IDs are deterministic from name and inputs, so each trace needs its
+	// own name to be distinct.
+	for _, name := range []string{"each-a", "each-b", "each-c"} {
+		if err := d.Put(completed(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var got []string
+	err = d.Each(func(tr *trace.Trace) error {
+		got = append(got, tr.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("visited %d traces", len(got))
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i-1] >= got[i] {
+			t.Fatalf("ids out of order: %v", got)
+		}
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	s := NewMemory()
+	if err := s.Put(completed("csv")); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := ExportCSV(&buf, s); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 { // header + the single step
+		t.Fatalf("csv lines %q", lines)
+	}
+	if !strings.Contains(lines[1], "double") {
+		t.Fatalf("step row %q", lines[1])
+	}
+}
+
+func TestAllTracesAndAllSteps(t *testing.T) {
+	s := NewMemory()
+	for _, name := range []string{"iter-a", "iter-b"} {
+		if err := s.Put(completed(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	traces := 0
+	AllTraces(s)(func(id string, tr *trace.Trace) bool {
+		if id != tr.ID {
+			t.Fatalf("key %q for trace %q", id, tr.ID)
+		}
+		traces++
+		return true
+	})
+	if traces != 2 {
+		t.Fatalf("visited %d traces", traces)
+	}
+	steps := 0
+	AllSteps(s)(func(tr *trace.Trace, st *trace.Step) bool {
+		steps++
+		return steps < 2 // early stop
+	})
+	if steps != 2 {
+		t.Fatalf("visited %d steps", steps)
+	}
+}
@@ -0,0 +1,116 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Scoped confines a Store to one tenant. Every Put stamps or checks the
+// trace's tenant, Get hides other tenants' traces behind ErrNotFound,
+// and Each visits only the tenant's own — so handing a Scoped store to
+// tenant-facing code makes cross-tenant leakage a type-system problem
+// rather than a code-review one.
+type Scoped struct {
+	s      Store
+	tenant string
+}
+
+// ForTenant scopes a store to the given tenant.
+func ForTenant(s Store, tenant string) *Scoped {
+	return &Scoped{s: s, tenant: tenant}
+}
+
+// Put stores the trace under the scope's tenant. An untenanted trace is
+// stamped; a trace already assigned to a different tenant is refused.
+func (sc *Scoped) Put(tr *trace.Trace) error {
+	switch tr.Tenant() {
+	case "":
+		tr.SetTenant(sc.tenant)
+	case sc.tenant:
+	default:
+		return fmt.Errorf("store: trace %s belongs to tenant %q, store is scoped to %q", tr.ID, tr.Tenant(), sc.tenant)
+	}
+	return sc.s.Put(tr)
+}
+
+// Get returns the tenant's trace, or ErrNotFound — including for traces
+// that exist but belong to someone else, so existence does not leak
+// across tenants.
+func (sc *Scoped) Get(id string) (*trace.Trace, error) {
+	tr, err := sc.s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if tr.Tenant() != sc.tenant {
+		return nil, ErrNotFound
+	}
+	return tr, nil
+}
+
+// Each visits only the tenant's traces.
+func (sc *Scoped) Each(fn func(tr *trace.Trace) error) error {
+	return sc.s.Each(func(tr *trace.Trace) error {
+		if tr.Tenant() != sc.tenant {
+			return nil
+		}
+		return fn(tr)
+	})
+}
+
+// Deleter is implemented by stores that can remove traces; retention
+// enforcement requires it.
+type Deleter interface {
+	Delete(id string) error
+}
+
+// Policy is one tenant's storage rules: how long its traces are kept
+// and the key its traces are encrypted with at rest.
+type Policy struct {
+	// Retention bounds how long a trace is kept after it finished; zero
+	// means kept indefinitely.
+	Retention time.Duration
+	// Key is the tenant's AES-256 key for encrypted stores; see
+	// NewEncryptedDir.
+	Key []byte
+}
+
+// Policies maps tenant IDs to their storage rules.
+type Policies map[string]Policy
+
+// EnforceRetention removes every trace whose tenant's retention has
+// lapsed, measured from when the trace finished (or started, if it
+// never finished). Traces of tenants without a policy are kept. The
+// store must implement Deleter. It returns how many traces were
+// removed.
+func EnforceRetention(s Store, p Policies, now time.Time) (int, error) {
+	d, ok := s.(Deleter)
+	if !ok {
+		return 0, fmt.Errorf("store: %T cannot delete traces", s)
+	}
+	var expired []string
+	err := s.Each(func(tr *trace.Trace) error {
+		policy, ok := p[tr.Tenant()]
+		if !ok || policy.Retention == 0 {
+			return nil
+		}
+		age := tr.Finished
+		if age.IsZero() {
+			age = tr.Started
+		}
+		if now.Sub(age) > policy.Retention {
+			expired = append(expired, tr.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	for i, id := range expired {
+		if err := d.Delete(id); err != nil {
+			return i, err
+		}
+	}
+	return len(expired), nil
+}
@@ -0,0 +1,85 @@
+package store
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/euclidtrace/trace"
+)
+
+func TestScopedIsolatesTenants(t *testing.T) {
+	shared := NewMemory()
+	acme := ForTenant(shared, "acme")
+	globex := ForTenant(shared, "globex")
+
+	tr := completed("scoped")
+	if err := acme.Put(tr); err != nil {
+		t.Fatal(err)
+	}
+	if tr.Tenant() != "acme" {
+		t.Fatalf("tenant %q", tr.Tenant())
+	}
+	if _, err := acme.Get(tr.ID); err != nil {
+		t.Fatal(err)
+	}
+	// Another tenant's scope cannot even learn the trace exists.
+	if _, err := globex.Get(tr.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("cross-tenant get: %v", err)
+	}
+	if err := globex.Put(tr); err == nil {
+		t.Fatal("stored another tenant's trace")
+	}
+
+	other := completed("scoped-other")
+	if err := globex.Put(other); err != nil {
+		t.Fatal(err)
+	}
+	var seen []string
+	err := acme.Each(func(tr *trace.Trace) error {
+		seen = append(seen, tr.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != 1 || seen[0] != tr.ID {
+		t.Fatalf("acme sees %v", seen)
+	}
+}
+
+func TestEnforceRetention(t *testing.T) {
+	s := NewMemory()
+	old := completed("old")
+	old.SetTenant("acme")
+	old.Finished = time.Now().Add(-48 * time.Hour)
+	fresh := completed("fresh")
+	fresh.SetTenant("acme")
+	unmanaged := completed("unmanaged")
+	unmanaged.SetTenant("globex")
+	unmanaged.Finished = old.Finished
+	for _, tr := range []*trace.Trace{old, fresh, unmanaged} {
+		if err := s.Put(tr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	policies := Policies{"acme": {Retention: 24 * time.Hour}}
+	removed, err := EnforceRetention(s, policies, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 1 {
+		t.Fatalf("removed %d traces", removed)
+	}
+	if _, err := s.Get(old.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expired trace survived: %v", err)
+	}
+	if _, err := s.Get(fresh.ID); err != nil {
+		t.Fatal(err)
+	}
+	// Tenants without a policy keep everything.
+	if _, err := s.Get(unmanaged.ID); err != nil {
+		t.Fatal(err)
+	}
+}
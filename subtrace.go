@@ -0,0 +1,33 @@
+package trace
+
+// AddSubTrace records a completed sub-computation as a step of this trace.
+// The step references the sub-trace by content hash; the sub-trace itself is
+// stored once in the trace's sub-trace table, so a shared subexpression that
+// appears at many usage sites costs one copy in memory and in the serialized
+// form. The step's inputs and outputs mirror the sub-trace's inputs and
+// result, keeping the parent readable without expanding the reference.
+func (tr *Trace) AddSubTrace(operation string, sub *Trace, opts ...StepOption) *Step {
+	hash := sub.ContentHash()
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if tr.Status != StatusRecording {
+		return nil
+	}
+	if tr.SubTraces == nil {
+		tr.SubTraces = map[string]*Trace{}
+	}
+	if _, ok := tr.SubTraces[hash]; !ok {
+		tr.SubTraces[hash] = sub
+	}
+	opts = append(opts, func(st *Step) { st.SubTraceRef = hash })
+	return tr.addStepLocked(operation, sub.Inputs, map[string]any{"result": sub.Result}, opts...)
+}
+
+// SubTrace resolves a step's sub-trace reference against the trace's
+// sub-trace table.
+func (tr *Trace) SubTrace(contentHash string) (*Trace, bool) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	sub, ok := tr.SubTraces[contentHash]
+	return sub, ok
+}
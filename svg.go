@@ -0,0 +1,113 @@
+package trace
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SVG timeline layout constants.
+const (
+	svgWidth      = 960
+	svgLaneHeight = 26
+	svgBarHeight  = 18
+	svgLabelWidth = 140
+	svgHeaderY    = 30
+)
+
+// ToSVGTimeline renders the steps as bars on a time axis, one lane per
+// origin (the main trace plus each child recorder), so the performance
+// shape of a computation is visible at a glance. Bar positions come from
+// step timestamps and widths from durations; hovering a bar shows the
+// operation and exact timing.
+func (tr *Trace) ToSVGTimeline() string {
+	tr.mu.Lock()
+	steps := make([]*Step, len(tr.Steps))
+	copy(steps, tr.Steps)
+	tr.mu.Unlock()
+
+	lanes, order := timelineLanes(steps)
+	start, span := timelineSpan(steps)
+
+	height := svgHeaderY + len(order)*svgLaneHeight + 10
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="sans-serif" font-size="11">`+"\n", svgWidth, height)
+	fmt.Fprintf(&b, `  <text x="4" y="16" font-size="13">%s — %s</text>`+"\n", svgEscape(tr.Name), span)
+	for i, lane := range order {
+		y := svgHeaderY + i*svgLaneHeight
+		fmt.Fprintf(&b, `  <text x="4" y="%d">%s</text>`+"\n", y+svgBarHeight-4, svgEscape(lane))
+		for _, st := range lanes[lane] {
+			x, w := barGeometry(st, start, span)
+			fill := "#4a90d9"
+			if st.Err != "" {
+				fill = "#d94a4a"
+			}
+			fmt.Fprintf(&b, `  <rect x="%d" y="%d" width="%d" height="%d" fill="%s" rx="2">`, x, y, w, svgBarHeight, fill)
+			fmt.Fprintf(&b, `<title>%s (%s)</title></rect>`+"\n",
+				svgEscape(st.Operation), st.Duration)
+		}
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// timelineLanes groups steps by origin; the unnamed origin (steps recorded
+// directly on the trace) renders first as "main", children follow in name
+// order.
+func timelineLanes(steps []*Step) (map[string][]*Step, []string) {
+	lanes := make(map[string][]*Step)
+	for _, st := range steps {
+		lane := st.Origin
+		if lane == "" {
+			lane = "main"
+		}
+		lanes[lane] = append(lanes[lane], st)
+	}
+	order := make([]string, 0, len(lanes))
+	for lane := range lanes {
+		if lane != "main" {
+			order = append(order, lane)
+		}
+	}
+	sort.Strings(order)
+	if _, ok := lanes["main"]; ok {
+		order = append([]string{"main"}, order...)
+	}
+	return lanes, order
+}
+
+// timelineSpan returns the earliest step time and the total span covered by
+// the steps, never shorter than a microsecond so zero-duration traces still
+// render.
+func timelineSpan(steps []*Step) (time.Time, time.Duration) {
+	var start, end time.Time
+	for _, st := range steps {
+		if start.IsZero() || st.Time.Before(start) {
+			start = st.Time
+		}
+		if stop := st.Time.Add(st.Duration); stop.After(end) {
+			end = stop
+		}
+	}
+	span := end.Sub(start)
+	if span < time.Microsecond {
+		span = time.Microsecond
+	}
+	return start, span
+}
+
+func barGeometry(st *Step, start time.Time, span time.Duration) (x, w int) {
+	area := svgWidth - svgLabelWidth - 10
+	x = svgLabelWidth + int(int64(area)*int64(st.Time.Sub(start))/int64(span))
+	w = int(int64(area) * int64(st.Duration) / int64(span))
+	if w < 2 {
+		w = 2
+	}
+	return x, w
+}
+
+func svgEscape(s string) string {
+	r := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+	return r.Replace(s)
+}
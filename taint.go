@@ -0,0 +1,114 @@
+package trace
+
+import "sort"
+
+// WithInputTag tags a declared input with source labels (e.g. "source=user",
+// "source=market-feed"). Tags propagate forward through the data flow, so
+// every step — and the final result — can report which tagged sources
+// influenced it.
+func WithInputTag(input string, tags ...string) Option {
+	return func(tr *Trace) {
+		if tr.InputTags == nil {
+			tr.InputTags = map[string][]string{}
+		}
+		tr.InputTags[input] = append(tr.InputTags[input], tags...)
+	}
+}
+
+// Taints computes, for every step, the set of input tags that influenced it,
+// propagated through explicit Refs and value lineage. The result is keyed by
+// step index; steps influenced by no tagged input are absent.
+func (tr *Trace) Taints() map[int][]string {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	inputHashes := map[string]string{} // value hash -> input name
+	for name, v := range tr.Inputs {
+		inputHashes[HashValue(v)] = name
+	}
+
+	taints := make([]map[string]bool, len(tr.Steps))
+	outputTaints := map[string]map[string]bool{} // output value hash -> tags
+	for i, st := range tr.Steps {
+		set := map[string]bool{}
+		for k, v := range st.Inputs {
+			if tags, ok := tr.InputTags[k]; ok {
+				addTags(set, tags)
+			}
+			if name, ok := inputHashes[HashValue(v)]; ok {
+				addTags(set, tr.InputTags[name])
+			}
+			if carried, ok := outputTaints[HashValue(v)]; ok {
+				for t := range carried {
+					set[t] = true
+				}
+			}
+		}
+		for _, ref := range st.Refs {
+			if ref >= 0 && ref < i {
+				for t := range taints[ref] {
+					set[t] = true
+				}
+			}
+		}
+		taints[i] = set
+		for _, out := range st.Outputs {
+			hash := HashValue(out)
+			if outputTaints[hash] == nil {
+				outputTaints[hash] = map[string]bool{}
+			}
+			for t := range set {
+				outputTaints[hash][t] = true
+			}
+		}
+	}
+
+	result := map[int][]string{}
+	for i, set := range taints {
+		if len(set) > 0 {
+			result[i] = sortedTags(set)
+		}
+	}
+	return result
+}
+
+// StepTaints returns the tags that influenced one step.
+func (tr *Trace) StepTaints(index int) []string {
+	return tr.Taints()[index]
+}
+
+// ResultTaints returns the tags that influenced the final result: the taints
+// of the step that produced it, or — when no step's output matches the
+// result — the union across all steps.
+func (tr *Trace) ResultTaints() []string {
+	taints := tr.Taints()
+	tr.mu.Lock()
+	idx := tr.resultStepLocked()
+	tr.mu.Unlock()
+	if idx >= 0 {
+		return taints[idx]
+	}
+	set := map[string]bool{}
+	for _, tags := range taints {
+		addTags(set, tags)
+	}
+	if len(set) == 0 {
+		return nil
+	}
+	return sortedTags(set)
+}
+
+func addTags(set map[string]bool, tags []string) {
+	for _, t := range tags {
+		set[t] = true
+	}
+}
+
+func sortedTags(set map[string]bool) []string {
+	tags := make([]string, 0, len(set))
+	for t := range set {
+		tags = append(tags, t)
+	}
+	sort.Strings(tags)
+	return tags
+}
@@ -0,0 +1,57 @@
+package trace
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTaintsPropagateThroughValuesAndRefs(t *testing.T) {
+	tr := New("price", map[string]any{"quote": 101.5, "qty": 3},
+		WithInputTag("quote", "source=market-feed"),
+		WithInputTag("qty", "source=user"))
+	tr.AddStep("scale", map[string]any{"quote": 101.5}, map[string]any{"gross": 304.5})
+	tr.AddStep("round", map[string]any{"gross": 304.5}, map[string]any{"net": 304.0})
+	tr.AddStep("audit", nil, nil, WithRefs(1))
+	tr.AddStep("untainted", map[string]any{"k": 1}, nil)
+	tr.Complete(304.0)
+
+	taints := tr.Taints()
+	if !reflect.DeepEqual(taints[0], []string{"source=market-feed"}) {
+		t.Fatalf("step 0 taints %v", taints[0])
+	}
+	// Step 1 consumes step 0's output value; step 2 references step 1.
+	for _, i := range []int{1, 2} {
+		if !reflect.DeepEqual(taints[i], []string{"source=market-feed"}) {
+			t.Fatalf("step %d taints %v", i, taints[i])
+		}
+	}
+	if _, ok := taints[3]; ok {
+		t.Fatalf("untainted step reported taints %v", taints[3])
+	}
+	if got := tr.ResultTaints(); !reflect.DeepEqual(got, []string{"source=market-feed"}) {
+		t.Fatalf("ResultTaints %v", got)
+	}
+}
+
+func TestTaintsSurviveCompactAndNormalize(t *testing.T) {
+	tr := New("price", map[string]any{"quote": 101.5},
+		WithInputTag("quote", "source=market-feed"))
+	tr.AddStep("scale", map[string]any{"quote": 101.5}, map[string]any{"gross": 304.5})
+	tr.Complete(304.5)
+
+	for _, cp := range []*Trace{Compact(tr), Normalize(tr)} {
+		taints := cp.Taints()
+		if !reflect.DeepEqual(taints[0], []string{"source=market-feed"}) {
+			t.Fatalf("step taints %v", taints)
+		}
+		if got := cp.ResultTaints(); !reflect.DeepEqual(got, []string{"source=market-feed"}) {
+			t.Fatalf("ResultTaints %v", got)
+		}
+	}
+	// The copies own their tag slices; tagging one does not bleed back.
+	cp := Compact(tr)
+	cp.InputTags["quote"] = append(cp.InputTags["quote"], "source=backfill")
+	if len(tr.InputTags["quote"]) != 1 {
+		t.Fatalf("original tags mutated: %v", tr.InputTags["quote"])
+	}
+}
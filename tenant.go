@@ -0,0 +1,52 @@
+package trace
+
+// TenantMetadataKey carries the tenant a trace belongs to. Tenancy is
+// bookkeeping about ownership, not part of the computation, so it lives
+// in metadata outside every hash — the same trace recorded for two
+// tenants is the same mathematical fact, isolated at the storage and
+// collection layers; see store.ForTenant.
+const TenantMetadataKey = "tenant.id"
+
+// WithTenant assigns the new trace to a tenant.
+func WithTenant(id string) Option {
+	return func(tr *Trace) {
+		tr.Metadata[TenantMetadataKey] = id
+	}
+}
+
+// Tenant returns the tenant the trace belongs to, or "" for an
+// untenanted trace.
+func (tr *Trace) Tenant() string {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	return tr.Metadata[TenantMetadataKey]
+}
+
+// SetTenant assigns the trace to a tenant. Unlike SetMetadata it also
+// works on terminal traces — tenancy is storage bookkeeping outside
+// every hash, and stores stamp traces at Put time, after they have
+// finished. Scoped stores use it; see store.ForTenant.
+func (tr *Trace) SetTenant(id string) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.Metadata[TenantMetadataKey] = id
+}
+
+// TenantCollector is a view of a Collector that begins every trace
+// under one tenant. The memory budget stays shared across the
+// deployment; only the stamping is per-tenant, so a request handler can
+// be handed a view and cannot start a trace under the wrong customer.
+type TenantCollector struct {
+	c      *Collector
+	tenant string
+}
+
+// ForTenant returns a view of the collector scoped to the given tenant.
+func (c *Collector) ForTenant(tenant string) *TenantCollector {
+	return &TenantCollector{c: c, tenant: tenant}
+}
+
+// New begins a trace under the view's tenant and the shared budget.
+func (tc *TenantCollector) New(name string, inputs map[string]any, opts ...Option) *Trace {
+	return tc.c.New(name, inputs, append([]Option{WithTenant(tc.tenant)}, opts...)...)
+}
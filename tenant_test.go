@@ -0,0 +1,37 @@
+package trace
+
+import "testing"
+
+func TestTenantStampingStaysOutsideHashes(t *testing.T) {
+	plain := New("job", map[string]any{"x": 1})
+	tenanted := New("job", map[string]any{"x": 1}, WithTenant("acme"))
+	if tenanted.Tenant() != "acme" {
+		t.Fatalf("Tenant() = %q", tenanted.Tenant())
+	}
+	if plain.ID != tenanted.ID {
+		t.Fatal("tenancy changed the trace identity")
+	}
+	plain.Complete(1)
+	tenanted.Complete(1)
+	if plain.ContentHash() != tenanted.ContentHash() {
+		t.Fatal("tenancy changed the content hash")
+	}
+}
+
+func TestSetTenantWorksOnTerminalTraces(t *testing.T) {
+	tr := New("job", nil)
+	tr.Complete(nil)
+	tr.SetTenant("acme") // stores stamp at Put time, after completion
+	if tr.Tenant() != "acme" {
+		t.Fatalf("Tenant() = %q after SetTenant on a terminal trace", tr.Tenant())
+	}
+}
+
+func TestTenantCollectorStampsEveryTrace(t *testing.T) {
+	c := NewCollector(1 << 20)
+	tr := c.ForTenant("acme").New("job", nil)
+	if tr.Tenant() != "acme" {
+		t.Fatalf("Tenant() = %q", tr.Tenant())
+	}
+	tr.Complete(nil)
+}
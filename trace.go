@@ -0,0 +1,285 @@
+// Package trace records computations as ordered, hash-chained steps so that a
+// numeric result can be explained, compared, and re-verified long after it was
+// produced. A Trace is a mathematical fact about how a value came to be, not a
+// debugging artifact: identical inputs to the same named computation yield the
+// same trace identity.
+package trace
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	rtrace "runtime/trace"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Status describes the lifecycle state of a Trace.
+type Status string
+
+const (
+	// StatusRecording is the initial state: steps may still be added.
+	StatusRecording Status = "recording"
+	// StatusCompleted marks a trace whose computation finished with a result.
+	StatusCompleted Status = "completed"
+	// StatusFailed marks a trace whose computation ended in an error.
+	StatusFailed Status = "failed"
+)
+
+// Trace is an ordered record of a single computation: its named inputs, the
+// steps that transformed them, and the final result. A Trace is safe for
+// concurrent use while recording; once completed or failed it is immutable.
+type Trace struct {
+	ID       string            `json:"id"`
+	Name     string            `json:"name"`
+	Inputs   map[string]any    `json:"inputs"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Steps    []*Step           `json:"steps"`
+	Result   any               `json:"result,omitempty"`
+	Status   Status            `json:"status"`
+	Error    string            `json:"error,omitempty"`
+	Started  time.Time         `json:"started"`
+	Finished time.Time         `json:"finished,omitempty"`
+	// Attachments are named binary payloads carried outside the canonical
+	// form; see Attach.
+	Attachments map[string][]byte `json:"-"`
+	// SubTraces stores embedded sub-computations once, keyed by content
+	// hash; steps reference them via SubTraceRef. See AddSubTrace.
+	SubTraces map[string]*Trace `json:"subtraces,omitempty"`
+	// InputTags labels inputs with their sources for taint propagation; see
+	// WithInputTag.
+	InputTags map[string][]string `json:"input_tags,omitempty"`
+	// Supersedes and SupersededBy link correction chains: Supersedes names
+	// the trace ID this trace corrects, SupersededBy the trace that
+	// corrects this one. Both sit outside the content hash — supersession
+	// is bookkeeping about a trace, not part of the computation it records.
+	// See Amendment and store.LatestVersion.
+	Supersedes   string `json:"supersedes,omitempty"`
+	SupersededBy string `json:"superseded_by,omitempty"`
+	// FinalHash seals the step chain at the moment the trace reached a
+	// terminal state: it records the last step's chain hash, so Verify can
+	// detect trailing steps truncated after the fact — a mutation an
+	// intact prefix chain alone cannot reveal. Empty while recording and
+	// on traces recorded before the field existed.
+	FinalHash string `json:"final_hash,omitempty"`
+
+	mu       sync.Mutex
+	children []*Child
+
+	// Execution-tracer bridge; see WithRuntimeTrace.
+	rtCtx  context.Context
+	rtTask *rtrace.Task
+
+	// Float reproducibility tracking; see WithFloatReproducibility.
+	fpTrack     bool
+	fpTolerance float64
+
+	// Per-operation pre/postconditions; see WithContracts.
+	contracts *Contracts
+
+	// Versioned operation semantics; see OpCatalog.
+	catalog *OpCatalog
+
+	// Process-level budget accounting; see Collector.
+	collector *Collector
+	memCost   int64
+
+	// Terminal-state callbacks; see OnFinish.
+	onFinish []func(tr *Trace)
+
+	// Step-limit configuration and overflow bookkeeping; see WithStepLimit.
+	limit     int
+	limitMode LimitMode
+	limitTail int
+	overflow  limitState
+}
+
+// Option configures a Trace at creation time.
+type Option func(*Trace)
+
+// WithMetadata attaches a metadata key/value pair to the new trace.
+func WithMetadata(key, value string) Option {
+	return func(tr *Trace) {
+		tr.Metadata[key] = value
+	}
+}
+
+// New begins recording a computation. The trace ID is derived
+// deterministically from the name and inputs, so re-running the same
+// computation on the same inputs produces the same identity.
+func New(name string, inputs map[string]any, opts ...Option) *Trace {
+	if inputs == nil {
+		inputs = map[string]any{}
+	}
+	tr := newTrace()
+	tr.ID = generateID(name, inputs)
+	tr.Name = name
+	tr.Inputs = inputs
+	tr.Metadata = map[string]string{}
+	tr.Status = StatusRecording
+	tr.Started = time.Now()
+	for k, v := range BuildInfoMetadata() {
+		tr.Metadata[k] = v
+	}
+	for _, opt := range opts {
+		opt(tr)
+	}
+	tr.applySchemaLocked()
+	return tr
+}
+
+// AddStep appends one step to the trace and returns it. Inputs and outputs
+// are named values; operation identifies what was done with them. AddStep is
+// a no-op returning nil once the trace has completed or failed.
+func (tr *Trace) AddStep(operation string, inputs, outputs map[string]any, opts ...StepOption) *Step {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if tr.Status != StatusRecording {
+		return nil
+	}
+	if !tr.admitStepLocked(operation, inputs, outputs, opts) {
+		return nil
+	}
+	if !tr.admitCollectorLocked() {
+		return nil
+	}
+	st := tr.addStepLocked(operation, inputs, outputs, opts...)
+	tr.checkCatalogLocked(st)
+	tr.checkContractsLocked(st)
+	return st
+}
+
+func (tr *Trace) addStepLocked(operation string, inputs, outputs map[string]any, opts ...StepOption) *Step {
+	st := newStep()
+	st.Index = len(tr.Steps)
+	st.Operation = operation
+	st.Inputs = inputs
+	st.Outputs = outputs
+	st.Time = time.Now()
+	for _, opt := range opts {
+		opt(st)
+	}
+	tr.stampOpVersionLocked(st)
+	prev := ""
+	if n := len(tr.Steps); n > 0 {
+		prev = tr.Steps[n-1].Hash
+	}
+	st.Hash = st.chainHash(prev)
+	tr.Steps = append(tr.Steps, st)
+	tr.runtimeLogLocked(st)
+	tr.accumulateFloatLocked(st)
+	tr.accountStepLocked(st)
+	return st
+}
+
+// sealChainLocked captures the hash at the end of the step chain as the
+// trace reaches a terminal state. The seal is recorded after any
+// overflow or abort bookkeeping steps, so it always names the true last
+// step; its value is already covered by ContentHash through the step
+// hashes it duplicates.
+func (tr *Trace) sealChainLocked() {
+	if n := len(tr.Steps); n > 0 {
+		tr.FinalHash = tr.Steps[n-1].Hash
+	}
+}
+
+// Complete finishes the trace with its final result. Further steps are
+// rejected after completion.
+func (tr *Trace) Complete(result any) {
+	tr.mu.Lock()
+	defer tr.runFinishHooks()
+	defer tr.mu.Unlock()
+	if tr.Status != StatusRecording {
+		return
+	}
+	tr.finalizeLimitLocked()
+	tr.sealChainLocked()
+	tr.Result = result
+	tr.Status = StatusCompleted
+	tr.Finished = time.Now()
+	tr.finalizeFloatLocked()
+	tr.endRuntimeTaskLocked()
+	tr.releaseCollectorLocked()
+}
+
+// Fail finishes the trace in the failed state, recording the error.
+func (tr *Trace) Fail(err error) {
+	tr.mu.Lock()
+	defer tr.runFinishHooks()
+	defer tr.mu.Unlock()
+	if tr.Status != StatusRecording {
+		return
+	}
+	tr.finalizeLimitLocked()
+	tr.sealChainLocked()
+	if err != nil {
+		tr.Error = err.Error()
+	}
+	tr.Status = StatusFailed
+	tr.Finished = time.Now()
+	tr.finalizeFloatLocked()
+	tr.endRuntimeTaskLocked()
+	tr.releaseCollectorLocked()
+}
+
+// SetMetadata records a metadata key/value pair on a still-recording trace.
+func (tr *Trace) SetMetadata(key, value string) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if tr.Status != StatusRecording {
+		return
+	}
+	tr.Metadata[key] = value
+}
+
+// ContentHash returns a hash covering the trace identity, every step's chain
+// hash, and the outcome. Two traces with equal content hashes describe the
+// same computation and result.
+func (tr *Trace) ContentHash() string {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	h := sha256.New()
+	fmt.Fprintf(h, "%s|%s|%s", tr.ID, tr.Name, tr.Status)
+	for _, k := range sortedKeys(tr.Inputs) {
+		fmt.Fprintf(h, "|%s=%s", k, HashValue(tr.Inputs[k]))
+	}
+	for _, st := range tr.Steps {
+		io.WriteString(h, "|"+st.Hash)
+	}
+	fmt.Fprintf(h, "|result=%s|err=%s", HashValue(tr.Result), tr.Error)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// HashValue returns a short stable hash of a single value, used for value
+// identity in step records and trace IDs. Values are hashed through the
+// typed canonical encoding documented in canonical.go, so the hash is
+// insensitive to Go formatting details and survives a JSON round trip.
+func HashValue(v any) string {
+	h := sha256.New()
+	canonicalEncode(h, v)
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// generateID derives a deterministic trace ID from the computation name and
+// its canonically encoded inputs.
+func generateID(name string, inputs map[string]any) string {
+	h := sha256.New()
+	io.WriteString(h, name)
+	for _, k := range sortedKeys(inputs) {
+		fmt.Fprintf(h, "|%s=", k)
+		canonicalEncode(h, inputs[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
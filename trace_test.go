@@ -0,0 +1,197 @@
+package trace
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewDerivesDeterministicID(t *testing.T) {
+	a := New("calc.tax", map[string]any{"income": 50000})
+	b := New("calc.tax", map[string]any{"income": 50000})
+	if a.ID == "" || a.ID != b.ID {
+		t.Fatalf("same name and inputs gave IDs %q and %q", a.ID, b.ID)
+	}
+	c := New("calc.tax", map[string]any{"income": 50001})
+	if c.ID == a.ID {
+		t.Fatalf("different inputs gave the same ID %q", c.ID)
+	}
+	d := New("calc.other", map[string]any{"income": 50000})
+	if d.ID == a.ID {
+		t.Fatalf("different names gave the same ID %q", d.ID)
+	}
+}
+
+func TestAddStepChainsHashes(t *testing.T) {
+	tr := New("t", nil)
+	s0 := tr.AddStep("a", map[string]any{"x": 1}, map[string]any{"y": 2})
+	s1 := tr.AddStep("b", map[string]any{"y": 2}, map[string]any{"z": 3})
+	if s0.Index != 0 || s1.Index != 1 {
+		t.Fatalf("step indexes %d, %d", s0.Index, s1.Index)
+	}
+	if s0.Hash == "" || s1.Hash == "" || s0.Hash == s1.Hash {
+		t.Fatalf("step hashes %q, %q", s0.Hash, s1.Hash)
+	}
+	if got := s1.chainHash(s0.Hash); got != s1.Hash {
+		t.Fatalf("step 1 hash %s does not chain onto step 0 (recomputed %s)", s1.Hash, got)
+	}
+}
+
+func TestCompleteRejectsFurtherSteps(t *testing.T) {
+	tr := New("t", nil)
+	tr.AddStep("a", nil, nil)
+	tr.Complete(42)
+	if tr.Status != StatusCompleted {
+		t.Fatalf("status %s after Complete", tr.Status)
+	}
+	if tr.Finished.IsZero() {
+		t.Fatal("Finished not stamped")
+	}
+	if st := tr.AddStep("late", nil, nil); st != nil {
+		t.Fatalf("AddStep after Complete returned %+v", st)
+	}
+	if len(tr.Steps) != 1 {
+		t.Fatalf("%d steps after post-completion AddStep", len(tr.Steps))
+	}
+	tr.Fail(errors.New("late")) // second terminal transition is a no-op
+	if tr.Status != StatusCompleted || tr.Error != "" {
+		t.Fatalf("Fail after Complete changed the trace: %s %q", tr.Status, tr.Error)
+	}
+}
+
+func TestFailRecordsError(t *testing.T) {
+	tr := New("t", nil)
+	tr.Fail(errors.New("boom"))
+	if tr.Status != StatusFailed || tr.Error != "boom" {
+		t.Fatalf("status %s error %q", tr.Status, tr.Error)
+	}
+}
+
+func TestAbortRecordsTerminalStep(t *testing.T) {
+	tr := New("t", nil)
+	tr.AddStep("a", nil, nil)
+	tr.Abort(errors.New("deadline"))
+	if tr.Status != StatusAborted || tr.Error != "deadline" {
+		t.Fatalf("status %s error %q", tr.Status, tr.Error)
+	}
+	last := tr.Steps[len(tr.Steps)-1]
+	if last.Operation != "trace.abort" {
+		t.Fatalf("last step %q, want trace.abort", last.Operation)
+	}
+	if last.Inputs["reason"] != "deadline" {
+		t.Fatalf("abort step inputs %v", last.Inputs)
+	}
+	if err := tr.Verify(); err != nil {
+		t.Fatalf("aborted trace does not verify: %v", err)
+	}
+}
+
+func TestContentHashCoversOutcome(t *testing.T) {
+	build := func(result any) *Trace {
+		tr := New("t", map[string]any{"x": 1})
+		tr.AddStep("a", map[string]any{"x": 1}, map[string]any{"y": 2})
+		tr.Complete(result)
+		return tr
+	}
+	a, b := build(2), build(2)
+	if a.ContentHash() != b.ContentHash() {
+		t.Fatal("identical traces disagree on content hash")
+	}
+	if c := build(3); c.ContentHash() == a.ContentHash() {
+		t.Fatal("different results share a content hash")
+	}
+}
+
+func TestJSONRoundTripPreservesHashes(t *testing.T) {
+	tr := New("t", map[string]any{"x": 1.5, "s": "in"})
+	tr.AddStep("a", map[string]any{"x": 1.5}, map[string]any{"y": 3.0}, WithDescription("double"))
+	tr.AddStep("b", map[string]any{"y": 3.0}, nil, WithRefs(0), WithStepError(errors.New("partial")))
+	tr.Complete(3.0)
+	data, err := tr.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := FromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := got.Verify(); err != nil {
+		t.Fatalf("round-tripped trace does not verify: %v", err)
+	}
+	if got.ContentHash() != tr.ContentHash() {
+		t.Fatal("content hash changed across JSON round trip")
+	}
+	if got.FinalHash != tr.FinalHash {
+		t.Fatal("terminal seal changed across JSON round trip")
+	}
+}
+
+func TestMetadataNamespaces(t *testing.T) {
+	tr := New("t", nil, WithMetadata("user.team", "risk"))
+	if err := tr.SetUserMetadata("owner", "ops"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.SetNamespacedMetadata("env", "host", "x"); err == nil {
+		t.Fatal("system namespace accepted")
+	}
+	if err := tr.SetNamespacedMetadata("user", "", "x"); err == nil {
+		t.Fatal("empty key accepted")
+	}
+	if !IsSystemMetadata("tenant.id") || IsSystemMetadata("user.team") {
+		t.Fatal("IsSystemMetadata misclassifies")
+	}
+	got := tr.SelectMetadata(NamespaceUser)
+	if got["user.team"] != "risk" || got["user.owner"] != "ops" {
+		t.Fatalf("SelectMetadata: %v", got)
+	}
+	tr.Complete(nil)
+	tr.SetMetadata("user.late", "x")
+	if _, ok := tr.Metadata["user.late"]; ok {
+		t.Fatal("SetMetadata wrote to a terminal trace")
+	}
+}
+
+func TestAssertRecordsOutcome(t *testing.T) {
+	tr := New("t", nil)
+	tr.Assert("non-negative", true, "")
+	st := tr.Assert("bounded", false, "got 12, want <= 10")
+	if st.Err == "" || !strings.Contains(st.Err, "bounded") {
+		t.Fatalf("failed assertion error %q", st.Err)
+	}
+	failed := tr.FailedAssertions()
+	if len(failed) != 1 || failed[0].Inputs["name"] != "bounded" {
+		t.Fatalf("FailedAssertions: %+v", failed)
+	}
+}
+
+func TestWarningsCollectSeverity(t *testing.T) {
+	tr := New("t", nil)
+	tr.AddStep("ok", nil, nil)
+	tr.AddStep("clamped", nil, nil, Warn())
+	ws := tr.Warnings()
+	if len(ws) != 1 || ws[0].Operation != "clamped" {
+		t.Fatalf("Warnings: %+v", ws)
+	}
+	// Severity is hashed: erasing it must break the chain.
+	tr.Complete(nil)
+	data, _ := tr.ToJSON()
+	cp, _ := FromJSON(data)
+	cp.Steps[1].Severity = ""
+	if err := cp.Verify(); err == nil {
+		t.Fatal("severity erased without Verify noticing")
+	}
+}
+
+func TestAttachmentsStayOutsideCanonicalForm(t *testing.T) {
+	tr := New("t", nil)
+	tr.Attach("report.txt", []byte("hello"))
+	if got, ok := tr.Attachment("report.txt"); !ok || string(got) != "hello" {
+		t.Fatalf("Attachment: %q %v", got, ok)
+	}
+	tr.Complete(nil)
+	data, _ := tr.ToJSON()
+	cp, _ := FromJSON(data)
+	if _, ok := cp.Attachment("report.txt"); ok {
+		t.Fatal("attachment crossed the canonical JSON form")
+	}
+}
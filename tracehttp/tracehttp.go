@@ -0,0 +1,298 @@
+// Package tracehttp serves stored traces over HTTP with pluggable
+// authorization. Every request passes through an Authorizer before a
+// byte of trace content leaves the process, and what leaves is shaped
+// by the trace package's View projections — so one deployment can hand
+// auditors the full record and support staff a redacted one from the
+// same store. The Authorizer hook is transport-agnostic: it sees who is
+// asking, which trace, and through which view, and other serving layers
+// can reuse the same implementations.
+package tracehttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/euclidtrace/trace"
+	"github.com/euclidtrace/trace/store"
+)
+
+// Request describes one attempt to read a trace, in terms an
+// authorization policy can rule on without parsing the transport
+// itself.
+type Request struct {
+	// TraceID and TraceName identify the trace being read.
+	TraceID   string
+	TraceName string
+	// Tenant is the trace's tenant, or "" for an untenanted trace.
+	Tenant string
+	// View is the projection the caller asked for, or "" for the full
+	// trace.
+	View string
+	// HTTP is the underlying request, for policies that key off headers
+	// or TLS state.
+	HTTP *http.Request
+}
+
+// Decision is an Authorizer's answer. A denied request is refused
+// before any trace content is serialized. View, when non-empty, forces
+// the named projection even if the caller asked for the full trace —
+// how a policy grants access to the shape of a computation without its
+// values.
+type Decision struct {
+	Allow bool
+	View  string
+}
+
+// Authorizer rules on read requests.
+type Authorizer interface {
+	Authorize(r Request) Decision
+}
+
+// AuthorizerFunc adapts a function to the Authorizer interface.
+type AuthorizerFunc func(r Request) Decision
+
+// Authorize calls the function.
+func (f AuthorizerFunc) Authorize(r Request) Decision { return f(r) }
+
+// Handler serves traces from a Store under an authorization policy.
+// It answers GET /traces with a listing and GET /traces/<id> with one
+// trace, optionally projected via ?view=<name>.
+type Handler struct {
+	s     store.Store
+	auth  Authorizer
+	views map[string]trace.View
+}
+
+// Option configures a Handler.
+type Option func(*Handler)
+
+// WithAuthorizer sets the authorization policy. Without one the handler
+// serves every trace in full — acceptable only behind other access
+// control, such as a private debug port.
+func WithAuthorizer(a Authorizer) Option {
+	return func(h *Handler) { h.auth = a }
+}
+
+// WithView registers a projection clients may request by v.Name and
+// authorizers may force by the same name.
+func WithView(v trace.View) Option {
+	return func(h *Handler) { h.views[v.Name] = v }
+}
+
+// NewHandler returns a handler serving the store's traces.
+func NewHandler(s store.Store, opts ...Option) *Handler {
+	h := &Handler{s: s, views: map[string]trace.View{}}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// ServeHTTP routes GET /traces and GET /traces/<id>.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "tracehttp: method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	rest, ok := strings.CutPrefix(r.URL.Path, "/traces")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	rest = strings.Trim(rest, "/")
+	if rest == "" {
+		h.serveList(w, r)
+		return
+	}
+	h.serveTrace(w, r, rest)
+}
+
+// validTraceID reports whether id has the lowercase-hex shape every
+// generated trace ID (and content hash) has. Checking before the store
+// lookup keeps path metacharacters out of filesystem-backed stores,
+// which join the ID into a file path.
+func validTraceID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, c := range id {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// serveTrace authorizes and returns one trace as JSON.
+func (h *Handler) serveTrace(w http.ResponseWriter, r *http.Request, id string) {
+	if !validTraceID(id) {
+		http.NotFound(w, r)
+		return
+	}
+	tr, err := h.s.Get(id)
+	if errors.Is(err, store.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, "tracehttp: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	req := h.request(r, tr)
+	dec := h.authorize(req)
+	if !dec.Allow {
+		http.Error(w, "tracehttp: access denied", http.StatusForbidden)
+		return
+	}
+	viewName := req.View
+	if dec.View != "" {
+		viewName = dec.View
+	}
+	if viewName != "" {
+		v, ok := h.views[viewName]
+		if !ok {
+			status := http.StatusBadRequest
+			if dec.View != "" {
+				// The policy named it, not the caller: a deployment bug.
+				status = http.StatusInternalServerError
+			}
+			http.Error(w, fmt.Sprintf("tracehttp: unknown view %q", viewName), status)
+			return
+		}
+		tr = tr.Project(v)
+		if tr == nil {
+			http.Error(w, "tracehttp: projection failed", http.StatusInternalServerError)
+			return
+		}
+	}
+	data, err := tr.ToJSON()
+	if err != nil {
+		http.Error(w, "tracehttp: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// listEntry is one row of the listing: identity and outcome only, never
+// values, so listing is safe to grant more broadly than reading.
+type listEntry struct {
+	ID     string       `json:"id"`
+	Name   string       `json:"name"`
+	Status trace.Status `json:"status"`
+}
+
+// serveList returns the traces the caller may read, skipping — not
+// erroring on — the ones it may not, so a listing never reveals the
+// existence of traces outside the caller's grants.
+func (h *Handler) serveList(w http.ResponseWriter, r *http.Request) {
+	entries := []listEntry{}
+	err := h.s.Each(func(tr *trace.Trace) error {
+		if h.authorize(h.request(r, tr)).Allow {
+			entries = append(entries, listEntry{ID: tr.ID, Name: tr.Name, Status: tr.Status})
+		}
+		return nil
+	})
+	if err != nil {
+		http.Error(w, "tracehttp: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+func (h *Handler) request(r *http.Request, tr *trace.Trace) Request {
+	return Request{
+		TraceID:   tr.ID,
+		TraceName: tr.Name,
+		Tenant:    tr.Tenant(),
+		View:      r.URL.Query().Get("view"),
+		HTTP:      r,
+	}
+}
+
+func (h *Handler) authorize(req Request) Decision {
+	if h.auth == nil {
+		return Decision{Allow: true}
+	}
+	return h.auth.Authorize(req)
+}
+
+// Permit is one grant: which trace names it covers and what it exposes
+// of them.
+type Permit struct {
+	// Names are path.Match patterns against the trace name; an empty
+	// list covers every trace.
+	Names []string
+	// Full grants the unprojected trace; Views lists the projection
+	// names granted instead. A permit with neither grants nothing.
+	Full  bool
+	Views []string
+}
+
+// matches reports whether the permit covers the trace name.
+func (p Permit) matches(name string) bool {
+	if len(p.Names) == 0 {
+		return true
+	}
+	for _, pattern := range p.Names {
+		if ok, err := path.Match(pattern, name); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleAuthorizer grants per-trace-name, per-view permissions by role —
+// the policy behind "auditors see everything, support staff see the
+// customer projection". Requests for a view are checked against the
+// role's grants; requests for the full trace either get it (a Full
+// permit) or are steered to the first view the role is granted, so
+// redacted audiences need no special client configuration.
+type RoleAuthorizer struct {
+	// RoleFor extracts the caller's role from the request — typically a
+	// header or mTLS identity set by the deployment's auth frontend.
+	RoleFor func(r *http.Request) string
+	// Grants maps roles to their permits. A role with no entry is
+	// denied everything.
+	Grants map[string][]Permit
+}
+
+// Authorize implements Authorizer.
+func (ra *RoleAuthorizer) Authorize(req Request) Decision {
+	role := ""
+	if ra.RoleFor != nil && req.HTTP != nil {
+		role = ra.RoleFor(req.HTTP)
+	}
+	var full bool
+	var views []string
+	for _, p := range ra.Grants[role] {
+		if !p.matches(req.TraceName) {
+			continue
+		}
+		full = full || p.Full
+		views = append(views, p.Views...)
+	}
+	if req.View != "" {
+		if full {
+			return Decision{Allow: true}
+		}
+		for _, v := range views {
+			if v == req.View {
+				return Decision{Allow: true}
+			}
+		}
+		return Decision{}
+	}
+	if full {
+		return Decision{Allow: true}
+	}
+	if len(views) > 0 {
+		return Decision{Allow: true, View: views[0]}
+	}
+	return Decision{}
+}
@@ -0,0 +1,176 @@
+package tracehttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/euclidtrace/trace"
+	"github.com/euclidtrace/trace/store"
+)
+
+func served(t *testing.T) (*store.Memory, *trace.Trace) {
+	t.Helper()
+	s := store.NewMemory()
+	tr := trace.New("pricing", map[string]any{"gross": 100.0})
+	tr.AddStep("tax", map[string]any{"gross": 100.0}, map[string]any{"tax": 20.0})
+	tr.Complete(80.0)
+	if err := s.Put(tr); err != nil {
+		t.Fatal(err)
+	}
+	return s, tr
+}
+
+func get(t *testing.T, h http.Handler, target, role string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	if role != "" {
+		r.Header.Set("X-Role", role)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func roleHandler(s store.Store) *Handler {
+	auth := &RoleAuthorizer{
+		RoleFor: func(r *http.Request) string { return r.Header.Get("X-Role") },
+		Grants: map[string][]Permit{
+			"auditor": {{Full: true}},
+			"support": {{Names: []string{"pricing"}, Views: []string{"customer"}}},
+		},
+	}
+	return NewHandler(s,
+		WithAuthorizer(auth),
+		WithView(trace.ViewCustomer))
+}
+
+func TestServeTraceByRole(t *testing.T) {
+	s, tr := served(t)
+	h := roleHandler(s)
+
+	w := get(t, h, "/traces/"+tr.ID, "auditor")
+	if w.Code != http.StatusOK {
+		t.Fatalf("auditor got %d: %s", w.Code, w.Body)
+	}
+	full, err := trace.FromJSON(w.Body.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if full.Steps[0].Outputs["tax"] != 20.0 {
+		t.Fatalf("auditor body %+v", full.Steps[0])
+	}
+
+	// Support asked for the full trace and is steered to its granted
+	// projection: step values are gone, structure remains.
+	w = get(t, h, "/traces/"+tr.ID, "support")
+	if w.Code != http.StatusOK {
+		t.Fatalf("support got %d: %s", w.Code, w.Body)
+	}
+	redacted, err := trace.FromJSON(w.Body.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(redacted.Steps) != 1 || redacted.Steps[0].Operation != "tax" {
+		t.Fatalf("support body %+v", redacted.Steps)
+	}
+	// The customer view keeps trace inputs and the result but no
+	// per-step values.
+	if len(redacted.Steps[0].Outputs) != 0 || redacted.Result != 80.0 {
+		t.Fatalf("support projection: %+v result %v", redacted.Steps[0], redacted.Result)
+	}
+	if redacted.Metadata["projection.view"] != "customer" {
+		t.Fatalf("metadata %v", redacted.Metadata)
+	}
+
+	// No role, no access.
+	if w := get(t, h, "/traces/"+tr.ID, ""); w.Code != http.StatusForbidden {
+		t.Fatalf("anonymous got %d", w.Code)
+	}
+}
+
+func TestListingSkipsUnreadableTraces(t *testing.T) {
+	s, tr := served(t)
+	other := trace.New("billing", nil)
+	other.Complete(nil)
+	if err := s.Put(other); err != nil {
+		t.Fatal(err)
+	}
+	h := roleHandler(s)
+
+	w := get(t, h, "/traces", "support")
+	if w.Code != http.StatusOK {
+		t.Fatalf("listing got %d", w.Code)
+	}
+	var entries []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &entries); err != nil {
+		t.Fatal(err)
+	}
+	// Support's permit covers pricing only; billing is absent, not
+	// forbidden.
+	if len(entries) != 1 || entries[0].ID != tr.ID || entries[0].Status != "completed" {
+		t.Fatalf("entries %+v", entries)
+	}
+	if w := get(t, h, "/traces", "auditor"); w.Code != http.StatusOK {
+		t.Fatalf("auditor listing got %d", w.Code)
+	}
+}
+
+func TestRequestedViewAuthorization(t *testing.T) {
+	s, tr := served(t)
+	h := roleHandler(s)
+	if w := get(t, h, "/traces/"+tr.ID+"?view=customer", "support"); w.Code != http.StatusOK {
+		t.Fatalf("granted view got %d", w.Code)
+	}
+	// A view outside the role's grants is denied outright.
+	if w := get(t, h, "/traces/"+tr.ID+"?view=internal", "support"); w.Code != http.StatusForbidden {
+		t.Fatalf("ungranted view got %d", w.Code)
+	}
+	// An authorized caller asking for an unregistered view is a client
+	// error, not a leak of the full trace.
+	if w := get(t, h, "/traces/"+tr.ID+"?view=nonesuch", "auditor"); w.Code != http.StatusBadRequest {
+		t.Fatalf("unknown view got %d", w.Code)
+	}
+}
+
+func TestTraceIDValidation(t *testing.T) {
+	s, tr := served(t)
+	h := NewHandler(s)
+	// Traversal-shaped and otherwise malformed IDs 404 before any store
+	// lookup; store.Memory would not be harmed, but Dir-backed stores
+	// join the ID into a file path.
+	for _, id := range []string{
+		"../../etc/passwd",
+		"..%2f..%2fetc%2fpasswd",
+		"ABCDEF123456",
+		tr.ID + tr.ID + tr.ID + tr.ID + tr.ID,
+	} {
+		w := get(t, h, "/traces/"+url.PathEscape(id), "")
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("id %q got %d", id, w.Code)
+		}
+	}
+	if w := get(t, h, "/traces/"+tr.ID, ""); w.Code != http.StatusOK {
+		t.Fatalf("open handler got %d", w.Code)
+	}
+}
+
+func TestMethodAndPathRouting(t *testing.T) {
+	s, _ := served(t)
+	h := NewHandler(s)
+	r := httptest.NewRequest(http.MethodPost, "/traces", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("POST got %d", w.Code)
+	}
+	if w := get(t, h, "/other", ""); w.Code != http.StatusNotFound {
+		t.Fatalf("foreign path got %d", w.Code)
+	}
+}
@@ -0,0 +1,245 @@
+// Package tracesql wraps a database/sql driver so that every query executed
+// during a traced computation is recorded as a step: the normalized SQL text,
+// per-parameter hashes, row counts, and duration. This puts data-fetch
+// provenance into the trace alongside the arithmetic that consumed it.
+//
+// Register the wrapped driver once and use it as usual; queries only record
+// steps when the context carries a trace (see trace.NewContext):
+//
+//	sql.Register("traced-pg", tracesql.WrapDriver(pq.Driver{}))
+//	db, _ := sql.Open("traced-pg", dsn)
+//	rows, _ := db.QueryContext(trace.NewContext(ctx, tr), q, args...)
+package tracesql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/euclidtrace/trace"
+)
+
+// WrapDriver returns a driver that records queries run under a traced context
+// as steps on that trace. Drivers that do not implement the Context variants
+// of the driver interfaces fall back to their prepared-statement path, which
+// is wrapped as well.
+func WrapDriver(d driver.Driver) driver.Driver {
+	return wrappedDriver{d}
+}
+
+type wrappedDriver struct {
+	d driver.Driver
+}
+
+func (w wrappedDriver) Open(name string) (driver.Conn, error) {
+	conn, err := w.d.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedConn{conn: conn}, nil
+}
+
+type wrappedConn struct {
+	conn driver.Conn
+}
+
+func (c *wrappedConn) Prepare(query string) (driver.Stmt, error) {
+	stmt, err := c.conn.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedStmt{stmt: stmt, query: query}, nil
+}
+
+func (c *wrappedConn) Close() error { return c.conn.Close() }
+
+func (c *wrappedConn) Begin() (driver.Tx, error) { return c.conn.Begin() } //nolint:staticcheck // driver.Conn still requires Begin
+
+func (c *wrappedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if ct, ok := c.conn.(driver.ConnBeginTx); ok {
+		return ct.BeginTx(ctx, opts)
+	}
+	return c.conn.Begin() //nolint:staticcheck // fallback for pre-context drivers
+}
+
+func (c *wrappedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	q, ok := c.conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	rows, err := q.QueryContext(ctx, query, args)
+	if err != nil {
+		recordQuery(trace.FromContext(ctx), query, args, -1, time.Since(start), err)
+		return nil, err
+	}
+	return wrapRows(ctx, rows, query, args, start), nil
+}
+
+func (c *wrappedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	e, ok := c.conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	res, err := e.ExecContext(ctx, query, args)
+	recordExec(trace.FromContext(ctx), query, args, res, time.Since(start), err)
+	return res, err
+}
+
+type wrappedStmt struct {
+	stmt  driver.Stmt
+	query string
+}
+
+func (s *wrappedStmt) Close() error  { return s.stmt.Close() }
+func (s *wrappedStmt) NumInput() int { return s.stmt.NumInput() }
+
+func (s *wrappedStmt) Exec(args []driver.Value) (driver.Result, error) {
+	start := time.Now()
+	res, err := s.stmt.Exec(args) //nolint:staticcheck // fallback for pre-context drivers
+	recordExec(nil, s.query, namedValues(args), res, time.Since(start), err)
+	return res, err
+}
+
+func (s *wrappedStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.stmt.Query(args) //nolint:staticcheck // fallback for pre-context drivers
+}
+
+func (s *wrappedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	res, err := execStmtContext(ctx, s.stmt, args)
+	recordExec(trace.FromContext(ctx), s.query, args, res, time.Since(start), err)
+	return res, err
+}
+
+func (s *wrappedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	rows, err := queryStmtContext(ctx, s.stmt, args)
+	if err != nil {
+		recordQuery(trace.FromContext(ctx), s.query, args, -1, time.Since(start), err)
+		return nil, err
+	}
+	return wrapRows(ctx, rows, s.query, args, start), nil
+}
+
+func execStmtContext(ctx context.Context, stmt driver.Stmt, args []driver.NamedValue) (driver.Result, error) {
+	if sc, ok := stmt.(driver.StmtExecContext); ok {
+		return sc.ExecContext(ctx, args)
+	}
+	vals, err := plainValues(args)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.Exec(vals) //nolint:staticcheck // fallback for pre-context drivers
+}
+
+func queryStmtContext(ctx context.Context, stmt driver.Stmt, args []driver.NamedValue) (driver.Rows, error) {
+	if sc, ok := stmt.(driver.StmtQueryContext); ok {
+		return sc.QueryContext(ctx, args)
+	}
+	vals, err := plainValues(args)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.Query(vals) //nolint:staticcheck // fallback for pre-context drivers
+}
+
+// wrappedRows counts rows as they are consumed; the step is recorded when the
+// result set is closed, so the row count and duration cover the full fetch.
+type wrappedRows struct {
+	driver.Rows
+	tr       *trace.Trace
+	query    string
+	args     []driver.NamedValue
+	start    time.Time
+	count    int64
+	recorded bool
+}
+
+func wrapRows(ctx context.Context, rows driver.Rows, query string, args []driver.NamedValue, start time.Time) driver.Rows {
+	return &wrappedRows{Rows: rows, tr: trace.FromContext(ctx), query: query, args: args, start: start}
+}
+
+func (r *wrappedRows) Next(dest []driver.Value) error {
+	err := r.Rows.Next(dest)
+	if err == nil {
+		r.count++
+	}
+	return err
+}
+
+func (r *wrappedRows) Close() error {
+	err := r.Rows.Close()
+	if !r.recorded {
+		r.recorded = true
+		recordQuery(r.tr, r.query, r.args, r.count, time.Since(r.start), nil)
+	}
+	return err
+}
+
+func recordQuery(tr *trace.Trace, query string, args []driver.NamedValue, rows int64, d time.Duration, err error) {
+	if tr == nil {
+		return
+	}
+	outputs := map[string]any{}
+	if rows >= 0 {
+		outputs["rows"] = rows
+	}
+	tr.AddStep("sql.query", queryInputs(query, args), outputs,
+		trace.WithDuration(d), trace.WithStepError(err))
+}
+
+func recordExec(tr *trace.Trace, query string, args []driver.NamedValue, res driver.Result, d time.Duration, err error) {
+	if tr == nil {
+		return
+	}
+	outputs := map[string]any{}
+	if res != nil {
+		if n, raErr := res.RowsAffected(); raErr == nil {
+			outputs["rows_affected"] = n
+		}
+	}
+	tr.AddStep("sql.exec", queryInputs(query, args), outputs,
+		trace.WithDuration(d), trace.WithStepError(err))
+}
+
+func queryInputs(query string, args []driver.NamedValue) map[string]any {
+	inputs := map[string]any{"query": NormalizeSQL(query)}
+	if len(args) > 0 {
+		hashes := make([]string, len(args))
+		for i, a := range args {
+			hashes[i] = trace.HashValue(a.Value)
+		}
+		inputs["param_hashes"] = hashes
+	}
+	return inputs
+}
+
+// NormalizeSQL collapses runs of whitespace to single spaces and trims the
+// statement, so the same query recorded from differently formatted call sites
+// hashes identically.
+func NormalizeSQL(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return named
+}
+
+func plainValues(args []driver.NamedValue) ([]driver.Value, error) {
+	vals := make([]driver.Value, len(args))
+	for i, a := range args {
+		if a.Name != "" {
+			return nil, errors.New("tracesql: driver does not support named parameters")
+		}
+		vals[i] = a.Value
+	}
+	return vals, nil
+}
@@ -0,0 +1,162 @@
+package tracesql
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+// fakeDriver is a minimal in-memory driver: queries return two rows,
+// execs report one affected row. The connection implements the Context
+// variants; the prepared statements deliberately do not, to exercise the
+// fallback path.
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) { return &fakeStmt{}, nil }
+func (c *fakeConn) Close() error                              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)                 { return nil, nil }
+
+func (c *fakeConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return &fakeRows{n: 2}, nil
+}
+
+func (c *fakeConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return fakeResult{}, nil
+}
+
+type fakeStmt struct{}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) { return fakeResult{}, nil }
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error)  { return &fakeRows{n: 2}, nil }
+
+type fakeRows struct{ n int }
+
+func (r *fakeRows) Columns() []string { return []string{"price"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.n == 0 {
+		return io.EOF
+	}
+	r.n--
+	dest[0] = int64(42)
+	return nil
+}
+
+type fakeResult struct{}
+
+func (fakeResult) LastInsertId() (int64, error) { return 0, nil }
+func (fakeResult) RowsAffected() (int64, error) { return 1, nil }
+
+func open(t *testing.T) driver.Conn {
+	t.Helper()
+	conn, err := WrapDriver(fakeDriver{}).Open("dsn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+func drain(t *testing.T, rows driver.Rows) {
+	t.Helper()
+	dest := make([]driver.Value, 1)
+	for {
+		if err := rows.Next(dest); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rows.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestQueryRecordsStep(t *testing.T) {
+	tr := trace.New("report", nil)
+	ctx := trace.NewContext(context.Background(), tr)
+	args := []driver.NamedValue{{Ordinal: 1, Value: int64(7)}}
+	rows, err := open(t).(driver.QueryerContext).QueryContext(ctx, "SELECT price\n  FROM quotes\n  WHERE id = ?", args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	drain(t, rows)
+
+	if len(tr.Steps) != 1 {
+		t.Fatalf("steps %+v", tr.Steps)
+	}
+	st := tr.Steps[0]
+	if st.Operation != "sql.query" || st.Inputs["query"] != "SELECT price FROM quotes WHERE id = ?" {
+		t.Fatalf("step %+v", st)
+	}
+	if st.Outputs["rows"] != int64(2) {
+		t.Fatalf("rows %v", st.Outputs["rows"])
+	}
+	hashes, ok := st.Inputs["param_hashes"].([]string)
+	if !ok || len(hashes) != 1 || hashes[0] != trace.HashValue(int64(7)) {
+		t.Fatalf("param hashes %v", st.Inputs["param_hashes"])
+	}
+}
+
+func TestExecRecordsStep(t *testing.T) {
+	tr := trace.New("report", nil)
+	ctx := trace.NewContext(context.Background(), tr)
+	if _, err := open(t).(driver.ExecerContext).ExecContext(ctx, "UPDATE quotes SET price = 1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(tr.Steps) != 1 || tr.Steps[0].Operation != "sql.exec" {
+		t.Fatalf("steps %+v", tr.Steps)
+	}
+	if tr.Steps[0].Outputs["rows_affected"] != int64(1) {
+		t.Fatalf("outputs %v", tr.Steps[0].Outputs)
+	}
+}
+
+func TestPreparedStatementFallback(t *testing.T) {
+	tr := trace.New("report", nil)
+	ctx := trace.NewContext(context.Background(), tr)
+	stmt, err := open(t).Prepare("SELECT price FROM quotes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The fake statement has no context interfaces; the wrapper falls
+	// back to the pre-context path and still records.
+	rows, err := stmt.(driver.StmtQueryContext).QueryContext(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	drain(t, rows)
+	if len(tr.Steps) != 1 || tr.Steps[0].Operation != "sql.query" {
+		t.Fatalf("steps %+v", tr.Steps)
+	}
+	// Named parameters cannot be lowered to the fallback path.
+	named := []driver.NamedValue{{Name: "id", Ordinal: 1, Value: int64(7)}}
+	if _, err := stmt.(driver.StmtExecContext).ExecContext(ctx, named); err == nil {
+		t.Fatal("lowered named parameters to a positional-only driver")
+	}
+}
+
+func TestUntracedContextRecordsNothing(t *testing.T) {
+	rows, err := open(t).(driver.QueryerContext).QueryContext(context.Background(), "SELECT 1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	drain(t, rows)
+}
+
+func TestNormalizeSQL(t *testing.T) {
+	got := NormalizeSQL("  SELECT *\n\t FROM t\n WHERE x = 1  ")
+	if got != "SELECT * FROM t WHERE x = 1" {
+		t.Fatalf("normalized %q", got)
+	}
+}
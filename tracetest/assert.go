@@ -0,0 +1,105 @@
+package tracetest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Assertion chains checks against a finished trace; each failed check is
+// reported through t.Errorf with the trace identity, and checks keep
+// running so one test failure shows everything that is wrong.
+type Assertion struct {
+	tb testing.TB
+	tr *trace.Trace
+}
+
+// Assert starts a chain of checks against tr:
+//
+//	tracetest.Assert(t, tr).Completed().ResultEquals(90).HasStep("multiply")
+func Assert(tb testing.TB, tr *trace.Trace) *Assertion {
+	tb.Helper()
+	return &Assertion{tb: tb, tr: tr}
+}
+
+// Completed asserts the trace finished in the completed state.
+func (a *Assertion) Completed() *Assertion {
+	a.tb.Helper()
+	if a.tr.Status != trace.StatusCompleted {
+		a.tb.Errorf("trace %s: status = %q, want %q (error: %s)",
+			a.tr.ID, a.tr.Status, trace.StatusCompleted, a.tr.Error)
+	}
+	return a
+}
+
+// Failed asserts the trace finished in the failed state.
+func (a *Assertion) Failed() *Assertion {
+	a.tb.Helper()
+	if a.tr.Status != trace.StatusFailed {
+		a.tb.Errorf("trace %s: status = %q, want %q", a.tr.ID, a.tr.Status, trace.StatusFailed)
+	}
+	return a
+}
+
+// ResultEquals asserts the trace result deep-equals want.
+func (a *Assertion) ResultEquals(want any) *Assertion {
+	a.tb.Helper()
+	if !reflect.DeepEqual(a.tr.Result, want) {
+		a.tb.Errorf("trace %s: result = %v (%T), want %v (%T)",
+			a.tr.ID, a.tr.Result, a.tr.Result, want, want)
+	}
+	return a
+}
+
+// HasStep asserts at least one step recorded the given operation.
+func (a *Assertion) HasStep(operation string) *Assertion {
+	a.tb.Helper()
+	for _, st := range a.tr.Steps {
+		if st.Operation == operation {
+			return a
+		}
+	}
+	a.tb.Errorf("trace %s: no step with operation %q among %s",
+		a.tr.ID, operation, operations(a.tr))
+	return a
+}
+
+// StepCount asserts the trace recorded exactly n steps.
+func (a *Assertion) StepCount(n int) *Assertion {
+	a.tb.Helper()
+	if len(a.tr.Steps) != n {
+		a.tb.Errorf("trace %s: %d steps, want %d: %s",
+			a.tr.ID, len(a.tr.Steps), n, operations(a.tr))
+	}
+	return a
+}
+
+// NoFailedAssertions asserts every in-trace assertion (Trace.Assert)
+// passed.
+func (a *Assertion) NoFailedAssertions() *Assertion {
+	a.tb.Helper()
+	for _, st := range a.tr.FailedAssertions() {
+		a.tb.Errorf("trace %s: assertion %v failed: %s",
+			a.tr.ID, st.Inputs["name"], st.Description)
+	}
+	return a
+}
+
+// Verified asserts the trace's hash chain and structure check out.
+func (a *Assertion) Verified() *Assertion {
+	a.tb.Helper()
+	if err := a.tr.Verify(); err != nil {
+		a.tb.Errorf("trace %s: verify: %v", a.tr.ID, err)
+	}
+	return a
+}
+
+func operations(tr *trace.Trace) string {
+	ops := make([]string, len(tr.Steps))
+	for i, st := range tr.Steps {
+		ops[i] = st.Operation
+	}
+	return "[" + strings.Join(ops, " ") + "]"
+}
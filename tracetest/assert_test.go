@@ -0,0 +1,82 @@
+package tracetest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+// recordingTB captures assertion failures instead of failing the real
+// test, so the failing half of each check is testable.
+type recordingTB struct {
+	testing.TB
+	errors []string
+	fatal  string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTB) Fatalf(format string, args ...any) {
+	r.fatal = fmt.Sprintf(format, args...)
+}
+
+func completedTrace() *trace.Trace {
+	tr := trace.New("calc", map[string]any{"x": 6, "y": 15})
+	tr.AddStep("multiply", map[string]any{"x": 6, "y": 15}, map[string]any{"product": 90})
+	tr.Assert("product positive", 90 > 0, "product must be positive")
+	tr.Complete(90)
+	return tr
+}
+
+func TestAssertPassingChain(t *testing.T) {
+	tr := completedTrace()
+	Assert(t, tr).
+		Completed().
+		ResultEquals(90).
+		HasStep("multiply").
+		StepCount(2).
+		NoFailedAssertions().
+		Verified()
+}
+
+func TestAssertReportsEveryFailure(t *testing.T) {
+	tr := trace.New("calc", nil)
+	tr.AddStep("divide", nil, nil)
+	tr.Assert("never", false, "always fails")
+	tr.Fail(errors.New("boom"))
+
+	rec := &recordingTB{TB: t}
+	Assert(rec, tr).
+		Completed().
+		ResultEquals(90).
+		HasStep("multiply").
+		StepCount(7).
+		NoFailedAssertions()
+	if len(rec.errors) != 5 {
+		t.Fatalf("%d failures reported:\n%s", len(rec.errors), strings.Join(rec.errors, "\n"))
+	}
+	for _, msg := range rec.errors {
+		if !strings.Contains(msg, tr.ID) {
+			t.Fatalf("failure does not name the trace: %s", msg)
+		}
+	}
+}
+
+func TestAssertFailedMatchesFailedTraces(t *testing.T) {
+	tr := trace.New("calc", nil)
+	tr.Fail(errors.New("boom"))
+	Assert(t, tr).Failed()
+
+	rec := &recordingTB{TB: t}
+	Assert(rec, completedTrace()).Failed()
+	if len(rec.errors) != 1 {
+		t.Fatalf("Failed on a completed trace reported %d errors", len(rec.errors))
+	}
+}
@@ -0,0 +1,76 @@
+package tracetest
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// SeedKey is the input key under which generated input maps record the
+// seed that produced them.
+const SeedKey = "gen.seed"
+
+// InputGen builds reproducible random input maps for documentation
+// examples, benchmarks, and determinism tests. Values are drawn in
+// method-call order from a seeded source, so the same seed and the same
+// chain always produce the same map:
+//
+//	inputs := tracetest.Inputs(42).Int("a", 1, 100).Float("rate", 0, 1).Map()
+type InputGen struct {
+	seed   int64
+	rng    *rand.Rand
+	values map[string]any
+}
+
+// Inputs starts a generator drawing from the given seed.
+func Inputs(seed int64) *InputGen {
+	return &InputGen{
+		seed:   seed,
+		rng:    rand.New(rand.NewSource(seed)),
+		values: map[string]any{},
+	}
+}
+
+// Int draws an integer in [min, max].
+func (g *InputGen) Int(name string, min, max int64) *InputGen {
+	g.values[name] = min + g.rng.Int63n(max-min+1)
+	return g
+}
+
+// Float draws a float in [min, max).
+func (g *InputGen) Float(name string, min, max float64) *InputGen {
+	g.values[name] = min + g.rng.Float64()*(max-min)
+	return g
+}
+
+// Bool draws a coin flip.
+func (g *InputGen) Bool(name string) *InputGen {
+	g.values[name] = g.rng.Int63n(2) == 1
+	return g
+}
+
+// Choice draws one of the given options.
+func (g *InputGen) Choice(name string, options ...any) *InputGen {
+	g.values[name] = options[g.rng.Int63n(int64(len(options)))]
+	return g
+}
+
+// String draws a lowercase string of length n.
+func (g *InputGen) String(name string, n int) *InputGen {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte('a' + g.rng.Int63n(26))
+	}
+	g.values[name] = string(b)
+	return g
+}
+
+// Map returns the generated inputs with the seed recorded under SeedKey,
+// so any trace built from them carries its own reproduction recipe.
+func (g *InputGen) Map() map[string]any {
+	out := make(map[string]any, len(g.values)+1)
+	for k, v := range g.values {
+		out[k] = v
+	}
+	out[SeedKey] = fmt.Sprintf("%d", g.seed)
+	return out
+}
@@ -0,0 +1,46 @@
+package tracetest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInputsAreReproduciblePerSeed(t *testing.T) {
+	gen := func(seed int64) map[string]any {
+		return Inputs(seed).
+			Int("a", 1, 100).
+			Float("rate", 0, 1).
+			Bool("flag").
+			Choice("mode", "fast", "slow").
+			String("name", 8).
+			Map()
+	}
+	first, second := gen(42), gen(42)
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("same seed diverged:\n%v\n%v", first, second)
+	}
+	if reflect.DeepEqual(first, gen(43)) {
+		t.Fatal("different seeds produced identical inputs")
+	}
+	if first[SeedKey] != "42" {
+		t.Fatalf("seed not recorded: %v", first)
+	}
+}
+
+func TestInputsRespectRanges(t *testing.T) {
+	for seed := int64(0); seed < 50; seed++ {
+		m := Inputs(seed).Int("a", 1, 3).Float("f", 2, 4).Choice("c", "x", "y").String("s", 4).Map()
+		if a := m["a"].(int64); a < 1 || a > 3 {
+			t.Fatalf("seed %d: int %d out of [1,3]", seed, a)
+		}
+		if f := m["f"].(float64); f < 2 || f >= 4 {
+			t.Fatalf("seed %d: float %g out of [2,4)", seed, f)
+		}
+		if c := m["c"].(string); c != "x" && c != "y" {
+			t.Fatalf("seed %d: choice %q", seed, c)
+		}
+		if s := m["s"].(string); len(s) != 4 {
+			t.Fatalf("seed %d: string %q", seed, s)
+		}
+	}
+}
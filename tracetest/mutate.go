@@ -0,0 +1,111 @@
+package tracetest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Mutation names one deliberate corruption applied to a trace copy during
+// mutation testing of the verification layer.
+type Mutation struct {
+	Name      string
+	StepIndex int
+}
+
+func (m Mutation) String() string {
+	return fmt.Sprintf("%s@step%d", m.Name, m.StepIndex)
+}
+
+// SurvivingMutations applies a catalog of corruptions — altered step
+// inputs and outputs, renamed operations, dropped and swapped steps,
+// cleared errors — each to a fresh copy of the trace, and returns the
+// mutations that Verify failed to detect. An empty result means the
+// verification layer caught every mutation; any survivor is a blind spot.
+// Dropping the last step is only detectable on terminal traces, whose
+// FinalHash seals the end of the chain; a still-recording trace has no
+// seal yet, so run mutation tests on completed traces.
+func SurvivingMutations(tr *trace.Trace) ([]Mutation, error) {
+	if err := tr.Verify(); err != nil {
+		return nil, fmt.Errorf("tracetest: trace does not verify before mutation: %w", err)
+	}
+	base, err := snapshotCopy(tr)
+	if err != nil {
+		return nil, fmt.Errorf("tracetest: copy trace: %w", err)
+	}
+	if err := base.Verify(); err != nil {
+		return nil, fmt.Errorf("tracetest: trace copy does not verify before mutation: %w", err)
+	}
+
+	var survivors []Mutation
+	try := func(m Mutation, corrupt func(*trace.Trace)) error {
+		mutant, err := snapshotCopy(tr)
+		if err != nil {
+			return err
+		}
+		corrupt(mutant)
+		if mutant.Verify() == nil {
+			survivors = append(survivors, m)
+		}
+		return nil
+	}
+
+	for i, st := range base.Steps {
+		i := i
+		for key := range st.Outputs {
+			key := key
+			if err := try(Mutation{"alter-output:" + key, i}, func(mt *trace.Trace) {
+				mt.Steps[i].Outputs[key] = "mutated"
+			}); err != nil {
+				return nil, err
+			}
+		}
+		for key := range st.Inputs {
+			key := key
+			if err := try(Mutation{"alter-input:" + key, i}, func(mt *trace.Trace) {
+				mt.Steps[i].Inputs[key] = "mutated"
+			}); err != nil {
+				return nil, err
+			}
+		}
+		if err := try(Mutation{"rename-operation", i}, func(mt *trace.Trace) {
+			mt.Steps[i].Operation += ".mutated"
+		}); err != nil {
+			return nil, err
+		}
+		if st.Err != "" {
+			if err := try(Mutation{"clear-error", i}, func(mt *trace.Trace) {
+				mt.Steps[i].Err = ""
+			}); err != nil {
+				return nil, err
+			}
+		}
+		if err := try(Mutation{"drop-step", i}, func(mt *trace.Trace) {
+			mt.Steps = append(mt.Steps[:i], mt.Steps[i+1:]...)
+		}); err != nil {
+			return nil, err
+		}
+		if i+1 < len(base.Steps) {
+			if err := try(Mutation{"swap-steps", i}, func(mt *trace.Trace) {
+				mt.Steps[i], mt.Steps[i+1] = mt.Steps[i+1], mt.Steps[i]
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return survivors, nil
+}
+
+// VerifyCatchesMutations fails the test with every mutation the
+// verification layer missed.
+func VerifyCatchesMutations(tb testing.TB, tr *trace.Trace) {
+	tb.Helper()
+	survivors, err := SurvivingMutations(tr)
+	if err != nil {
+		tb.Fatalf("%v", err)
+	}
+	for _, m := range survivors {
+		tb.Errorf("trace %s: mutation %s not caught by Verify", tr.ID, m)
+	}
+}
@@ -0,0 +1,50 @@
+package tracetest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+func TestVerifyCatchesEveryMutation(t *testing.T) {
+	tr := trace.New("calc", map[string]any{"x": 2})
+	tr.AddStep("double", map[string]any{"x": 2}, map[string]any{"y": 4})
+	tr.AddStep("check", map[string]any{"y": 4}, nil, trace.WithRefs(0))
+	tr.AddStep("fetch", nil, nil, trace.WithStepError(errors.New("timeout")))
+	tr.Complete(4)
+	VerifyCatchesMutations(t, tr)
+}
+
+func TestSurvivingMutationsEmptyOnSealedTrace(t *testing.T) {
+	tr := trace.New("t", nil)
+	for i := 0; i < 3; i++ {
+		tr.AddStep("iter", map[string]any{"i": i}, map[string]any{"o": i * i})
+	}
+	tr.Complete(nil)
+	survivors, err := SurvivingMutations(tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(survivors) != 0 {
+		t.Fatalf("mutations survived: %v", survivors)
+	}
+}
+
+func TestSurvivingMutationsRejectsBrokenInput(t *testing.T) {
+	tr := trace.New("t", nil)
+	tr.AddStep("op", nil, map[string]any{"v": 1})
+	tr.Complete(nil)
+	data, err := tr.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	broken, err := trace.FromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	broken.Steps[0].Outputs["v"] = 2
+	if _, err := SurvivingMutations(broken); err == nil {
+		t.Fatal("mutated a trace that does not verify to begin with")
+	}
+}
@@ -0,0 +1,111 @@
+package tracetest
+
+import (
+	"github.com/euclidtrace/trace"
+)
+
+// Runner executes the computation under test for one input map and
+// returns the trace it produced.
+type Runner func(inputs map[string]any) *trace.Trace
+
+// Property checks a trace and reports whether the property holds.
+type Property func(tr *trace.Trace) bool
+
+// shrinkBudget caps the number of candidate runs so shrinking terminates
+// even when the computation is not perfectly deterministic.
+const shrinkBudget = 1000
+
+// Shrink minimizes a failing input map: given inputs for which the
+// property fails, it greedily drops keys and moves numeric and string
+// values toward zero while the property keeps failing, and returns the
+// smallest failing inputs with their reproducer trace. If the property
+// holds for the initial inputs there is nothing to shrink and Shrink
+// returns nil, nil.
+func Shrink(inputs map[string]any, run Runner, prop Property) (map[string]any, *trace.Trace) {
+	current := copyInputs(inputs)
+	tr := run(current)
+	if prop(tr) {
+		return nil, nil
+	}
+	budget := shrinkBudget
+	for {
+		improved := false
+		for _, cand := range candidates(current) {
+			if budget == 0 {
+				return current, tr
+			}
+			budget--
+			candTr := run(cand)
+			if !prop(candTr) {
+				current, tr = cand, candTr
+				improved = true
+				break
+			}
+		}
+		if !improved {
+			return current, tr
+		}
+	}
+}
+
+// candidates proposes strictly simpler variants of the input map: each
+// key dropped, and each value stepped toward its zero. The seed key is
+// kept so shrunk reproducers stay reproducible.
+func candidates(inputs map[string]any) []map[string]any {
+	var out []map[string]any
+	for _, k := range sortedKeys(inputs) {
+		if k == SeedKey {
+			continue
+		}
+		dropped := copyInputs(inputs)
+		delete(dropped, k)
+		out = append(out, dropped)
+		for _, v := range simplerValues(inputs[k]) {
+			cand := copyInputs(inputs)
+			cand[k] = v
+			out = append(out, cand)
+		}
+	}
+	return out
+}
+
+// simplerValues steps one value toward its zero: zero itself first, then
+// the halfway point, so shrinking converges in few runs.
+func simplerValues(v any) []any {
+	switch n := v.(type) {
+	case int:
+		if n == 0 {
+			return nil
+		}
+		return []any{0, n / 2}
+	case int64:
+		if n == 0 {
+			return nil
+		}
+		return []any{int64(0), n / 2}
+	case float64:
+		if n == 0 {
+			return nil
+		}
+		return []any{float64(0), n / 2}
+	case bool:
+		if !n {
+			return nil
+		}
+		return []any{false}
+	case string:
+		if n == "" {
+			return nil
+		}
+		return []any{"", n[:len(n)/2]}
+	}
+	return nil
+}
+
+func copyInputs(inputs map[string]any) map[string]any {
+	out := make(map[string]any, len(inputs))
+	for k, v := range inputs {
+		out[k] = v
+	}
+	return out
+}
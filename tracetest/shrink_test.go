@@ -0,0 +1,52 @@
+package tracetest
+
+import (
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+func TestShrinkMinimizesFailingInputs(t *testing.T) {
+	run := func(inputs map[string]any) *trace.Trace {
+		n, _ := inputs["n"].(int)
+		tr := trace.New("calc", inputs)
+		tr.AddStep("square", map[string]any{"n": n}, map[string]any{"sq": n * n})
+		tr.Complete(n * n)
+		return tr
+	}
+	// The property fails for n >= 10: the overflow bug under test.
+	prop := func(tr *trace.Trace) bool {
+		sq, _ := tr.Result.(int)
+		return sq < 100
+	}
+
+	inputs := map[string]any{"n": 96, "unrelated": "payload", SeedKey: "7"}
+	shrunk, reproducer := Shrink(inputs, run, prop)
+	if shrunk == nil {
+		t.Fatal("property fails for the initial inputs; Shrink returned nothing")
+	}
+	if prop(reproducer) {
+		t.Fatal("shrunk reproducer does not fail the property")
+	}
+	n := shrunk["n"].(int)
+	if n >= 96 || n < 10 {
+		t.Fatalf("shrunk n = %d, want a smaller still-failing value", n)
+	}
+	if _, ok := shrunk["unrelated"]; ok {
+		t.Fatalf("irrelevant input survived shrinking: %v", shrunk)
+	}
+	if shrunk[SeedKey] != "7" {
+		t.Fatalf("seed key dropped: %v", shrunk)
+	}
+}
+
+func TestShrinkReturnsNilWhenPropertyHolds(t *testing.T) {
+	run := func(inputs map[string]any) *trace.Trace {
+		tr := trace.New("t", inputs)
+		tr.Complete(nil)
+		return tr
+	}
+	if shrunk, _ := Shrink(map[string]any{"n": 1}, run, func(*trace.Trace) bool { return true }); shrunk != nil {
+		t.Fatalf("nothing to shrink, got %v", shrunk)
+	}
+}
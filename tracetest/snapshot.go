@@ -0,0 +1,130 @@
+package tracetest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/euclidtrace/trace"
+)
+
+var update = flag.Bool("update", false, "rewrite tracetest golden files")
+
+// Normalizer rewrites a trace copy before snapshot comparison, stripping
+// fields that legitimately vary between runs.
+type Normalizer func(*trace.Trace)
+
+// NormalizeTimestamps zeroes trace and step timestamps and durations.
+func NormalizeTimestamps(tr *trace.Trace) {
+	tr.Started = time.Time{}
+	tr.Finished = time.Time{}
+	for _, st := range tr.Steps {
+		st.Time = time.Time{}
+		st.Duration = 0
+	}
+	for _, sub := range tr.SubTraces {
+		NormalizeTimestamps(sub)
+	}
+}
+
+// NormalizeIDs replaces the trace ID with a fixed placeholder.
+func NormalizeIDs(tr *trace.Trace) {
+	tr.ID = "trace"
+	for _, sub := range tr.SubTraces {
+		NormalizeIDs(sub)
+	}
+}
+
+// NormalizeBuildMetadata drops the env.* and build.* metadata keys that
+// track the recording host.
+func NormalizeBuildMetadata(tr *trace.Trace) {
+	for k := range tr.Metadata {
+		if strings.HasPrefix(k, "env.") || strings.HasPrefix(k, "build.") {
+			delete(tr.Metadata, k)
+		}
+	}
+	for _, sub := range tr.SubTraces {
+		NormalizeBuildMetadata(sub)
+	}
+}
+
+// MatchSnapshot compares the normalized trace against the golden file
+// testdata/<test-name>.golden.json. Run the test with -update to write or
+// rewrite the golden; otherwise a mismatch fails the test with a line diff
+// of the canonical JSON.
+func MatchSnapshot(tb testing.TB, tr *trace.Trace, normalizers ...Normalizer) {
+	tb.Helper()
+	copyTr, err := snapshotCopy(tr)
+	if err != nil {
+		tb.Fatalf("tracetest: copy trace: %v", err)
+	}
+	for _, n := range normalizers {
+		n(copyTr)
+	}
+	got, err := copyTr.ToJSON()
+	if err != nil {
+		tb.Fatalf("tracetest: marshal trace: %v", err)
+	}
+	got = append(got, '\n')
+
+	path := filepath.Join("testdata", artifactName(tb.Name())+".golden.json")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			tb.Fatalf("tracetest: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			tb.Fatalf("tracetest: write golden: %v", err)
+		}
+		tb.Logf("tracetest: wrote %s", path)
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		tb.Fatalf("tracetest: no golden file %s (re-run with -update to create it): %v", path, err)
+	}
+	if string(got) != string(want) {
+		tb.Errorf("trace snapshot differs from %s (re-run with -update to accept):\n%s",
+			path, lineDiff(string(want), string(got)))
+	}
+}
+
+// snapshotCopy deep-copies the trace through its canonical JSON form so
+// normalizers never touch the caller's trace.
+func snapshotCopy(tr *trace.Trace) (*trace.Trace, error) {
+	data, err := tr.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+	return trace.FromJSON(data)
+}
+
+// lineDiff renders a minimal line-oriented diff: shared lines elided,
+// golden lines prefixed "-", new lines prefixed "+".
+func lineDiff(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	var b strings.Builder
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w == g {
+			continue
+		}
+		if w != "" || i < len(wantLines) {
+			fmt.Fprintf(&b, "-%s\n", w)
+		}
+		if g != "" || i < len(gotLines) {
+			fmt.Fprintf(&b, "+%s\n", g)
+		}
+	}
+	return b.String()
+}
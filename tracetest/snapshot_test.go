@@ -0,0 +1,54 @@
+package tracetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/euclidtrace/trace"
+)
+
+func TestMatchSnapshot(t *testing.T) {
+	tr := trace.New("tax", map[string]any{"gross": 100.0, "rate": 0.1})
+	tr.AddStep("multiply", map[string]any{"gross": 100.0, "rate": 0.1}, map[string]any{"tax": 10.0})
+	tr.AddStep("subtract", map[string]any{"gross": 100.0, "tax": 10.0}, map[string]any{"net": 90.0},
+		trace.WithRefs(0))
+	tr.Complete(90.0)
+	MatchSnapshot(t, tr, NormalizeTimestamps, NormalizeIDs, NormalizeBuildMetadata)
+}
+
+func TestNormalizersStripVolatileFields(t *testing.T) {
+	sub := trace.New("inner", nil)
+	sub.Complete(nil)
+	tr := trace.New("outer", nil, trace.WithEnvironmentCapture())
+	tr.AddStep("op", nil, nil, trace.WithDuration(time.Millisecond))
+	tr.AddSubTrace("inner", sub)
+	tr.Complete(nil)
+
+	cp, err := snapshotCopy(tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	NormalizeTimestamps(cp)
+	NormalizeIDs(cp)
+	NormalizeBuildMetadata(cp)
+	if !cp.Started.IsZero() || !cp.Steps[0].Time.IsZero() || cp.Steps[0].Duration != 0 {
+		t.Fatal("timestamps survived normalization")
+	}
+	if cp.ID != "trace" {
+		t.Fatalf("ID %q", cp.ID)
+	}
+	for k := range cp.Metadata {
+		if k == "env.go_version" {
+			t.Fatal("env metadata survived normalization")
+		}
+	}
+	for _, inner := range cp.SubTraces {
+		if inner.ID != "trace" || !inner.Started.IsZero() {
+			t.Fatal("normalizers did not recurse into sub-traces")
+		}
+	}
+	// The caller's trace is untouched.
+	if tr.ID == "trace" || tr.Started.IsZero() {
+		t.Fatal("normalizers mutated the original")
+	}
+}
@@ -0,0 +1,98 @@
+// Package tracetest integrates traces with the testing package: traces
+// created through it dump themselves when the test fails, so flaky numeric
+// failures arrive with their full derivation attached.
+package tracetest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+// ArtifactsEnv names the environment variable that, when set to a
+// directory, receives a JSON copy of every failing test's trace.
+const ArtifactsEnv = "TRACETEST_ARTIFACTS"
+
+// New begins a trace for the test and registers a cleanup that, if the
+// test fails, logs the trace as readable text and JSON and — when
+// ArtifactsEnv points at a directory — writes the JSON there as
+// <test-name>.json.
+func New(tb testing.TB, name string, inputs map[string]any, opts ...trace.Option) *trace.Trace {
+	tb.Helper()
+	tr := trace.New(name, inputs, opts...)
+	tb.Cleanup(func() {
+		if !tb.Failed() {
+			return
+		}
+		tb.Logf("trace %s (%s):\n%s", tr.ID, tr.Name, render(tr))
+		data, err := tr.ToJSON()
+		if err != nil {
+			tb.Logf("tracetest: marshal trace: %v", err)
+			return
+		}
+		tb.Logf("trace JSON:\n%s", data)
+		if dir := os.Getenv(ArtifactsEnv); dir != "" {
+			path := filepath.Join(dir, artifactName(tb.Name())+".json")
+			if err := os.WriteFile(path, data, 0o644); err != nil {
+				tb.Logf("tracetest: write artifact: %v", err)
+			} else {
+				tb.Logf("trace artifact: %s", path)
+			}
+		}
+	})
+	return tr
+}
+
+// render lays the trace out as indented text for the test log.
+func render(tr *trace.Trace) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "  status: %s\n", tr.Status)
+	for _, k := range sortedKeys(tr.Inputs) {
+		fmt.Fprintf(&b, "  input %s = %v\n", k, tr.Inputs[k])
+	}
+	for _, st := range tr.Steps {
+		fmt.Fprintf(&b, "  %3d. %s", st.Index, st.Operation)
+		if d := trace.Describe(st); d != "" {
+			fmt.Fprintf(&b, " — %s", d)
+		}
+		b.WriteString("\n")
+		for _, k := range sortedKeys(st.Inputs) {
+			fmt.Fprintf(&b, "       in  %s = %v\n", k, st.Inputs[k])
+		}
+		for _, k := range sortedKeys(st.Outputs) {
+			fmt.Fprintf(&b, "       out %s = %v\n", k, st.Outputs[k])
+		}
+		if st.Err != "" {
+			fmt.Fprintf(&b, "       error: %s\n", st.Err)
+		}
+		for _, n := range st.Notes {
+			fmt.Fprintf(&b, "       note [%s]: %s\n", n.Audience, n.Text)
+		}
+	}
+	if tr.Error != "" {
+		fmt.Fprintf(&b, "  error: %s\n", tr.Error)
+	} else {
+		fmt.Fprintf(&b, "  result: %v\n", tr.Result)
+	}
+	return b.String()
+}
+
+// artifactName flattens a test name (which may contain subtest slashes)
+// into a file name.
+func artifactName(name string) string {
+	return strings.NewReplacer("/", "_", " ", "_").Replace(name)
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,42 @@
+package tracetest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+func TestNewRecordsLikeTraceNew(t *testing.T) {
+	tr := New(t, "calc", map[string]any{"x": 1})
+	tr.AddStep("op", map[string]any{"x": 1}, map[string]any{"y": 2})
+	tr.Complete(2)
+	Assert(t, tr).Completed().ResultEquals(2).Verified()
+}
+
+func TestRenderLaysOutTheDerivation(t *testing.T) {
+	tr := trace.New("calc", map[string]any{"x": 1})
+	tr.AddStep("double", map[string]any{"x": 1}, map[string]any{"y": 2}).
+		WithNote("support", "doubled per contract")
+	tr.Complete(2)
+	out := render(tr)
+	for _, want := range []string{"input x = 1", "0. double", "out y = 2", "note [support]", "result: 2"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("render missing %q:\n%s", want, out)
+		}
+	}
+	failed := trace.New("calc", nil)
+	failed.AddStep("op", nil, nil, trace.WithStepError(errors.New("boom")))
+	failed.Fail(errors.New("boom"))
+	out = render(failed)
+	if !strings.Contains(out, "error: boom") {
+		t.Fatalf("render hides the failure:\n%s", out)
+	}
+}
+
+func TestArtifactName(t *testing.T) {
+	if got := artifactName("TestX/sub case"); got != "TestX_sub_case" {
+		t.Fatalf("artifact name %q", got)
+	}
+}
@@ -0,0 +1,106 @@
+// Package uncertain provides measurement values carrying a mean and a
+// standard uncertainty, with traced operations that propagate the
+// uncertainty through arithmetic and record the propagation formula used,
+// so measurement-based computations state not just their result but how
+// sure it is and why.
+package uncertain
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Value is a measurement: a mean and its standard uncertainty. Propagation
+// follows the standard first-order formulas and treats operands as
+// uncorrelated.
+type Value struct {
+	Mean float64 `json:"mean"`
+	U    float64 `json:"u"`
+}
+
+// New returns a value with the given mean and standard uncertainty.
+func New(mean, u float64) Value {
+	return Value{Mean: mean, U: math.Abs(u)}
+}
+
+// Exact returns a value with zero uncertainty.
+func Exact(mean float64) Value {
+	return Value{Mean: mean}
+}
+
+func (v Value) String() string {
+	return fmt.Sprintf("%g ± %g", v.Mean, v.U)
+}
+
+// Relative returns the relative uncertainty u/|mean|.
+func (v Value) Relative() float64 {
+	if v.Mean == 0 {
+		return math.Inf(1)
+	}
+	return v.U / math.Abs(v.Mean)
+}
+
+const (
+	formulaAdditive = "u = sqrt(u_a^2 + u_b^2)"
+	formulaProduct  = "u = sqrt((b*u_a)^2 + (a*u_b)^2)"
+	formulaQuotient = "u = sqrt((u_a/b)^2 + (a*u_b/b^2)^2)"
+)
+
+// Add records a + b, propagating uncertainty in quadrature.
+func Add(tr *trace.Trace, a, b Value) Value {
+	out := Value{Mean: a.Mean + b.Mean, U: math.Hypot(a.U, b.U)}
+	record(tr, "uncertain.add", formulaAdditive, a, b, out)
+	return out
+}
+
+// Sub records a - b; the uncertainties still add in quadrature.
+func Sub(tr *trace.Trace, a, b Value) Value {
+	out := Value{Mean: a.Mean - b.Mean, U: math.Hypot(a.U, b.U)}
+	record(tr, "uncertain.sub", formulaAdditive, a, b, out)
+	return out
+}
+
+// Mul records a * b. The first-order propagation is written in its
+// absolute form, sqrt((b*u_a)^2 + (a*u_b)^2), which equals the familiar
+// relative-quadrature formula away from zero but stays finite when a
+// mean is zero, where |result| * relative would evaluate 0 * Inf.
+func Mul(tr *trace.Trace, a, b Value) Value {
+	out := Value{
+		Mean: a.Mean * b.Mean,
+		U:    math.Hypot(b.Mean*a.U, a.Mean*b.U),
+	}
+	record(tr, "uncertain.mul", formulaProduct, a, b, out)
+	return out
+}
+
+// Div records a / b, propagating in the absolute first-order form like
+// Mul, so a zero numerator yields a finite uncertainty. Division by an
+// exact zero records an error step.
+func Div(tr *trace.Trace, a, b Value) (Value, error) {
+	if b.Mean == 0 {
+		err := fmt.Errorf("uncertain: division by %s", b)
+		tr.AddStep("uncertain.div",
+			map[string]any{"a": a, "b": b}, nil,
+			trace.WithStepError(err))
+		return Value{}, err
+	}
+	out := Value{
+		Mean: a.Mean / b.Mean,
+		U:    math.Hypot(a.U/b.Mean, a.Mean*b.U/(b.Mean*b.Mean)),
+	}
+	record(tr, "uncertain.div", formulaQuotient, a, b, out)
+	return out, nil
+}
+
+func record(tr *trace.Trace, op, formula string, a, b, out Value) {
+	outputs := map[string]any{"result": out}
+	// A zero mean has no finite relative uncertainty; omit the reading
+	// rather than record an Inf that no JSON form can carry.
+	if rel := out.Relative(); !math.IsInf(rel, 0) && !math.IsNaN(rel) {
+		outputs["relative_u"] = rel
+	}
+	tr.AddStep(op, map[string]any{"a": a, "b": b}, outputs,
+		trace.WithDescription(formula))
+}
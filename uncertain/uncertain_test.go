@@ -0,0 +1,97 @@
+package uncertain
+
+import (
+	"math"
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+func close(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-12*math.Max(math.Abs(a), math.Abs(b))
+}
+
+func TestAddSubPropagateInQuadrature(t *testing.T) {
+	tr := trace.New("t", nil)
+	sum := Add(tr, New(10, 0.3), New(20, 0.4))
+	if sum.Mean != 30 || !close(sum.U, 0.5) {
+		t.Fatalf("sum %v", sum)
+	}
+	diff := Sub(tr, New(10, 0.3), New(20, 0.4))
+	if diff.Mean != -10 || !close(diff.U, 0.5) {
+		t.Fatalf("diff %v", diff)
+	}
+	if len(tr.Steps) != 2 || tr.Steps[0].Description != formulaAdditive {
+		t.Fatalf("steps %+v", tr.Steps)
+	}
+}
+
+func TestMulDivPropagateAbsoluteFirstOrder(t *testing.T) {
+	tr := trace.New("t", nil)
+	p := Mul(tr, New(2, 0.1), New(3, 0.2))
+	if p.Mean != 6 || !close(p.U, math.Hypot(3*0.1, 2*0.2)) {
+		t.Fatalf("product %v", p)
+	}
+	q, err := Div(tr, New(6, 0.3), New(2, 0.1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Mean != 3 || !close(q.U, math.Hypot(0.3/2, 6*0.1/4)) {
+		t.Fatalf("quotient %v", q)
+	}
+	if tr.Steps[0].Description != formulaProduct || tr.Steps[1].Description != formulaQuotient {
+		t.Fatalf("formulas %q %q", tr.Steps[0].Description, tr.Steps[1].Description)
+	}
+}
+
+func TestZeroMeanOperandsStayFinite(t *testing.T) {
+	tr := trace.New("t", nil)
+	p := Mul(tr, New(0, 0.1), New(3, 0.2))
+	if p.Mean != 0 || !close(p.U, 0.3) {
+		t.Fatalf("0 ± 0.1 times 3 ± 0.2 = %v", p)
+	}
+	if math.IsNaN(p.U) || math.IsInf(p.U, 0) {
+		t.Fatalf("uncertainty not finite: %v", p)
+	}
+	q, err := Div(tr, New(0, 0.1), New(2, 0.2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Mean != 0 || !close(q.U, 0.05) {
+		t.Fatalf("0 ± 0.1 over 2 ± 0.2 = %v", q)
+	}
+	// The zero-mean results have no finite relative uncertainty; the
+	// reading is omitted rather than recorded as Inf.
+	for _, st := range tr.Steps {
+		if _, ok := st.Outputs["relative_u"]; ok {
+			t.Fatalf("relative_u recorded for a zero mean: %+v", st.Outputs)
+		}
+	}
+	tr.Complete(nil)
+	if err := tr.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDivByExactZeroRecordsError(t *testing.T) {
+	tr := trace.New("t", nil)
+	if _, err := Div(tr, New(1, 0.1), Exact(0)); err == nil {
+		t.Fatal("divided by an exact zero")
+	}
+	if tr.Steps[0].Err == "" {
+		t.Fatal("error step not recorded")
+	}
+}
+
+func TestRelativeAndString(t *testing.T) {
+	v := New(10, 0.5)
+	if v.Relative() != 0.05 || v.String() != "10 ± 0.5" {
+		t.Fatalf("%v %q", v.Relative(), v.String())
+	}
+	if !math.IsInf(Exact(0).Relative(), 1) {
+		t.Fatal("zero mean should report infinite relative uncertainty")
+	}
+	if New(1, -0.5).U != 0.5 {
+		t.Fatal("negative uncertainty not normalized")
+	}
+}
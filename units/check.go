@@ -0,0 +1,164 @@
+package units
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Violation is one dimensionally inconsistent arithmetic step.
+type Violation struct {
+	StepIndex int
+	Operation string
+	Message   string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("step %d (%s): %s", v.StepIndex, v.Operation, v.Message)
+}
+
+// additiveOps and multiplicativeOps classify arithmetic operations by the
+// final name segment, so "price.add" and "add" both check as additions.
+var (
+	additiveOps       = map[string]bool{"add": true, "sum": true, "subtract": true, "sub": true, "plus": true, "minus": true}
+	multiplicativeOps = map[string]bool{"multiply": true, "mul": true, "times": true, "divide": true, "div": true}
+	divisionOps       = map[string]bool{"divide": true, "div": true}
+)
+
+// Check validates every arithmetic step whose values carry units:
+// additions and subtractions require all quantity operands (and the
+// quantity output, if any) to share one dimension; multiplications and
+// divisions require the output dimension to equal the product or quotient
+// of the input dimensions. Steps without quantities, and operations
+// outside the arithmetic vocabulary, are skipped.
+func Check(tr *trace.Trace) []Violation {
+	var out []Violation
+	for _, st := range tr.Steps {
+		op := st.Operation
+		if i := strings.LastIndexByte(op, '.'); i >= 0 {
+			op = op[i+1:]
+		}
+		switch {
+		case additiveOps[op]:
+			out = append(out, checkAdditive(st)...)
+		case multiplicativeOps[op]:
+			out = append(out, checkMultiplicative(st, divisionOps[op])...)
+		}
+	}
+	return out
+}
+
+// Validate runs Check and records each violation as a failed assertion on
+// the still-recording trace, so dimensional errors become explicit trace
+// content subject to compliance rules.
+func Validate(tr *trace.Trace) []Violation {
+	violations := Check(tr)
+	for _, v := range violations {
+		tr.Assert(fmt.Sprintf("units.step%d", v.StepIndex), false, v.String())
+	}
+	return violations
+}
+
+func checkAdditive(st *trace.Step) []Violation {
+	var first *Dim
+	var firstKey string
+	var out []Violation
+	check := func(key string, v any) {
+		dim, ok := valueDim(v)
+		if !ok {
+			return
+		}
+		if first == nil {
+			first, firstKey = &dim, key
+			return
+		}
+		if !dim.Equal(*first) {
+			out = append(out, Violation{
+				StepIndex: st.Index,
+				Operation: st.Operation,
+				Message:   fmt.Sprintf("cannot add %s [%s] to %s [%s]", key, dim, firstKey, *first),
+			})
+		}
+	}
+	for _, k := range sortedKeys(st.Inputs) {
+		check(k, st.Inputs[k])
+	}
+	for _, k := range sortedKeys(st.Outputs) {
+		check(k, st.Outputs[k])
+	}
+	return out
+}
+
+func checkMultiplicative(st *trace.Step, division bool) []Violation {
+	var dims []Dim
+	for _, k := range sortedKeys(st.Inputs) {
+		if dim, ok := valueDim(st.Inputs[k]); ok {
+			dims = append(dims, dim)
+		}
+	}
+	if len(dims) == 0 {
+		return nil
+	}
+	want := dims[0]
+	for _, dim := range dims[1:] {
+		if division {
+			want = want.Div(dim)
+		} else {
+			want = want.Mul(dim)
+		}
+	}
+	var out []Violation
+	for _, k := range sortedKeys(st.Outputs) {
+		dim, ok := valueDim(st.Outputs[k])
+		if !ok {
+			continue
+		}
+		if !dim.Equal(want) {
+			out = append(out, Violation{
+				StepIndex: st.Index,
+				Operation: st.Operation,
+				Message:   fmt.Sprintf("output %s has dimension [%s], inputs derive [%s]", k, dim, want),
+			})
+		}
+	}
+	return out
+}
+
+// valueDim recovers the dimension of a recorded value: a Quantity
+// directly, or its map form after a JSON round trip. Unparseable units
+// count as dimensionless rather than erroring mid-check.
+func valueDim(v any) (Dim, bool) {
+	switch q := v.(type) {
+	case Quantity:
+		dim, err := ParseUnit(q.Unit)
+		if err != nil {
+			return nil, false
+		}
+		return dim, true
+	case map[string]any:
+		unit, ok := q["unit"].(string)
+		if !ok {
+			return nil, false
+		}
+		if _, ok := q["value"]; !ok {
+			return nil, false
+		}
+		dim, err := ParseUnit(unit)
+		if err != nil {
+			return nil, false
+		}
+		return dim, true
+	}
+	return nil, false
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
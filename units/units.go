@@ -0,0 +1,151 @@
+// Package units adds dimensional analysis to traced arithmetic. Values
+// recorded as Quantity carry a unit expression ("kg", "m/s", "kg*m/s^2"),
+// and Check validates that every arithmetic step is dimensionally
+// consistent — adding kg to USD is a violation, multiplying m by m must
+// yield m². Units are treated symbolically: kg and g are distinct symbols,
+// since the goal is catching category errors, not unit conversion.
+package units
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Quantity is a numeric value with its unit, recorded as a step input or
+// output so checkers and readers see what kind of number it is.
+type Quantity struct {
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit"`
+}
+
+// Q is shorthand for building a Quantity.
+func Q(value float64, unit string) Quantity {
+	return Quantity{Value: value, Unit: unit}
+}
+
+func (q Quantity) String() string {
+	return fmt.Sprintf("%g %s", q.Value, q.Unit)
+}
+
+// Dim is a dimension: unit symbols mapped to their exponents. m/s is
+// {m: 1, s: -1}; a dimensionless value is the empty Dim.
+type Dim map[string]int
+
+// ParseUnit parses a unit expression — symbols combined with *, /, and
+// integer exponents after ^ — into its dimension.
+func ParseUnit(expr string) (Dim, error) {
+	dim := Dim{}
+	if strings.TrimSpace(expr) == "" {
+		return dim, nil
+	}
+	// The solidus divides everything after it, matching the conventional
+	// reading of kg*m/s^2.
+	sign := 1
+	for _, part := range splitUnits(expr) {
+		if part == "*" {
+			continue
+		}
+		if part == "/" {
+			sign = -1
+			continue
+		}
+		symbol, exp := part, 1
+		if i := strings.IndexByte(part, '^'); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil {
+				return nil, fmt.Errorf("units: bad exponent in %q", expr)
+			}
+			symbol, exp = part[:i], n
+		}
+		if symbol == "" {
+			return nil, fmt.Errorf("units: bad unit expression %q", expr)
+		}
+		dim[symbol] += sign * exp
+		if dim[symbol] == 0 {
+			delete(dim, symbol)
+		}
+	}
+	return dim, nil
+}
+
+// splitUnits tokenizes a unit expression into symbols and operators.
+func splitUnits(expr string) []string {
+	var out []string
+	cur := strings.Builder{}
+	for _, r := range expr {
+		switch r {
+		case '*', '/':
+			if cur.Len() > 0 {
+				out = append(out, cur.String())
+				cur.Reset()
+			}
+			out = append(out, string(r))
+		case ' ':
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if cur.Len() > 0 {
+		out = append(out, cur.String())
+	}
+	return out
+}
+
+// Mul returns the dimension of a product.
+func (d Dim) Mul(other Dim) Dim {
+	out := Dim{}
+	for k, v := range d {
+		out[k] = v
+	}
+	for k, v := range other {
+		out[k] += v
+		if out[k] == 0 {
+			delete(out, k)
+		}
+	}
+	return out
+}
+
+// Div returns the dimension of a quotient.
+func (d Dim) Div(other Dim) Dim {
+	inv := Dim{}
+	for k, v := range other {
+		inv[k] = -v
+	}
+	return d.Mul(inv)
+}
+
+// Equal reports whether two dimensions are the same.
+func (d Dim) Equal(other Dim) bool {
+	if len(d) != len(other) {
+		return false
+	}
+	for k, v := range d {
+		if other[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (d Dim) String() string {
+	if len(d) == 0 {
+		return "1"
+	}
+	keys := make([]string, 0, len(d))
+	for k := range d {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if d[k] == 1 {
+			parts = append(parts, k)
+		} else {
+			parts = append(parts, fmt.Sprintf("%s^%d", k, d[k]))
+		}
+	}
+	return strings.Join(parts, "*")
+}
@@ -0,0 +1,119 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+func mustParse(t *testing.T, expr string) Dim {
+	t.Helper()
+	dim, err := ParseUnit(expr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dim
+}
+
+func TestParseUnit(t *testing.T) {
+	cases := []struct {
+		expr string
+		want Dim
+	}{
+		{"", Dim{}},
+		{"kg", Dim{"kg": 1}},
+		{"m/s", Dim{"m": 1, "s": -1}},
+		{"kg*m/s^2", Dim{"kg": 1, "m": 1, "s": -2}},
+		{"m^2", Dim{"m": 2}},
+		{"m/m", Dim{}},
+	}
+	for _, c := range cases {
+		if got := mustParse(t, c.expr); !got.Equal(c.want) {
+			t.Fatalf("ParseUnit(%q) = %v, want %v", c.expr, got, c.want)
+		}
+	}
+	for _, bad := range []string{"m^x", "^2"} {
+		if _, err := ParseUnit(bad); err == nil {
+			t.Fatalf("ParseUnit(%q) accepted", bad)
+		}
+	}
+}
+
+func TestDimAlgebra(t *testing.T) {
+	m := mustParse(t, "m")
+	s := mustParse(t, "s")
+	if got := m.Div(s); !got.Equal(Dim{"m": 1, "s": -1}) {
+		t.Fatalf("m/s = %v", got)
+	}
+	if got := m.Mul(m); !got.Equal(Dim{"m": 2}) || got.String() != "m^2" {
+		t.Fatalf("m*m = %v (%s)", got, got)
+	}
+	if got := m.Div(m); !got.Equal(Dim{}) || got.String() != "1" {
+		t.Fatalf("m/m = %v (%s)", got, got)
+	}
+}
+
+func TestCheckAdditive(t *testing.T) {
+	tr := trace.New("t", nil)
+	tr.AddStep("price.add",
+		map[string]any{"a": Q(1, "USD"), "b": Q(2, "kg")},
+		map[string]any{"sum": Q(3, "USD")})
+	violations := Check(tr)
+	if len(violations) != 1 {
+		t.Fatalf("violations %v", violations)
+	}
+	if violations[0].StepIndex != 0 || violations[0].Operation != "price.add" {
+		t.Fatalf("violation %+v", violations[0])
+	}
+}
+
+func TestCheckMultiplicative(t *testing.T) {
+	tr := trace.New("t", nil)
+	tr.AddStep("mul",
+		map[string]any{"f": Q(2, "kg"), "a": Q(3, "m/s^2")},
+		map[string]any{"force": Q(6, "kg*m/s^2")})
+	tr.AddStep("div",
+		map[string]any{"d": Q(10, "m"), "t": Q(2, "s")},
+		map[string]any{"v": Q(5, "kg")})
+	violations := Check(tr)
+	if len(violations) != 1 || violations[0].StepIndex != 1 {
+		t.Fatalf("violations %v", violations)
+	}
+}
+
+func TestCheckSkipsNonArithmeticAndUnitless(t *testing.T) {
+	tr := trace.New("t", nil)
+	tr.AddStep("lookup", map[string]any{"a": Q(1, "kg")}, map[string]any{"b": Q(2, "m")})
+	tr.AddStep("add", map[string]any{"a": 1.0, "b": 2.0}, map[string]any{"sum": 3.0})
+	if violations := Check(tr); len(violations) != 0 {
+		t.Fatalf("violations %v", violations)
+	}
+}
+
+func TestCheckSurvivesJSONRoundTrip(t *testing.T) {
+	tr := trace.New("t", nil)
+	tr.AddStep("add", map[string]any{"a": Q(1, "kg"), "b": Q(2, "USD")}, nil)
+	tr.Complete(nil)
+	data, err := tr.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := trace.FromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if violations := Check(back); len(violations) != 1 {
+		t.Fatalf("violations after round trip: %v", violations)
+	}
+}
+
+func TestValidateRecordsFailedAssertions(t *testing.T) {
+	tr := trace.New("t", nil)
+	tr.AddStep("add", map[string]any{"a": Q(1, "kg"), "b": Q(2, "USD")}, nil)
+	if violations := Validate(tr); len(violations) != 1 {
+		t.Fatalf("violations %v", violations)
+	}
+	if failed := tr.FailedAssertions(); len(failed) != 1 {
+		t.Fatalf("failed assertions %v", failed)
+	}
+}
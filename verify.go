@@ -0,0 +1,43 @@
+package trace
+
+import "fmt"
+
+// Verify checks the trace's internal consistency: identity fields, a valid
+// terminal status, sequential step indexes, the step hash chain, and — on
+// terminal traces — the FinalHash seal, so trailing steps truncated after
+// completion are detected too. A trace that verifies has not been
+// reordered or edited since it was recorded.
+func (tr *Trace) Verify() error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if tr.ID == "" {
+		return fmt.Errorf("trace: empty ID")
+	}
+	if tr.Name == "" {
+		return fmt.Errorf("trace %s: empty name", tr.ID)
+	}
+	switch tr.Status {
+	case StatusRecording, StatusCompleted, StatusFailed, StatusAborted:
+	default:
+		return fmt.Errorf("trace %s: unknown status %q", tr.ID, tr.Status)
+	}
+	prev := ""
+	for i, st := range tr.Steps {
+		if st.Index != i {
+			return fmt.Errorf("trace %s: step at position %d carries index %d", tr.ID, i, st.Index)
+		}
+		if got := st.chainHash(prev); got != st.Hash {
+			return fmt.Errorf("trace %s: step %d hash chain broken: recorded %s, recomputed %s", tr.ID, i, st.Hash, got)
+		}
+		for _, ref := range st.Refs {
+			if ref < 0 || ref >= i {
+				return fmt.Errorf("trace %s: step %d references step %d, which is not an earlier step", tr.ID, i, ref)
+			}
+		}
+		prev = st.Hash
+	}
+	if tr.Status != StatusRecording && tr.FinalHash != "" && prev != tr.FinalHash {
+		return fmt.Errorf("trace %s: chain ends at %q, terminal seal recorded %s: trailing steps truncated", tr.ID, prev, tr.FinalHash)
+	}
+	return tr.verifyCatalogLocked()
+}
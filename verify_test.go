@@ -0,0 +1,80 @@
+package trace
+
+import "testing"
+
+// tamper round-trips the trace through JSON so mutations never touch the
+// original, applies corrupt, and reports whether Verify caught it.
+func tamper(t *testing.T, tr *Trace, corrupt func(*Trace)) error {
+	t.Helper()
+	data, err := tr.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cp, err := FromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	corrupt(cp)
+	return cp.Verify()
+}
+
+func buildVerifiable(t *testing.T) *Trace {
+	t.Helper()
+	tr := New("v", map[string]any{"x": 2})
+	tr.AddStep("square", map[string]any{"x": 2}, map[string]any{"y": 4})
+	tr.AddStep("add", map[string]any{"y": 4}, map[string]any{"z": 5}, WithRefs(0))
+	tr.AddStep("emit", map[string]any{"z": 5}, nil)
+	tr.Complete(5)
+	if err := tr.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	return tr
+}
+
+func TestVerifyCatchesEditedValues(t *testing.T) {
+	tr := buildVerifiable(t)
+	if err := tamper(t, tr, func(cp *Trace) { cp.Steps[1].Outputs["z"] = 99 }); err == nil {
+		t.Error("edited output not caught")
+	}
+	if err := tamper(t, tr, func(cp *Trace) { cp.Steps[0].Operation = "cube" }); err == nil {
+		t.Error("renamed operation not caught")
+	}
+	if err := tamper(t, tr, func(cp *Trace) {
+		cp.Steps[0], cp.Steps[1] = cp.Steps[1], cp.Steps[0]
+	}); err == nil {
+		t.Error("swapped steps not caught")
+	}
+	if err := tamper(t, tr, func(cp *Trace) { cp.Steps = cp.Steps[1:] }); err == nil {
+		t.Error("dropped leading step not caught")
+	}
+}
+
+func TestVerifyCatchesTailTruncation(t *testing.T) {
+	tr := buildVerifiable(t)
+	if tr.FinalHash != tr.Steps[len(tr.Steps)-1].Hash {
+		t.Fatalf("seal %q does not name the last step", tr.FinalHash)
+	}
+	if err := tamper(t, tr, func(cp *Trace) { cp.Steps = cp.Steps[:2] }); err == nil {
+		t.Error("truncated tail not caught")
+	}
+	if err := tamper(t, tr, func(cp *Trace) { cp.Steps = nil }); err == nil {
+		t.Error("all steps dropped without Verify noticing")
+	}
+	// Traces recorded before the seal existed carry none and still verify.
+	if err := tamper(t, tr, func(cp *Trace) { cp.FinalHash = "" }); err != nil {
+		t.Errorf("legacy trace without seal rejected: %v", err)
+	}
+}
+
+func TestVerifyRejectsMalformedTraces(t *testing.T) {
+	tr := buildVerifiable(t)
+	if err := tamper(t, tr, func(cp *Trace) { cp.ID = "" }); err == nil {
+		t.Error("empty ID accepted")
+	}
+	if err := tamper(t, tr, func(cp *Trace) { cp.Status = "done" }); err == nil {
+		t.Error("unknown status accepted")
+	}
+	if err := tamper(t, tr, func(cp *Trace) { cp.Steps[2].Refs = []int{5} }); err == nil {
+		t.Error("forward reference accepted")
+	}
+}
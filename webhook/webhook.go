@@ -0,0 +1,193 @@
+// Package webhook posts compact completion events to configured HTTP
+// endpoints when traces complete or fail, so downstream workflow
+// systems can react to finished computations without polling a store.
+// The event carries identity, outcome, and the content hash — enough to
+// decide what to do and to fetch or verify the full trace elsewhere —
+// never the trace body itself.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/euclidtrace/trace"
+)
+
+// Event is the payload POSTed to each endpoint.
+type Event struct {
+	TraceID string       `json:"trace_id"`
+	Name    string       `json:"name"`
+	Status  trace.Status `json:"status"`
+	// Result is a compact rendering of the final result; ContentHash,
+	// not Result, is the authoritative record of the outcome.
+	Result string `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// ContentHash lets the receiver verify any copy of the full trace
+	// it later obtains against what this event announced.
+	ContentHash string    `json:"content_hash"`
+	Tenant      string    `json:"tenant,omitempty"`
+	Finished    time.Time `json:"finished"`
+}
+
+// SignatureHeader carries the hex HMAC-SHA256 of the request body when
+// the notifier is configured with a secret, so receivers can check that
+// events really came from this deployment.
+const SignatureHeader = "X-Trace-Signature"
+
+// Notifier delivers completion events to a fixed set of webhook
+// endpoints. Deliveries run asynchronously so finishing a trace never
+// blocks on a slow receiver; Flush waits for in-flight deliveries at
+// shutdown.
+type Notifier struct {
+	endpoints []string
+	client    *http.Client
+	secret    []byte
+
+	wg sync.WaitGroup
+
+	mu        sync.Mutex
+	delivered uint64
+	failed    uint64
+}
+
+// Option configures a Notifier.
+type Option func(*Notifier)
+
+// WithClient sets the HTTP client used for deliveries; the default has
+// a ten-second timeout.
+func WithClient(c *http.Client) Option {
+	return func(n *Notifier) { n.client = c }
+}
+
+// WithSecret signs each delivery's body with HMAC-SHA256 under the
+// given key, sent in SignatureHeader.
+func WithSecret(key []byte) Option {
+	return func(n *Notifier) { n.secret = key }
+}
+
+// NewNotifier returns a notifier posting to the given endpoint URLs.
+func NewNotifier(endpoints []string, opts ...Option) *Notifier {
+	n := &Notifier{
+		endpoints: endpoints,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	return n
+}
+
+// Option returns a trace option attaching the notifier, so
+//
+//	tr := trace.New(name, inputs, n.Option())
+//
+// posts to every endpoint when the trace completes or fails.
+func (n *Notifier) Option() trace.Option {
+	return trace.OnFinish(n.Notify)
+}
+
+// Notify posts the trace's completion event to every endpoint. Traces
+// that are still recording or were aborted are ignored: an abort is an
+// interruption, not an outcome downstream workflows should act on.
+func (n *Notifier) Notify(tr *trace.Trace) {
+	if tr.Status != trace.StatusCompleted && tr.Status != trace.StatusFailed {
+		return
+	}
+	ev := Event{
+		TraceID:     tr.ID,
+		Name:        tr.Name,
+		Status:      tr.Status,
+		Result:      summarize(tr.Result),
+		Error:       tr.Error,
+		ContentHash: tr.ContentHash(),
+		Tenant:      tr.Tenant(),
+		Finished:    tr.Finished,
+	}
+	body, err := json.Marshal(ev)
+	if err != nil {
+		n.mu.Lock()
+		n.failed += uint64(len(n.endpoints))
+		n.mu.Unlock()
+		return
+	}
+	for _, url := range n.endpoints {
+		n.wg.Add(1)
+		go n.deliver(url, body)
+	}
+}
+
+// Flush blocks until every delivery started so far has finished.
+func (n *Notifier) Flush() {
+	n.wg.Wait()
+}
+
+// NotifierStats is a snapshot of the notifier's counters.
+type NotifierStats struct {
+	// Delivered counts POSTs answered with a 2xx status; Failed counts
+	// everything else, including transport errors.
+	Delivered uint64 `json:"delivered"`
+	Failed    uint64 `json:"failed"`
+}
+
+// Stats returns the notifier's current counters.
+func (n *Notifier) Stats() NotifierStats {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return NotifierStats{Delivered: n.delivered, Failed: n.failed}
+}
+
+// deliver POSTs one event body to one endpoint and counts the outcome.
+func (n *Notifier) deliver(url string, body []byte) {
+	defer n.wg.Done()
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		n.count(false)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(n.secret) > 0 {
+		mac := hmac.New(sha256.New, n.secret)
+		mac.Write(body)
+		req.Header.Set(SignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		n.count(false)
+		return
+	}
+	resp.Body.Close()
+	n.count(resp.StatusCode >= 200 && resp.StatusCode < 300)
+}
+
+func (n *Notifier) count(ok bool) {
+	n.mu.Lock()
+	if ok {
+		n.delivered++
+	} else {
+		n.failed++
+	}
+	n.mu.Unlock()
+}
+
+// summarize renders a result compactly: its JSON form, truncated past
+// 256 bytes so events stay small whatever the computation produced.
+func summarize(v any) string {
+	if v == nil {
+		return ""
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	if len(b) > 256 {
+		return string(b[:253]) + "..."
+	}
+	return string(b)
+}
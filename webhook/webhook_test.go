@@ -0,0 +1,169 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/euclidtrace/trace"
+)
+
+// receiver collects delivered events behind a mutex; deliveries are
+// asynchronous.
+type receiver struct {
+	mu     sync.Mutex
+	bodies [][]byte
+	sigs   []string
+	status int
+}
+
+func (rc *receiver) handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		rc.mu.Lock()
+		rc.bodies = append(rc.bodies, body)
+		rc.sigs = append(rc.sigs, r.Header.Get(SignatureHeader))
+		rc.mu.Unlock()
+		status := rc.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		w.WriteHeader(status)
+	}
+}
+
+func (rc *receiver) events(t *testing.T) []Event {
+	t.Helper()
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	out := make([]Event, len(rc.bodies))
+	for i, b := range rc.bodies {
+		if err := json.Unmarshal(b, &out[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return out
+}
+
+func TestNotifyOnCompletion(t *testing.T) {
+	rc := &receiver{}
+	srv := httptest.NewServer(rc.handler())
+	defer srv.Close()
+	n := NewNotifier([]string{srv.URL})
+
+	tr := trace.New("pricing", map[string]any{"gross": 100.0}, n.Option(), trace.WithTenant("acme"))
+	tr.AddStep("tax", nil, nil)
+	tr.Complete(80.0)
+	n.Flush()
+
+	events := rc.events(t)
+	if len(events) != 1 {
+		t.Fatalf("events %+v", events)
+	}
+	ev := events[0]
+	if ev.TraceID != tr.ID || ev.Status != trace.StatusCompleted || ev.Result != "80" {
+		t.Fatalf("event %+v", ev)
+	}
+	if ev.ContentHash != tr.ContentHash() || ev.Tenant != "acme" {
+		t.Fatalf("event %+v", ev)
+	}
+	if s := n.Stats(); s.Delivered != 1 || s.Failed != 0 {
+		t.Fatalf("stats %+v", s)
+	}
+}
+
+func TestNotifyCarriesFailure(t *testing.T) {
+	rc := &receiver{}
+	srv := httptest.NewServer(rc.handler())
+	defer srv.Close()
+	n := NewNotifier([]string{srv.URL})
+
+	tr := trace.New("pricing", nil, n.Option())
+	tr.Fail(errors.New("bad rate"))
+	n.Flush()
+
+	events := rc.events(t)
+	if len(events) != 1 || events[0].Status != trace.StatusFailed || events[0].Error != "bad rate" {
+		t.Fatalf("events %+v", events)
+	}
+}
+
+func TestNotifySkipsAborts(t *testing.T) {
+	rc := &receiver{}
+	srv := httptest.NewServer(rc.handler())
+	defer srv.Close()
+	n := NewNotifier([]string{srv.URL})
+
+	tr := trace.New("pricing", nil, n.Option())
+	tr.Abort(errors.New("shutting down"))
+	n.Flush()
+	if events := rc.events(t); len(events) != 0 {
+		t.Fatalf("events %+v", events)
+	}
+	// A still-recording trace is not an outcome either.
+	n.Notify(trace.New("open", nil))
+	n.Flush()
+	if events := rc.events(t); len(events) != 0 {
+		t.Fatalf("events %+v", events)
+	}
+}
+
+func TestSignedDeliveries(t *testing.T) {
+	rc := &receiver{}
+	srv := httptest.NewServer(rc.handler())
+	defer srv.Close()
+	secret := []byte("delivery-secret")
+	n := NewNotifier([]string{srv.URL}, WithSecret(secret))
+
+	tr := trace.New("pricing", nil, n.Option())
+	tr.Complete(nil)
+	n.Flush()
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if len(rc.bodies) != 1 {
+		t.Fatalf("deliveries %d", len(rc.bodies))
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(rc.bodies[0])
+	if rc.sigs[0] != hex.EncodeToString(mac.Sum(nil)) {
+		t.Fatalf("signature %q", rc.sigs[0])
+	}
+}
+
+func TestStatsCountFailures(t *testing.T) {
+	rc := &receiver{status: http.StatusBadGateway}
+	srv := httptest.NewServer(rc.handler())
+	defer srv.Close()
+	n := NewNotifier([]string{srv.URL, "http://127.0.0.1:1/unreachable"})
+
+	tr := trace.New("pricing", nil, n.Option())
+	tr.Complete(nil)
+	n.Flush()
+	if s := n.Stats(); s.Delivered != 0 || s.Failed != 2 {
+		t.Fatalf("stats %+v", s)
+	}
+}
+
+func TestSummarizeTruncatesLargeResults(t *testing.T) {
+	rc := &receiver{}
+	srv := httptest.NewServer(rc.handler())
+	defer srv.Close()
+	n := NewNotifier([]string{srv.URL})
+
+	tr := trace.New("pricing", nil, n.Option())
+	tr.Complete(strings.Repeat("x", 1000))
+	n.Flush()
+	events := rc.events(t)
+	if len(events) != 1 || len(events[0].Result) != 256 || !strings.HasSuffix(events[0].Result, "...") {
+		t.Fatalf("result %q", events[0].Result)
+	}
+}
@@ -0,0 +1,134 @@
+package trace
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ResultRef addresses the trace's final result in Why.
+const ResultRef = -1
+
+// Derivation is a minimal ordered explanation of one value: only the steps
+// on the lineage path from the trace inputs to the target, in step order.
+type Derivation struct {
+	// Target is the explained step index, or ResultRef for the final result.
+	Target int
+	// Inputs are the declared trace inputs that the lineage consumed.
+	Inputs []string
+	Steps  []*Step
+}
+
+// Why answers the auditor's question "where did this come from?": it slices
+// the trace backwards from a step (or from the final result with ResultRef)
+// through Refs and value lineage, returning the minimal ordered derivation.
+func (tr *Trace) Why(ref int) (*Derivation, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	target := ref
+	if ref == ResultRef {
+		target = tr.resultStepLocked()
+		if target < 0 {
+			return nil, fmt.Errorf("trace: no step produced the result")
+		}
+	}
+	if target < 0 || target >= len(tr.Steps) {
+		return nil, fmt.Errorf("trace: no step %d (trace has %d steps)", ref, len(tr.Steps))
+	}
+
+	needed := map[int]bool{target: true}
+	for i := target; i >= 0; i-- {
+		if !needed[i] {
+			continue
+		}
+		st := tr.Steps[i]
+		for _, r := range st.Refs {
+			if r >= 0 && r < i {
+				needed[r] = true
+			}
+		}
+		// Value lineage: earlier steps whose outputs match this step's
+		// inputs by hash, for steps recorded without explicit Refs.
+		for _, v := range st.Inputs {
+			hash := HashValue(v)
+			for j := i - 1; j >= 0; j-- {
+				if needed[j] {
+					continue
+				}
+				for _, out := range tr.Steps[j].Outputs {
+					if HashValue(out) == hash {
+						needed[j] = true
+						break
+					}
+				}
+			}
+		}
+	}
+
+	d := &Derivation{Target: ref}
+	indexes := make([]int, 0, len(needed))
+	for i := range needed {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	usedInputs := map[string]bool{}
+	for _, i := range indexes {
+		st := tr.Steps[i]
+		d.Steps = append(d.Steps, st)
+		for k, v := range st.Inputs {
+			for name, in := range tr.Inputs {
+				if k == name || HashValue(in) == HashValue(v) {
+					usedInputs[name] = true
+				}
+			}
+		}
+	}
+	for name := range usedInputs {
+		d.Inputs = append(d.Inputs, name)
+	}
+	sort.Strings(d.Inputs)
+	return d, nil
+}
+
+// resultStepLocked finds the last step whose output matches the final
+// result by value hash.
+func (tr *Trace) resultStepLocked() int {
+	hash := HashValue(tr.Result)
+	for i := len(tr.Steps) - 1; i >= 0; i-- {
+		for _, out := range tr.Steps[i].Outputs {
+			if HashValue(out) == hash {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// Render formats the derivation as a human-readable explanation, one line
+// per lineage step.
+func (d *Derivation) Render() string {
+	var b strings.Builder
+	if d.Target == ResultRef {
+		b.WriteString("derivation of the final result:\n")
+	} else {
+		fmt.Fprintf(&b, "derivation of step %d:\n", d.Target)
+	}
+	if len(d.Inputs) > 0 {
+		fmt.Fprintf(&b, "  from inputs: %s\n", strings.Join(d.Inputs, ", "))
+	}
+	for _, st := range d.Steps {
+		fmt.Fprintf(&b, "  step %d: %s", st.Index, st.Operation)
+		if st.Description != "" {
+			fmt.Fprintf(&b, " — %s", st.Description)
+		}
+		for _, k := range sortedKeys(st.Outputs) {
+			fmt.Fprintf(&b, " [%s = %v]", k, st.Outputs[k])
+		}
+		if st.Err != "" {
+			fmt.Fprintf(&b, " (error: %s)", st.Err)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package trace
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWhySlicesLineageToTheResult(t *testing.T) {
+	tr := New("net", map[string]any{"gross": 100.0, "rate": 0.2})
+	tr.AddStep("tax", map[string]any{"gross": 100.0, "rate": 0.2}, map[string]any{"tax": 20.0})
+	tr.AddStep("unrelated", map[string]any{"k": 1}, map[string]any{"v": 2})
+	tr.AddStep("subtract", map[string]any{"gross": 100.0, "tax": 20.0}, map[string]any{"net": 80.0})
+	tr.Complete(80.0)
+
+	d, err := tr.Why(ResultRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Steps) != 2 || d.Steps[0].Operation != "tax" || d.Steps[1].Operation != "subtract" {
+		t.Fatalf("derivation steps: %+v", d.Steps)
+	}
+	if len(d.Inputs) != 2 || d.Inputs[0] != "gross" || d.Inputs[1] != "rate" {
+		t.Fatalf("derivation inputs %v", d.Inputs)
+	}
+	out := d.Render()
+	if !strings.Contains(out, "derivation of the final result") || strings.Contains(out, "unrelated") {
+		t.Fatalf("render:\n%s", out)
+	}
+}
+
+func TestWhyFollowsExplicitRefs(t *testing.T) {
+	tr := New("t", nil)
+	tr.AddStep("load", nil, nil)
+	tr.AddStep("check", nil, nil, WithRefs(0))
+	d, err := tr.Why(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Steps) != 2 {
+		t.Fatalf("ref lineage missed a step: %+v", d.Steps)
+	}
+}
+
+func TestWhyRejectsMissingTargets(t *testing.T) {
+	tr := New("t", nil)
+	if _, err := tr.Why(3); err == nil {
+		t.Fatal("explained a step that does not exist")
+	}
+	if _, err := tr.Why(ResultRef); err == nil {
+		t.Fatal("explained a result no step produced")
+	}
+}
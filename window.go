@@ -0,0 +1,92 @@
+package trace
+
+import (
+	"fmt"
+	"time"
+)
+
+// Window is a time window of a windowed computation, identified by its
+// half-open bounds [Start, End).
+type Window struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+func (w Window) String() string {
+	return fmt.Sprintf("[%s, %s)", w.Start.Format(time.RFC3339), w.End.Format(time.RFC3339))
+}
+
+// WindowRecorder records the lifecycle of time windows on a trace —
+// opens, watermark advances, inputs, closes, and late-data corrections —
+// as standardized steps, so the trace of a windowed aggregation explains
+// precisely why a window's final value includes or excludes particular
+// inputs.
+type WindowRecorder struct {
+	tr        *Trace
+	watermark time.Time
+}
+
+// NewWindowRecorder binds a recorder to the trace.
+func NewWindowRecorder(tr *Trace) *WindowRecorder {
+	return &WindowRecorder{tr: tr}
+}
+
+// Open records the window opening.
+func (wr *WindowRecorder) Open(w Window) {
+	wr.tr.AddStep("window.open",
+		map[string]any{"window": w.String()}, nil)
+}
+
+// Advance records the watermark advancing; a watermark that does not move
+// forward records nothing.
+func (wr *WindowRecorder) Advance(t time.Time) {
+	if !t.After(wr.watermark) {
+		return
+	}
+	wr.tr.AddStep("window.watermark",
+		map[string]any{"from": wr.watermark.Format(time.RFC3339Nano)},
+		map[string]any{"to": t.Format(time.RFC3339Nano)})
+	wr.watermark = t
+}
+
+// Input records a value arriving for a window, stamped with its event
+// time. An input whose event time is behind the watermark is late and the
+// step says so with a warning; whether it is included is the caller's
+// policy, recorded explicitly.
+func (wr *WindowRecorder) Input(w Window, name string, value any, eventTime time.Time, included bool) {
+	late := eventTime.Before(wr.watermark)
+	opts := []StepOption{}
+	if late {
+		opts = append(opts, Warn(), WithDescription(fmt.Sprintf(
+			"event time %s behind watermark %s",
+			eventTime.Format(time.RFC3339Nano), wr.watermark.Format(time.RFC3339Nano))))
+	}
+	wr.tr.AddStep("window.input",
+		map[string]any{
+			"window": w.String(), "name": name, "value": value,
+			"event_time": eventTime.Format(time.RFC3339Nano),
+		},
+		map[string]any{"late": late, "included": included},
+		opts...)
+}
+
+// Close records the window closing with its final value at the current
+// watermark.
+func (wr *WindowRecorder) Close(w Window, value any) {
+	wr.tr.AddStep("window.close",
+		map[string]any{"window": w.String(), "watermark": wr.watermark.Format(time.RFC3339Nano)},
+		map[string]any{"value": value})
+}
+
+// Correct records a late-data correction to an already-closed window: the
+// triggering input, and the window's value before and after.
+func (wr *WindowRecorder) Correct(w Window, name string, value any, eventTime time.Time, oldValue, newValue any) {
+	wr.tr.AddStep("window.late_correction",
+		map[string]any{
+			"window": w.String(), "name": name, "value": value,
+			"event_time": eventTime.Format(time.RFC3339Nano), "old_value": oldValue,
+		},
+		map[string]any{"new_value": newValue},
+		Warn(),
+		WithDescription(fmt.Sprintf("late data corrected closed window %s", w)))
+}
@@ -0,0 +1,63 @@
+package trace
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowRecorderLifecycle(t *testing.T) {
+	tr := New("agg", nil)
+	wr := NewWindowRecorder(tr)
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	w := Window{Start: start, End: start.Add(time.Hour)}
+
+	wr.Open(w)
+	wr.Advance(start.Add(10 * time.Minute))
+	wr.Input(w, "x", 1.0, start.Add(5*time.Minute), false) // behind the watermark: late
+	wr.Input(w, "y", 2.0, start.Add(20*time.Minute), true)
+	wr.Close(w, 2.0)
+	tr.Complete(2.0)
+
+	ops := make([]string, len(tr.Steps))
+	for i, st := range tr.Steps {
+		ops[i] = st.Operation
+	}
+	want := []string{"window.open", "window.watermark", "window.input", "window.input", "window.close"}
+	for i := range want {
+		if ops[i] != want[i] {
+			t.Fatalf("ops %v", ops)
+		}
+	}
+	late := tr.Steps[2]
+	if late.Outputs["late"] != true || late.Severity != SeverityWarning {
+		t.Fatalf("late input not flagged: %+v", late)
+	}
+	if tr.Steps[3].Outputs["late"] != false {
+		t.Fatalf("on-time input flagged late: %+v", tr.Steps[3].Outputs)
+	}
+	if err := tr.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWindowWatermarkNeverRegresses(t *testing.T) {
+	tr := New("agg", nil)
+	wr := NewWindowRecorder(tr)
+	now := time.Now()
+	wr.Advance(now)
+	wr.Advance(now.Add(-time.Minute))
+	if len(tr.Steps) != 1 {
+		t.Fatalf("regressing watermark recorded a step: %+v", tr.Steps)
+	}
+}
+
+func TestWindowCorrectRecordsOldAndNew(t *testing.T) {
+	tr := New("agg", nil)
+	wr := NewWindowRecorder(tr)
+	w := Window{Start: time.Now(), End: time.Now().Add(time.Hour)}
+	wr.Correct(w, "x", 3.0, time.Now(), 2.0, 5.0)
+	st := tr.Steps[0]
+	if st.Operation != "window.late_correction" || st.Inputs["old_value"] != 2.0 || st.Outputs["new_value"] != 5.0 {
+		t.Fatalf("correction step %+v", st)
+	}
+}